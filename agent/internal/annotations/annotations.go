@@ -0,0 +1,83 @@
+// Package annotations holds Console-driven instance labels applied at
+// runtime via MsgAnnotateInstance. Unlike registration.RegistrationPayload
+// tags, annotations are never persisted to the registration store and
+// expire automatically.
+package annotations
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// DefaultTTL is used when Store.Set is called with a zero ttl.
+const DefaultTTL = 24 * time.Hour
+
+type entry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// Store holds a set of expiring key/value annotations. It is safe for
+// concurrent use.
+type Store struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// NewStore returns an empty Store.
+func NewStore() *Store {
+	return &Store{entries: make(map[string]entry)}
+}
+
+// Set stores value under key until ttl elapses. A zero ttl uses
+// DefaultTTL; a negative ttl stores an already-expired entry, which
+// Snapshot purges on its next call.
+func (s *Store) Set(key, value string, ttl time.Duration) {
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = entry{value: value, expiresAt: time.Now().Add(ttl)}
+}
+
+// Apply merges msg.Annotations into s under ttl, then removes msg.Remove
+// keys.
+func (s *Store) Apply(msg protocol.MsgAnnotateInstance, ttl time.Duration) {
+	for k, v := range msg.Annotations {
+		s.Set(k, v, ttl)
+	}
+	for _, k := range msg.Remove {
+		s.Remove(k)
+	}
+}
+
+// Remove deletes key from the store, if present.
+func (s *Store) Remove(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+}
+
+// Snapshot returns every unexpired annotation as a plain map, purging
+// expired entries as a side effect.
+func (s *Store) Snapshot() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	snapshot := make(map[string]string, len(s.entries))
+	for k, e := range s.entries {
+		if now.After(e.expiresAt) {
+			delete(s.entries, k)
+			continue
+		}
+		snapshot[k] = e.value
+	}
+	if len(snapshot) == 0 {
+		return nil
+	}
+	return snapshot
+}
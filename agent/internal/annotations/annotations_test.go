@@ -0,0 +1,53 @@
+package annotations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestApplyMergesAndRemoves(t *testing.T) {
+	s := NewStore()
+	s.Apply(protocol.MsgAnnotateInstance{Annotations: map[string]string{"maintenance": "true"}}, time.Minute)
+
+	snapshot := s.Snapshot()
+	if snapshot["maintenance"] != "true" {
+		t.Fatalf("Snapshot() = %v, want maintenance=true", snapshot)
+	}
+
+	s.Apply(protocol.MsgAnnotateInstance{Remove: []string{"maintenance"}}, time.Minute)
+	if snapshot := s.Snapshot(); snapshot["maintenance"] != "" {
+		t.Fatalf("Snapshot() = %v, want maintenance removed", snapshot)
+	}
+}
+
+func TestSnapshotOmitsExpiredEntry(t *testing.T) {
+	s := NewStore()
+	s.Set("maintenance", "true", -time.Second)
+
+	if snapshot := s.Snapshot(); len(snapshot) != 0 {
+		t.Fatalf("Snapshot() = %v, want empty after expiry", snapshot)
+	}
+}
+
+func TestSetUsesDefaultTTLWhenZero(t *testing.T) {
+	s := NewStore()
+	s.Set("maintenance", "true", 0)
+
+	s.mu.Lock()
+	expiresAt := s.entries["maintenance"].expiresAt
+	s.mu.Unlock()
+
+	wantMin := time.Now().Add(DefaultTTL - time.Minute)
+	if expiresAt.Before(wantMin) {
+		t.Errorf("expiresAt = %v, want roughly now+%v", expiresAt, DefaultTTL)
+	}
+}
+
+func TestSnapshotReturnsNilWhenEmpty(t *testing.T) {
+	s := NewStore()
+	if snapshot := s.Snapshot(); snapshot != nil {
+		t.Errorf("Snapshot() = %v, want nil", snapshot)
+	}
+}
@@ -0,0 +1,262 @@
+// Package archive creates on-demand tar.gz or zip archives of remote
+// paths on behalf of the Console.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/pacphi/sindri/agent/internal/filesystem"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// ErrArchiveTooLarge is returned when the total uncompressed size of the
+// requested paths exceeds maxBytes.
+var ErrArchiveTooLarge = errors.New("archive: exceeds maximum archive size")
+
+// ProgressSender delivers incremental MsgArchiveProgress updates while an
+// archive is being created.
+type ProgressSender interface {
+	SendArchiveProgress(progress protocol.MsgArchiveProgress) error
+}
+
+// fileEntry is a single file to be added to the archive.
+type fileEntry struct {
+	// absPath is the resolved on-disk location to read from.
+	absPath string
+	// name is the path recorded inside the archive.
+	name string
+	size int64
+}
+
+// Create archives req.Paths into req.Destination (both resolved against
+// root) in req.Format ("tar.gz" or "zip"), reporting progress to sender as
+// each file is written. maxBytes caps the total uncompressed size of the
+// source files; 0 disables the cap.
+func Create(root *filesystem.Root, req protocol.MsgArchiveCreateRequest, maxBytes int64, sender ProgressSender) (protocol.MsgArchiveComplete, error) {
+	files, err := collectFiles(root, req.Paths, maxBytes)
+	if err != nil {
+		return protocol.MsgArchiveComplete{}, err
+	}
+
+	dest, err := root.Resolve(req.Destination)
+	if err != nil {
+		return protocol.MsgArchiveComplete{}, fmt.Errorf("archive: resolve destination: %w", err)
+	}
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return protocol.MsgArchiveComplete{}, fmt.Errorf("archive: create destination: %w", err)
+	}
+
+	hasher := sha256.New()
+	tee := io.MultiWriter(out, hasher)
+	tracker := &progressTracker{requestID: req.RequestID, filesTotal: len(files), sender: sender}
+
+	switch req.Format {
+	case "tar.gz":
+		err = writeTarGz(tee, files, tracker)
+	case "zip":
+		err = writeZip(tee, files, tracker)
+	default:
+		err = fmt.Errorf("archive: unsupported format %q", req.Format)
+	}
+	if err != nil {
+		out.Close()
+		return protocol.MsgArchiveComplete{}, err
+	}
+
+	info, err := out.Stat()
+	if err != nil {
+		out.Close()
+		return protocol.MsgArchiveComplete{}, fmt.Errorf("archive: stat destination: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return protocol.MsgArchiveComplete{}, fmt.Errorf("archive: close destination: %w", err)
+	}
+
+	return protocol.MsgArchiveComplete{
+		RequestID:   req.RequestID,
+		Destination: req.Destination,
+		SHA256:      hex.EncodeToString(hasher.Sum(nil)),
+		SizeBytes:   info.Size(),
+	}, nil
+}
+
+// collectFiles resolves each of paths against root and walks it, returning
+// every regular file found. Directories in paths are walked recursively
+// and their files are named in the archive relative to the directory's own
+// base name, so archiving "/srv/app" produces entries like "app/main.go"
+// rather than absolute paths.
+func collectFiles(root *filesystem.Root, paths []string, maxBytes int64) ([]fileEntry, error) {
+	var files []fileEntry
+	var totalSize int64
+
+	for _, p := range paths {
+		resolved, err := root.Resolve(p)
+		if err != nil {
+			return nil, fmt.Errorf("archive: resolve %q: %w", p, err)
+		}
+
+		base := filepath.Base(filepath.Clean(resolved))
+		walkErr := filepath.WalkDir(resolved, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			totalSize += info.Size()
+			if maxBytes > 0 && totalSize > maxBytes {
+				return ErrArchiveTooLarge
+			}
+
+			rel, err := filepath.Rel(resolved, path)
+			if err != nil {
+				return err
+			}
+			name := base
+			if rel != "." {
+				name = filepath.Join(base, rel)
+			}
+
+			files = append(files, fileEntry{absPath: path, name: filepath.ToSlash(name), size: info.Size()})
+			return nil
+		})
+		if walkErr != nil {
+			return nil, fmt.Errorf("archive: walk %q: %w", p, walkErr)
+		}
+	}
+
+	return files, nil
+}
+
+// progressTracker sends a MsgArchiveProgress update after each file is
+// written to the archive.
+type progressTracker struct {
+	requestID      string
+	filesTotal     int
+	filesProcessed int
+	bytesProcessed int64
+	sender         ProgressSender
+}
+
+func (t *progressTracker) advance(size int64) error {
+	t.filesProcessed++
+	t.bytesProcessed += size
+	if t.sender == nil {
+		return nil
+	}
+	return t.sender.SendArchiveProgress(protocol.MsgArchiveProgress{
+		RequestID:      t.requestID,
+		FilesProcessed: t.filesProcessed,
+		FilesTotal:     t.filesTotal,
+		BytesProcessed: t.bytesProcessed,
+	})
+}
+
+func writeTarGz(w io.Writer, files []fileEntry, tracker *progressTracker) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for _, f := range files {
+		if err := addFileToTar(tw, f); err != nil {
+			tw.Close()
+			gz.Close()
+			return err
+		}
+		if err := tracker.advance(f.size); err != nil {
+			tw.Close()
+			gz.Close()
+			return fmt.Errorf("archive: send progress: %w", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		gz.Close()
+		return fmt.Errorf("archive: close tar writer: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("archive: close gzip writer: %w", err)
+	}
+	return nil
+}
+
+func addFileToTar(tw *tar.Writer, f fileEntry) error {
+	src, err := os.Open(f.absPath)
+	if err != nil {
+		return fmt.Errorf("archive: open %q: %w", f.absPath, err)
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return fmt.Errorf("archive: stat %q: %w", f.absPath, err)
+	}
+
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return fmt.Errorf("archive: build tar header for %q: %w", f.absPath, err)
+	}
+	hdr.Name = f.name
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("archive: write tar header for %q: %w", f.name, err)
+	}
+	if _, err := io.Copy(tw, src); err != nil {
+		return fmt.Errorf("archive: write tar data for %q: %w", f.name, err)
+	}
+	return nil
+}
+
+func writeZip(w io.Writer, files []fileEntry, tracker *progressTracker) error {
+	zw := zip.NewWriter(w)
+
+	for _, f := range files {
+		if err := addFileToZip(zw, f); err != nil {
+			zw.Close()
+			return err
+		}
+		if err := tracker.advance(f.size); err != nil {
+			zw.Close()
+			return fmt.Errorf("archive: send progress: %w", err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("archive: close zip writer: %w", err)
+	}
+	return nil
+}
+
+func addFileToZip(zw *zip.Writer, f fileEntry) error {
+	src, err := os.Open(f.absPath)
+	if err != nil {
+		return fmt.Errorf("archive: open %q: %w", f.absPath, err)
+	}
+	defer src.Close()
+
+	dst, err := zw.Create(f.name)
+	if err != nil {
+		return fmt.Errorf("archive: create zip entry %q: %w", f.name, err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		return fmt.Errorf("archive: write zip data for %q: %w", f.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/filesystem"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+type recordingProgressSender struct {
+	updates []protocol.MsgArchiveProgress
+}
+
+func (r *recordingProgressSender) SendArchiveProgress(p protocol.MsgArchiveProgress) error {
+	r.updates = append(r.updates, p)
+	return nil
+}
+
+func writeTestFiles(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "data"), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "data", "a.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "data", "b.txt"), []byte("world!"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func sha256File(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCreateTarGzMatchesLocalSHA256(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir)
+	root, err := filesystem.NewRoot(dir)
+	if err != nil {
+		t.Fatalf("NewRoot: %v", err)
+	}
+
+	sender := &recordingProgressSender{}
+	resp, err := Create(root, protocol.MsgArchiveCreateRequest{
+		RequestID:   "r1",
+		Paths:       []string{"data"},
+		Format:      "tar.gz",
+		Destination: "out.tar.gz",
+	}, 0, sender)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	wantSHA := sha256File(t, filepath.Join(dir, "out.tar.gz"))
+	if resp.SHA256 != wantSHA {
+		t.Errorf("SHA256 = %q, want %q", resp.SHA256, wantSHA)
+	}
+	if resp.RequestID != "r1" {
+		t.Errorf("RequestID = %q, want r1", resp.RequestID)
+	}
+	if resp.SizeBytes == 0 {
+		t.Error("SizeBytes = 0, want > 0")
+	}
+
+	if len(sender.updates) != 2 {
+		t.Fatalf("got %d progress updates, want 2", len(sender.updates))
+	}
+	last := sender.updates[len(sender.updates)-1]
+	if last.FilesProcessed != 2 || last.FilesTotal != 2 {
+		t.Errorf("final progress = %+v, want FilesProcessed=FilesTotal=2", last)
+	}
+
+	verifyTarGzContents(t, filepath.Join(dir, "out.tar.gz"), map[string]string{
+		"data/a.txt": "hello",
+		"data/b.txt": "world!",
+	})
+}
+
+func verifyTarGzContents(t *testing.T, path string, want map[string]string) {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	defer gz.Close()
+
+	got := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("tar.Next: %v", err)
+		}
+		var buf bytes.Buffer
+		if _, err := io.Copy(&buf, tr); err != nil {
+			t.Fatalf("io.Copy: %v", err)
+		}
+		got[hdr.Name] = buf.String()
+	}
+
+	for name, contents := range want {
+		if got[name] != contents {
+			t.Errorf("tar entry %q = %q, want %q", name, got[name], contents)
+		}
+	}
+}
+
+func TestCreateZipMatchesLocalSHA256(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir)
+	root, err := filesystem.NewRoot(dir)
+	if err != nil {
+		t.Fatalf("NewRoot: %v", err)
+	}
+
+	resp, err := Create(root, protocol.MsgArchiveCreateRequest{
+		RequestID:   "r2",
+		Paths:       []string{"data"},
+		Format:      "zip",
+		Destination: "out.zip",
+	}, 0, nil)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	wantSHA := sha256File(t, filepath.Join(dir, "out.zip"))
+	if resp.SHA256 != wantSHA {
+		t.Errorf("SHA256 = %q, want %q", resp.SHA256, wantSHA)
+	}
+
+	zr, err := zip.OpenReader(filepath.Join(dir, "out.zip"))
+	if err != nil {
+		t.Fatalf("zip.OpenReader: %v", err)
+	}
+	defer zr.Close()
+	if len(zr.File) != 2 {
+		t.Errorf("got %d zip entries, want 2", len(zr.File))
+	}
+}
+
+func TestCreateRejectsPathTraversal(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir)
+	root, err := filesystem.NewRoot(dir)
+	if err != nil {
+		t.Fatalf("NewRoot: %v", err)
+	}
+
+	_, err = Create(root, protocol.MsgArchiveCreateRequest{
+		RequestID:   "r3",
+		Paths:       []string{"../../etc"},
+		Format:      "tar.gz",
+		Destination: "out.tar.gz",
+	}, 0, nil)
+	if !errors.Is(err, filesystem.ErrPathEscapesRoot) {
+		t.Fatalf("Create() error = %v, want ErrPathEscapesRoot", err)
+	}
+}
+
+func TestCreateEnforcesMaxBytes(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir)
+	root, err := filesystem.NewRoot(dir)
+	if err != nil {
+		t.Fatalf("NewRoot: %v", err)
+	}
+
+	_, err = Create(root, protocol.MsgArchiveCreateRequest{
+		RequestID:   "r4",
+		Paths:       []string{"data"},
+		Format:      "tar.gz",
+		Destination: "out.tar.gz",
+	}, 1, nil)
+	if !errors.Is(err, ErrArchiveTooLarge) {
+		t.Fatalf("Create() error = %v, want ErrArchiveTooLarge", err)
+	}
+}
+
+func TestCreateRejectsUnsupportedFormat(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir)
+	root, err := filesystem.NewRoot(dir)
+	if err != nil {
+		t.Fatalf("NewRoot: %v", err)
+	}
+
+	_, err = Create(root, protocol.MsgArchiveCreateRequest{
+		RequestID:   "r5",
+		Paths:       []string{"data"},
+		Format:      "rar",
+		Destination: "out.rar",
+	}, 0, nil)
+	if err == nil {
+		t.Fatal("expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,12 @@
+// Package auth provides pluggable authentication strategies for outbound
+// requests to the Console, so new schemes (OIDC, HMAC, eventually AWS
+// SigV4) can be added without touching transport or registration code.
+package auth
+
+import "net/http"
+
+// Strategy applies an authentication scheme's headers to an outbound
+// request.
+type Strategy interface {
+	ApplyAuth(header *http.Header) error
+}
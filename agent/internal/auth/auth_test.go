@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBearerTokenStrategySetsHeader(t *testing.T) {
+	header := http.Header{}
+	s := BearerTokenStrategy{Token: "abc123"}
+	if err := s.ApplyAuth(&header); err != nil {
+		t.Fatalf("ApplyAuth: %v", err)
+	}
+	if got := header.Get("Authorization"); got != "Bearer abc123" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer abc123")
+	}
+}
+
+func TestOIDCStrategySetsHeaderFromTokenSource(t *testing.T) {
+	header := http.Header{}
+	s := OIDCStrategy{TokenSource: func() (string, error) { return "oidc-token", nil }}
+	if err := s.ApplyAuth(&header); err != nil {
+		t.Fatalf("ApplyAuth: %v", err)
+	}
+	if got := header.Get("Authorization"); got != "Bearer oidc-token" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer oidc-token")
+	}
+}
+
+func TestOIDCStrategyPropagatesTokenSourceError(t *testing.T) {
+	header := http.Header{}
+	s := OIDCStrategy{TokenSource: func() (string, error) { return "", errors.New("refresh failed") }}
+	if err := s.ApplyAuth(&header); err == nil {
+		t.Fatal("expected an error when the token source fails")
+	}
+}
+
+func TestHMACStrategySetsSignatureHeaders(t *testing.T) {
+	fixedNow := time.Unix(1700000000, 0)
+	header := http.Header{}
+	s := HMACStrategy{
+		KeyID:  "key-1",
+		Secret: []byte("shh"),
+		Now:    func() time.Time { return fixedNow },
+	}
+	if err := s.ApplyAuth(&header); err != nil {
+		t.Fatalf("ApplyAuth: %v", err)
+	}
+
+	if got := header.Get("X-Auth-Key-Id"); got != "key-1" {
+		t.Errorf("X-Auth-Key-Id = %q, want key-1", got)
+	}
+	if got := header.Get("X-Auth-Timestamp"); got != "1700000000" {
+		t.Errorf("X-Auth-Timestamp = %q, want 1700000000", got)
+	}
+
+	mac := hmac.New(sha256.New, []byte("shh"))
+	mac.Write([]byte("1700000000"))
+	want := hex.EncodeToString(mac.Sum(nil))
+	if got := header.Get("X-Auth-Signature"); got != want {
+		t.Errorf("X-Auth-Signature = %q, want %q", got, want)
+	}
+}
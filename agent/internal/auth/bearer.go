@@ -0,0 +1,14 @@
+package auth
+
+import "net/http"
+
+// BearerTokenStrategy sets a static "Authorization: Bearer <token>" header.
+type BearerTokenStrategy struct {
+	Token string
+}
+
+// ApplyAuth implements Strategy.
+func (b BearerTokenStrategy) ApplyAuth(header *http.Header) error {
+	header.Set("Authorization", "Bearer "+b.Token)
+	return nil
+}
@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HMACStrategy signs the current Unix timestamp with a shared secret and
+// sets the result in a header trio, letting the Console verify a request
+// was made by a holder of the secret without ever transmitting it.
+type HMACStrategy struct {
+	KeyID  string
+	Secret []byte
+
+	// Now returns the current time. Defaults to time.Now; overridable in
+	// tests for a deterministic signature.
+	Now func() time.Time
+}
+
+// ApplyAuth implements Strategy.
+func (h HMACStrategy) ApplyAuth(header *http.Header) error {
+	now := h.Now
+	if now == nil {
+		now = time.Now
+	}
+	ts := strconv.FormatInt(now().Unix(), 10)
+
+	mac := hmac.New(sha256.New, h.Secret)
+	mac.Write([]byte(ts))
+	sig := hex.EncodeToString(mac.Sum(nil))
+
+	header.Set("X-Auth-Key-Id", h.KeyID)
+	header.Set("X-Auth-Timestamp", ts)
+	header.Set("X-Auth-Signature", sig)
+	return nil
+}
@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// OIDCStrategy sets a bearer token sourced dynamically from an OIDC token
+// provider, e.g. one that refreshes short-lived access tokens in the
+// background and hands back the current one on each call.
+type OIDCStrategy struct {
+	// TokenSource returns the current access token, refreshing it first
+	// if necessary.
+	TokenSource func() (string, error)
+}
+
+// ApplyAuth implements Strategy.
+func (o OIDCStrategy) ApplyAuth(header *http.Header) error {
+	token, err := o.TokenSource()
+	if err != nil {
+		return fmt.Errorf("auth: get OIDC token: %w", err)
+	}
+	header.Set("Authorization", "Bearer "+token)
+	return nil
+}
@@ -0,0 +1,125 @@
+// Package certwatch periodically checks configured TLS certificate files
+// for approaching or past expiry and reports it via MsgEvent, so a
+// self-managed certificate doesn't expire silently on an instance
+// (SINDRI_AGENT_WATCH_CERTS, SINDRI_AGENT_CERT_WARN_DAYS).
+package certwatch
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// defaultCheckInterval is how often Start's background goroutine re-checks
+// the configured certificates.
+const defaultCheckInterval = 6 * time.Hour
+
+// DefaultWarnDays is how many days out from expiry a certificate starts
+// producing a "cert_expiry_warning" event, used when
+// SINDRI_AGENT_CERT_WARN_DAYS is unset.
+const DefaultWarnDays = 30
+
+// EventSender emits a MsgEvent to the Console.
+type EventSender interface {
+	SendEvent(event protocol.MsgEvent) error
+}
+
+// Watcher checks a fixed set of PEM certificate files for expiry.
+type Watcher struct {
+	// Paths lists the certificate files to check, from
+	// SINDRI_AGENT_WATCH_CERTS.
+	Paths []string
+
+	// WarnDays is how many days out from expiry a certificate starts
+	// producing a warning event.
+	WarnDays int
+
+	done chan struct{}
+}
+
+// NewWatcher returns a Watcher for paths, warning warnDays before expiry.
+func NewWatcher(paths []string, warnDays int) *Watcher {
+	return &Watcher{Paths: paths, WarnDays: warnDays}
+}
+
+// Check reads each configured certificate and returns a MsgEvent for any
+// that is within WarnDays of expiring ("cert_expiry_warning") or has
+// already expired ("cert_expired"). A path that can't be read or parsed is
+// skipped rather than failing the whole check, so one broken certificate
+// doesn't hide warnings about the others.
+func (w *Watcher) Check() []protocol.MsgEvent {
+	var events []protocol.MsgEvent
+	now := time.Now()
+
+	for _, path := range w.Paths {
+		cert, err := loadCertificate(path)
+		if err != nil {
+			continue
+		}
+
+		daysRemaining := int(cert.NotAfter.Sub(now).Hours() / 24)
+		detail := fmt.Sprintf("path=%s expiry=%s days_remaining=%d", path, cert.NotAfter.Format(time.RFC3339), daysRemaining)
+
+		switch {
+		case now.After(cert.NotAfter):
+			events = append(events, protocol.MsgEvent{Kind: "cert_expired", Detail: detail})
+		case daysRemaining <= w.WarnDays:
+			events = append(events, protocol.MsgEvent{Kind: "cert_expiry_warning", Detail: detail})
+		}
+	}
+
+	return events
+}
+
+// loadCertificate reads and parses the leaf certificate from the PEM file
+// at path. Unlike tls.LoadX509KeyPair, it requires only the certificate,
+// not a matching private key, since SINDRI_AGENT_WATCH_CERTS names bare
+// certificate files.
+func loadCertificate(path string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("certwatch: read %s: %w", path, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("certwatch: no PEM block found in %s", path)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("certwatch: parse certificate %s: %w", path, err)
+	}
+	return cert, nil
+}
+
+// Start begins calling Check every defaultCheckInterval in a background
+// goroutine, forwarding any emitted events to sender, until Stop is
+// called. Calling Start more than once without an intervening Stop leaks
+// the earlier goroutine.
+func (w *Watcher) Start(sender EventSender) {
+	w.done = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(defaultCheckInterval)
+		defer ticker.Stop()
+		for {
+			for _, event := range w.Check() {
+				_ = sender.SendEvent(event)
+			}
+			select {
+			case <-ticker.C:
+			case <-w.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the background polling goroutine started by Start.
+func (w *Watcher) Stop() {
+	if w.done != nil {
+		close(w.done)
+	}
+}
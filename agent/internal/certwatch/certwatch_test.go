@@ -0,0 +1,97 @@
+package certwatch
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCertExpiringAt writes a self-signed PEM certificate expiring at
+// notAfter to dir and returns its path.
+func writeCertExpiringAt(t *testing.T, dir, name string, notAfter time.Time) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+
+	path := filepath.Join(dir, name+".pem")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create %s: %v", path, err)
+	}
+	defer f.Close()
+	if err := pem.Encode(f, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+		t.Fatalf("encode PEM: %v", err)
+	}
+	return path
+}
+
+func TestCheckEmitsWarningForCertExpiringSoon(t *testing.T) {
+	dir := t.TempDir()
+	path := writeCertExpiringAt(t, dir, "expiring-soon", time.Now().Add(24*time.Hour))
+
+	w := NewWatcher([]string{path}, DefaultWarnDays)
+	events := w.Check()
+
+	if len(events) != 1 {
+		t.Fatalf("Check() returned %d events, want 1: %+v", len(events), events)
+	}
+	if events[0].Kind != "cert_expiry_warning" {
+		t.Errorf("Kind = %q, want cert_expiry_warning", events[0].Kind)
+	}
+}
+
+func TestCheckEmitsExpiredForPastCert(t *testing.T) {
+	dir := t.TempDir()
+	path := writeCertExpiringAt(t, dir, "already-expired", time.Now().Add(-time.Hour))
+
+	w := NewWatcher([]string{path}, DefaultWarnDays)
+	events := w.Check()
+
+	if len(events) != 1 {
+		t.Fatalf("Check() returned %d events, want 1: %+v", len(events), events)
+	}
+	if events[0].Kind != "cert_expired" {
+		t.Errorf("Kind = %q, want cert_expired", events[0].Kind)
+	}
+}
+
+func TestCheckIgnoresCertNotNearExpiry(t *testing.T) {
+	dir := t.TempDir()
+	path := writeCertExpiringAt(t, dir, "healthy", time.Now().Add(365*24*time.Hour))
+
+	w := NewWatcher([]string{path}, DefaultWarnDays)
+	if events := w.Check(); len(events) != 0 {
+		t.Errorf("Check() = %+v, want no events", events)
+	}
+}
+
+func TestCheckSkipsUnreadablePath(t *testing.T) {
+	w := NewWatcher([]string{"/does/not/exist.pem"}, DefaultWarnDays)
+	if events := w.Check(); len(events) != 0 {
+		t.Errorf("Check() = %+v, want no events for an unreadable path", events)
+	}
+}
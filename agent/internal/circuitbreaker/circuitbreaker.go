@@ -0,0 +1,140 @@
+// Package circuitbreaker protects a flaky remote dependency from repeated,
+// pointless retries by tripping open after consecutive failures and
+// giving it time to recover before trying again.
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by Breaker.Do without calling fn when the
+// breaker is open and RecoveryTimeout has not yet elapsed since it
+// tripped.
+var ErrCircuitOpen = errors.New("circuitbreaker: circuit is open")
+
+// State is one of a Breaker's three states.
+type State string
+
+const (
+	// StateClosed is the normal state: Do calls fn and counts failures.
+	StateClosed State = "closed"
+
+	// StateOpen means fn has failed FailureThreshold times in a row; Do
+	// returns ErrCircuitOpen without calling fn until RecoveryTimeout has
+	// elapsed.
+	StateOpen State = "open"
+
+	// StateHalfOpen means RecoveryTimeout has elapsed since the breaker
+	// opened; the next Do call is let through as a trial. Success closes
+	// the breaker again, failure reopens it.
+	StateHalfOpen State = "half_open"
+)
+
+// defaultFailureThreshold is used when Breaker.FailureThreshold is zero.
+const defaultFailureThreshold = 5
+
+// defaultRecoveryTimeout is used when Breaker.RecoveryTimeout is zero.
+const defaultRecoveryTimeout = 30 * time.Second
+
+// Breaker wraps calls to a flaky dependency, tripping open after
+// consecutive failures so callers stop paying the cost (latency, CPU,
+// network) of retrying a dependency that keeps failing. The zero value is
+// a usable Breaker with the package's default thresholds.
+type Breaker struct {
+	// FailureThreshold is how many consecutive Do failures trip the
+	// breaker open. Defaults to 5 if zero.
+	FailureThreshold int
+
+	// RecoveryTimeout is how long the breaker stays open before letting a
+	// trial call through as half-open. Defaults to 30s if zero.
+	RecoveryTimeout time.Duration
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+}
+
+// NewBreaker returns a Breaker with the package's default
+// FailureThreshold and RecoveryTimeout.
+func NewBreaker() *Breaker {
+	return &Breaker{
+		FailureThreshold: defaultFailureThreshold,
+		RecoveryTimeout:  defaultRecoveryTimeout,
+	}
+}
+
+// State returns b's current state, first transitioning open to half-open
+// if RecoveryTimeout has elapsed.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stateLocked()
+}
+
+// stateLocked returns b's current state, promoting open to half-open if
+// RecoveryTimeout has elapsed. b.mu must be held.
+func (b *Breaker) stateLocked() State {
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.recoveryTimeout() {
+		b.state = StateHalfOpen
+	}
+	if b.state == "" {
+		return StateClosed
+	}
+	return b.state
+}
+
+func (b *Breaker) recoveryTimeout() time.Duration {
+	if b.RecoveryTimeout <= 0 {
+		return defaultRecoveryTimeout
+	}
+	return b.RecoveryTimeout
+}
+
+func (b *Breaker) failureThreshold() int {
+	if b.FailureThreshold <= 0 {
+		return defaultFailureThreshold
+	}
+	return b.FailureThreshold
+}
+
+// Do calls fn if the breaker is closed or half-open, returning fn's
+// error, if any. If the breaker is open, Do returns ErrCircuitOpen
+// immediately without calling fn. A half-open trial that succeeds closes
+// the breaker and resets its failure count; one that fails reopens it.
+func (b *Breaker) Do(fn func() error) error {
+	b.mu.Lock()
+	if b.stateLocked() == StateOpen {
+		b.mu.Unlock()
+		return ErrCircuitOpen
+	}
+	b.mu.Unlock()
+
+	err := fn()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err != nil {
+		b.failures++
+		if b.state == StateHalfOpen || b.failures >= b.failureThreshold() {
+			b.state = StateOpen
+			b.openedAt = time.Now()
+		}
+		return err
+	}
+
+	b.state = StateClosed
+	b.failures = 0
+	return nil
+}
+
+// Reset returns b to StateClosed with its failure count cleared,
+// regardless of its current state.
+func (b *Breaker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = StateClosed
+	b.failures = 0
+}
@@ -0,0 +1,120 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errBoom = errors.New("boom")
+
+func TestDoTripsOpenAfterFailureThreshold(t *testing.T) {
+	b := &Breaker{FailureThreshold: 5, RecoveryTimeout: time.Hour}
+
+	for i := 0; i < 5; i++ {
+		if err := b.Do(func() error { return errBoom }); !errors.Is(err, errBoom) {
+			t.Fatalf("attempt %d: err = %v, want errBoom", i, err)
+		}
+	}
+
+	called := false
+	err := b.Do(func() error { called = true; return nil })
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen", err)
+	}
+	if called {
+		t.Error("fn was called while circuit was open")
+	}
+	if got := b.State(); got != StateOpen {
+		t.Errorf("State() = %q, want %q", got, StateOpen)
+	}
+}
+
+func TestDoTransitionsToHalfOpenAfterRecoveryTimeout(t *testing.T) {
+	b := &Breaker{FailureThreshold: 1, RecoveryTimeout: 20 * time.Millisecond}
+
+	if err := b.Do(func() error { return errBoom }); !errors.Is(err, errBoom) {
+		t.Fatalf("err = %v, want errBoom", err)
+	}
+	if got := b.State(); got != StateOpen {
+		t.Fatalf("State() = %q, want %q", got, StateOpen)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if got := b.State(); got != StateHalfOpen {
+		t.Errorf("State() = %q, want %q", got, StateHalfOpen)
+	}
+}
+
+func TestDoClosesOnHalfOpenSuccess(t *testing.T) {
+	b := &Breaker{FailureThreshold: 1, RecoveryTimeout: 20 * time.Millisecond}
+
+	_ = b.Do(func() error { return errBoom })
+	time.Sleep(30 * time.Millisecond)
+
+	if err := b.Do(func() error { return nil }); err != nil {
+		t.Fatalf("half-open trial: err = %v, want nil", err)
+	}
+	if got := b.State(); got != StateClosed {
+		t.Errorf("State() = %q, want %q", got, StateClosed)
+	}
+}
+
+func TestDoReopensOnHalfOpenFailure(t *testing.T) {
+	b := &Breaker{FailureThreshold: 1, RecoveryTimeout: 20 * time.Millisecond}
+
+	_ = b.Do(func() error { return errBoom })
+	time.Sleep(30 * time.Millisecond)
+
+	if err := b.Do(func() error { return errBoom }); !errors.Is(err, errBoom) {
+		t.Fatalf("half-open trial: err = %v, want errBoom", err)
+	}
+	if got := b.State(); got != StateOpen {
+		t.Errorf("State() = %q, want %q", got, StateOpen)
+	}
+}
+
+func TestDoResetsFailureCountOnSuccess(t *testing.T) {
+	b := &Breaker{FailureThreshold: 2, RecoveryTimeout: time.Hour}
+
+	_ = b.Do(func() error { return errBoom })
+	_ = b.Do(func() error { return nil })
+	if err := b.Do(func() error { return errBoom }); !errors.Is(err, errBoom) {
+		t.Fatalf("err = %v, want errBoom", err)
+	}
+	if got := b.State(); got != StateClosed {
+		t.Errorf("State() = %q, want %q after a single post-reset failure", got, StateClosed)
+	}
+}
+
+func TestZeroValueBreakerUsesDefaults(t *testing.T) {
+	b := &Breaker{}
+	for i := 0; i < defaultFailureThreshold; i++ {
+		_ = b.Do(func() error { return errBoom })
+	}
+	if got := b.State(); got != StateOpen {
+		t.Errorf("State() = %q, want %q after %d failures", got, StateOpen, defaultFailureThreshold)
+	}
+}
+
+func TestReset(t *testing.T) {
+	b := &Breaker{FailureThreshold: 1, RecoveryTimeout: time.Hour}
+	_ = b.Do(func() error { return errBoom })
+	if got := b.State(); got != StateOpen {
+		t.Fatalf("State() = %q, want %q", got, StateOpen)
+	}
+
+	b.Reset()
+	if got := b.State(); got != StateClosed {
+		t.Errorf("State() = %q, want %q", got, StateClosed)
+	}
+
+	called := false
+	if err := b.Do(func() error { called = true; return nil }); err != nil {
+		t.Fatalf("Do after Reset: err = %v", err)
+	}
+	if !called {
+		t.Error("fn was not called after Reset")
+	}
+}
@@ -0,0 +1,16 @@
+package cloudmetadata
+
+import "fmt"
+
+// AWSParser parses the JSON document returned by the EC2 instance identity
+// document endpoint (http://169.254.169.254/latest/dynamic/instance-identity/document).
+type AWSParser struct{}
+
+// Parse implements Parser.
+func (AWSParser) Parse(body []byte) (map[string]string, error) {
+	tags, err := parseFlatJSON(body)
+	if err != nil {
+		return nil, fmt.Errorf("cloudmetadata: parse aws metadata: %w", err)
+	}
+	return tags, nil
+}
@@ -0,0 +1,22 @@
+// Package cloudmetadata fetches instance metadata from a cloud provider's
+// metadata service and flattens it into string tags for the Console.
+package cloudmetadata
+
+// Parser extracts flat string tags from a cloud metadata service response
+// body.
+type Parser interface {
+	Parse(body []byte) (map[string]string, error)
+}
+
+// ParserForProvider returns the Parser for the named cloud provider
+// ("aws", "gcp"), or nil if provider is unrecognized.
+func ParserForProvider(provider string) Parser {
+	switch provider {
+	case "aws":
+		return AWSParser{}
+	case "gcp":
+		return GCPParser{}
+	default:
+		return nil
+	}
+}
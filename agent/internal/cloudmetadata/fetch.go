@@ -0,0 +1,41 @@
+package cloudmetadata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Fetch retrieves the metadata document at url within timeout and parses
+// it with parser. A non-2xx response (in particular a 5xx from an
+// overloaded or unavailable metadata service) is returned as an error;
+// callers should treat that as non-fatal to registration and simply skip
+// merging cloud metadata tags.
+func Fetch(ctx context.Context, url string, parser Parser, timeout time.Duration) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cloudmetadata: build request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cloudmetadata: fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("cloudmetadata: %s returned status %d", url, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cloudmetadata: read response body: %w", err)
+	}
+
+	return parser.Parse(body)
+}
@@ -0,0 +1,41 @@
+package cloudmetadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchParsesAWSMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"instanceId": "i-0123456789abcdef0", "region": "us-east-1"}`))
+	}))
+	defer server.Close()
+
+	tags, err := Fetch(context.Background(), server.URL, AWSParser{}, 2*time.Second)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if tags["instanceId"] != "i-0123456789abcdef0" || tags["region"] != "us-east-1" {
+		t.Errorf("unexpected tags: %+v", tags)
+	}
+}
+
+func TestFetchReturnsErrorOnServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if _, err := Fetch(context.Background(), server.URL, AWSParser{}, 2*time.Second); err == nil {
+		t.Fatal("expected an error for a 5xx metadata response")
+	}
+}
+
+func TestParserForProviderUnknown(t *testing.T) {
+	if p := ParserForProvider("azure"); p != nil {
+		t.Errorf("expected nil parser for an unrecognized provider, got %T", p)
+	}
+}
@@ -0,0 +1,17 @@
+package cloudmetadata
+
+import "fmt"
+
+// GCPParser parses the JSON document returned by the GCE metadata server
+// when queried with ?recursive=true
+// (http://metadata.google.internal/computeMetadata/v1/instance/?recursive=true).
+type GCPParser struct{}
+
+// Parse implements Parser.
+func (GCPParser) Parse(body []byte) (map[string]string, error) {
+	tags, err := parseFlatJSON(body)
+	if err != nil {
+		return nil, fmt.Errorf("cloudmetadata: parse gcp metadata: %w", err)
+	}
+	return tags, nil
+}
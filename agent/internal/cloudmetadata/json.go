@@ -0,0 +1,29 @@
+package cloudmetadata
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// parseFlatJSON decodes body as a JSON object and stringifies its
+// top-level scalar values. Nested objects and arrays are skipped, since
+// the Console only needs flat key/value tags.
+func parseFlatJSON(body []byte) (map[string]string, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("cloudmetadata: unmarshal metadata: %w", err)
+	}
+
+	tags := make(map[string]string, len(raw))
+	for k, v := range raw {
+		switch val := v.(type) {
+		case string:
+			tags[k] = val
+		case float64, bool:
+			tags[k] = fmt.Sprintf("%v", val)
+		default:
+			// nested object or array: not a flat tag, skip.
+		}
+	}
+	return tags, nil
+}
@@ -0,0 +1,64 @@
+// Package completions provides remote shell tab-completion suggestions for
+// the Console's web terminal, guarded by SINDRI_AGENT_COMPLETIONS_ENABLED.
+package completions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// maxCompletions bounds how many suggestions Handle returns, protecting the
+// Console from a pathologically broad compgen match (e.g. a single-letter
+// partial matching most of $PATH).
+const maxCompletions = 100
+
+// commandTimeout bounds how long the compgen subprocess is allowed to run.
+const commandTimeout = 2 * time.Second
+
+// ErrDisabled is returned by Handle when the agent has not opted in to
+// tab-completion via SINDRI_AGENT_COMPLETIONS_ENABLED.
+var ErrDisabled = fmt.Errorf("completions: tab-completion is disabled")
+
+// Handle returns completion suggestions for req.Partial. It returns
+// ErrDisabled if enabled is false. An empty Partial returns an empty list
+// rather than every command on $PATH.
+func Handle(req protocol.MsgCompletionsRequest, enabled bool) (protocol.MsgCompletionsResponse, error) {
+	if !enabled {
+		return protocol.MsgCompletionsResponse{}, ErrDisabled
+	}
+	if req.Partial == "" {
+		return protocol.MsgCompletionsResponse{RequestID: req.RequestID, Completions: []string{}}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	// req.Partial arrives over the wire from the Console and must not be
+	// interpolated into the script text; pass it as a positional
+	// parameter instead so bash sees it as inert data.
+	cmd := exec.CommandContext(ctx, "bash", "--norc", "-c", `compgen -c -- "$1"`, "--", req.Partial)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return protocol.MsgCompletionsResponse{}, fmt.Errorf("completions: run compgen: %w", err)
+	}
+
+	completions := []string{}
+	for _, line := range strings.Split(strings.TrimRight(out.String(), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		completions = append(completions, line)
+		if len(completions) >= maxCompletions {
+			break
+		}
+	}
+
+	return protocol.MsgCompletionsResponse{RequestID: req.RequestID, Completions: completions}, nil
+}
@@ -0,0 +1,82 @@
+package completions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// installFakeBash writes an executable "bash" onto PATH that prints n lines
+// regardless of its arguments, so tests can exercise Handle's output
+// handling without depending on a real compgen implementation.
+func installFakeBash(t *testing.T, n int) {
+	t.Helper()
+
+	dir := t.TempDir()
+	var script strings.Builder
+	script.WriteString("#!/bin/sh\n")
+	for i := 1; i <= n; i++ {
+		script.WriteString(fmt.Sprintf("echo cmd%d\n", i))
+	}
+
+	path := filepath.Join(dir, "bash")
+	if err := os.WriteFile(path, []byte(script.String()), 0o755); err != nil {
+		t.Fatalf("write fake bash: %v", err)
+	}
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestHandleReturnsErrDisabled(t *testing.T) {
+	_, err := Handle(protocol.MsgCompletionsRequest{Partial: "l"}, false)
+	if err != ErrDisabled {
+		t.Errorf("err = %v, want ErrDisabled", err)
+	}
+}
+
+func TestHandleEmptyPartialReturnsEmptyList(t *testing.T) {
+	resp, err := Handle(protocol.MsgCompletionsRequest{RequestID: "r1"}, true)
+	if err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if len(resp.Completions) != 0 {
+		t.Errorf("Completions = %v, want empty", resp.Completions)
+	}
+}
+
+func TestHandleCapsAt100Completions(t *testing.T) {
+	installFakeBash(t, 150)
+
+	resp, err := Handle(protocol.MsgCompletionsRequest{RequestID: "r2", Partial: "cmd"}, true)
+	if err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if len(resp.Completions) != maxCompletions {
+		t.Errorf("len(Completions) = %d, want %d", len(resp.Completions), maxCompletions)
+	}
+	if resp.Completions[0] != "cmd1" {
+		t.Errorf("Completions[0] = %q, want cmd1", resp.Completions[0])
+	}
+}
+
+func TestHandleSplitsOnNewlines(t *testing.T) {
+	installFakeBash(t, 3)
+
+	resp, err := Handle(protocol.MsgCompletionsRequest{RequestID: "r3", Partial: "cmd"}, true)
+	if err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	want := []string{"cmd1", "cmd2", "cmd3"}
+	if len(resp.Completions) != len(want) {
+		t.Fatalf("Completions = %v, want %v", resp.Completions, want)
+	}
+	for i, c := range want {
+		if resp.Completions[i] != c {
+			t.Errorf("Completions[%d] = %q, want %q", i, resp.Completions[i], c)
+		}
+	}
+}
@@ -0,0 +1,693 @@
+// Package config loads sindri-agent configuration from the environment,
+// optionally seeded from a YAML or TOML file (see LoadFile).
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultWALPath               = "~/.sindri/metrics.wal"
+	defaultWALMaxRecords         = 1440
+	defaultMaxFileReadBytes      = 10 * 1024 * 1024
+	defaultCPUSampleDuration     = 200 * time.Millisecond
+	defaultWebSocketPath         = "/v1/agent/bridge"
+	defaultMetricsInterval       = 60 * time.Second
+	defaultMetricsJitterPct      = 10
+	defaultMetricsPushBatchSize  = 1
+	defaultHBDiskPressurePct     = 90.0
+	defaultHBMemPressurePct      = 85.0
+	defaultMaxArchiveBytes       = 500 * 1024 * 1024
+	defaultTerminalInputMaxBytes = 64 * 1024
+	defaultAnnotationTTL         = 24 * time.Hour
+	defaultCertWarnDays          = 30
+	defaultFlowBufferBytes       = 1024 * 1024
+	defaultGCInterval            = 5 * time.Minute
+	defaultCompressThreshold     = 4 * 1024
+	defaultMaxTransferBytes      = 500 * 1024 * 1024
+
+	// minMetricsInterval is the smallest interval allowed between metrics
+	// pushes, preventing a misconfigured fleet from hammering the Console.
+	minMetricsInterval = 5 * time.Second
+)
+
+// Config holds runtime configuration for the agent, populated from
+// SINDRI_AGENT_* and SINDRI_CONSOLE_* environment variables.
+type Config struct {
+	ConsoleURL    string
+	WebSocketPath string
+	APIKey        string
+
+	WALPath       string
+	WALMaxRecords int
+
+	AutoUpdate bool
+
+	FSRoot           string
+	MaxFileReadBytes int64
+
+	TLSServerName string
+
+	// TLSCertFile and TLSKeyFile, if both set, name a PEM client
+	// certificate and private key presented during the Console TLS
+	// handshake, from SINDRI_AGENT_TLS_CERT and SINDRI_AGENT_TLS_KEY —
+	// for a self-hosted Console requiring mutual TLS.
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// TLSCAFile, if set, names a PEM file whose certificates are trusted
+	// as the sole roots for verifying the Console's certificate, from
+	// SINDRI_AGENT_TLS_CA, instead of the system trust store — needed
+	// when the Console's certificate is signed by a private CA.
+	TLSCAFile string
+
+	// TLSSkipVerify disables verification of the Console's TLS
+	// certificate entirely, from SINDRI_AGENT_TLS_SKIP_VERIFY. Defaults
+	// to false; enabling it is only safe against a development Console,
+	// never in production.
+	TLSSkipVerify bool
+
+	ReconnectNotifyURL string
+
+	// Transport selects the agent-Console transport: "websocket" (the
+	// default) or "grpc".
+	Transport string
+
+	CPUSampleDuration time.Duration
+
+	// MetricsInterval is the base interval between metrics pushes. The
+	// interval actually used at runtime is MetricsInterval plus a random
+	// amount bounded by MetricsJitterPct — see JitteredMetricsInterval.
+	MetricsInterval time.Duration
+
+	// MetricsJitterPct (0-100) bounds the random jitter added to
+	// MetricsInterval, spreading out metrics pushes across a large fleet
+	// that would otherwise all report on the same second.
+	MetricsJitterPct int
+
+	// MetricsPushBatchSize is how many collected MetricsPayloads are
+	// accumulated into a single MsgMetricsBatch before sending, from
+	// SINDRI_AGENT_METRICS_PUSH_BATCH_SIZE. A value of 1 (the default)
+	// sends each payload individually with no batching. Values above 1
+	// trade off latency (a payload can wait up to MetricsPushBatchSize *
+	// MetricsInterval before it's sent) for fewer, larger messages on
+	// poor network connections.
+	MetricsPushBatchSize int
+
+	// DebugProfileEnabled gates handling of MsgDebugProfileRequest. It
+	// defaults to false since pprof profiles can expose sensitive process
+	// state (memory contents via heap dumps, stack traces) and are
+	// unnecessary overhead on most fleets.
+	DebugProfileEnabled bool
+
+	// HBDiskPressurePct and HBMemPressurePct (0-100) are the usage
+	// thresholds above which the heartbeat's DiskPressure and
+	// MemoryPressure summary flags are set.
+	HBDiskPressurePct float64
+	HBMemPressurePct  float64
+
+	// HBIncludeMetrics embeds a lightweight HeartbeatMetrics summary in
+	// every HeartbeatPayload, from SINDRI_AGENT_HEARTBEAT_INCLUDE_METRICS,
+	// so the Console can skip processing a standalone MsgMetrics for
+	// quick-overview dashboards.
+	HBIncludeMetrics bool
+
+	// Environment identifies the deployment environment this agent is
+	// running in (e.g. "production", "staging", "development"), so
+	// operators can distinguish them without relying on tags. Empty is a
+	// valid value; there is no default.
+	Environment string
+
+	// DiskIOStatsEnabled turns on per-device disk I/O latency, queue
+	// depth, and utilization metrics, computed from /proc/diskstats.
+	DiskIOStatsEnabled bool
+
+	// InstanceMetadataURL, if set, is queried for cloud instance metadata
+	// during registration, merged into RegistrationPayload.Tags under a
+	// "cloud_metadata." prefix. Empty disables the fetch (the default;
+	// AWS, GCP, and Azure each use a different metadata service URL, so
+	// there is no sane default).
+	InstanceMetadataURL string
+
+	// Provider selects which cloudmetadata.Parser to use for
+	// InstanceMetadataURL's response ("aws" or "gcp").
+	Provider string
+
+	// CompletionsEnabled gates handling of MsgCompletionsRequest. It
+	// defaults to false since it shells out to bash on the Console's
+	// behalf and is unnecessary overhead on most fleets.
+	CompletionsEnabled bool
+
+	// HTTPProxy mirrors the HTTP_PROXY environment variable, exposed for
+	// startup logging. The proxying itself is handled transparently by
+	// http.ProxyFromEnvironment in registration.New.
+	HTTPProxy string
+
+	// NoProxy holds additional NO_PROXY bypass entries from
+	// SINDRI_AGENT_NO_PROXY, merged into the process's NO_PROXY
+	// environment variable by Load so they take effect for any
+	// http.ProxyFromEnvironment-based client without operators having to
+	// edit the fleet-wide NO_PROXY value.
+	NoProxy string
+
+	// Tags holds instance tags applied at runtime via MsgTagUpdate,
+	// separate from any tags set at registration time so frequently
+	// changing tags don't require a full re-registration. Nil until the
+	// first update is applied.
+	Tags map[string]string
+
+	// GitCommit and BuildDate identify the exact build running, set by
+	// main from linker flags (e.g. -ldflags "-X main.gitCommit=...").
+	// Both are empty on a non-ldflags build.
+	GitCommit string
+	BuildDate string
+
+	// SyslogRateLimit caps forwarded syslog entries per second per
+	// subscription (see syslog.RateLimiter). 0 disables rate limiting.
+	SyslogRateLimit int
+
+	// MaxArchiveBytes caps the total uncompressed size of an archive
+	// created via MsgArchiveCreateRequest, protecting the instance's disk
+	// from a request that (accidentally or otherwise) archives far more
+	// than intended.
+	MaxArchiveBytes int64
+
+	// MaxTransferBytes caps the declared size of an upload or the size of
+	// a file requested for download via filetransfer.Handler, protecting
+	// the instance's disk and memory from an oversized file transfer.
+	MaxTransferBytes int64
+
+	// PortForwardEnabled gates handling of MsgPortForwardStart. It
+	// defaults to false since it lets the Console reach arbitrary hosts
+	// from the agent's network position.
+	PortForwardEnabled bool
+
+	// MessageSecret, when set, is used to HMAC-SHA256-sign outbound
+	// envelopes and verify inbound ones (see protocol.Sign/Verify),
+	// protecting message integrity against a compromised intermediary.
+	// Empty disables signing entirely.
+	MessageSecret string
+
+	// SecretsEnabled gates handling of MsgSecretInject. It defaults to
+	// false since it lets the Console inject arbitrary environment
+	// variables into PTY sessions on this instance.
+	SecretsEnabled bool
+
+	// AllowedForwardHosts lists the only RemoteHost values
+	// MsgPortForwardStart may target, from SINDRI_AGENT_ALLOWED_FORWARD_HOSTS
+	// (comma-separated). Empty means no host is allowed, even with
+	// PortForwardEnabled true — there is no "allow everything" default.
+	AllowedForwardHosts []string
+
+	// NetworkInterfaces lists the only interfaces reported in
+	// MetricsPayload.NetworkRate.Interfaces, from
+	// SINDRI_AGENT_NET_INTERFACES (comma-separated). Empty (the default)
+	// reports every interface — set this on multi-homed instances with
+	// many virtual interfaces (bridges, veths, tunnels) to avoid flooding
+	// the Console with rows it doesn't care about.
+	NetworkInterfaces []string
+
+	// TopProcessesCount reports the top N most CPU-hungry processes in
+	// MetricsPayload.TopProcesses, from SINDRI_AGENT_TOP_PROCESSES. 0 (the
+	// default) disables the feature entirely, since enumerating every
+	// process on the host on each collection cycle adds real overhead on
+	// systems with thousands of them.
+	TopProcessesCount int
+
+	// TerminalInputMaxBytes caps the size of a single MsgTerminalInput's
+	// Data field, protecting a session's PTY write from blocking for a
+	// long time on an oversized payload.
+	TerminalInputMaxBytes int
+
+	// DisableMetricsOnBattery, when true, makes the agent poll
+	// power.Monitor and scale MetricsInterval by
+	// power.BatteryIntervalMultiplier while running on battery power, to
+	// reduce power consumption on laptops and other battery-backed
+	// devices. Defaults to false (no behavior change).
+	DisableMetricsOnBattery bool
+
+	// AnnotationTTL bounds how long a MsgAnnotateInstance-applied
+	// annotation remains visible before annotations.Store expires it.
+	// Defaults to annotations.DefaultTTL (24h) if zero.
+	AnnotationTTL time.Duration
+
+	// InstanceID identifies this instance to the Console independent of
+	// hostname, e.g. as the path segment in the crash report upload URL
+	// (see crashreport.Report). Empty if unset.
+	InstanceID string
+
+	// CoreDumpOnPanic, when true, makes the agent capture a stack trace
+	// and recent log output and upload it to the Console before
+	// re-panicking, from SINDRI_AGENT_CORE_DUMP_ON_PANIC. Defaults to
+	// false since it uploads potentially sensitive log output.
+	CoreDumpOnPanic bool
+
+	// WatchCerts lists TLS certificate files certwatch.Watcher should
+	// periodically check for approaching expiry, from
+	// SINDRI_AGENT_WATCH_CERTS (comma-separated). Empty disables
+	// certificate expiry monitoring.
+	WatchCerts []string
+
+	// CertWarnDays is how many days out from expiry certwatch.Watcher
+	// starts emitting a cert_expiry_warning event.
+	CertWarnDays int
+
+	// FlowBufferBytes caps how much output a flow-controlled terminal
+	// session (see flowcontrol.Window) buffers while waiting for the
+	// Console to open more send credit, from
+	// SINDRI_AGENT_FLOW_BUFFER_BYTES. Oldest buffered bytes are dropped
+	// beyond this cap.
+	FlowBufferBytes int
+
+	// GCInterval is how often main forces a garbage collection cycle via
+	// runtime.GC, from SINDRI_AGENT_GC_INTERVAL, to keep heap
+	// fragmentation from terminal I/O buffers bounded on agents that run
+	// for days. Defaults to 5m if zero.
+	GCInterval time.Duration
+
+	// GOGC, if non-nil, is passed to runtime/debug.SetGCPercent by main
+	// before starting subsystems, from SINDRI_AGENT_GOGC. Nil (the
+	// default) leaves the Go runtime's own default (100) in effect.
+	GOGC *int
+
+	// GOMEMLIMIT, if non-nil, is passed to runtime/debug.SetMemoryLimit
+	// by main before starting subsystems, from SINDRI_AGENT_GOMEMLIMIT
+	// (bytes). Nil (the default) leaves the runtime's own default (no
+	// limit) in effect. Load rejects a value below the heap already in
+	// use at startup, since debug.SetMemoryLimit can't shrink live heap
+	// and such a limit would just trigger unbounded, unproductive GC.
+	GOMEMLIMIT *int64
+
+	// CompressThreshold is the minimum PTY output chunk size, in bytes,
+	// that terminal.NewOutputMessage will bother gzip-compressing, from
+	// SINDRI_AGENT_COMPRESS_THRESHOLD. Defaults to 4KiB if zero; chunks
+	// below it are sent uncompressed rather than pay gzip's per-message
+	// overhead for little or no size reduction.
+	CompressThreshold int
+
+	// PromAddr, if set, makes the agent serve the most recently collected
+	// MetricsPayload at /metrics in the Prometheus text exposition format
+	// (see promexport.Exporter), from SINDRI_AGENT_PROM_ADDR (e.g.
+	// ":9090"). Empty (the default) disables the endpoint entirely, for
+	// operators who only want metrics delivered over the Console
+	// WebSocket.
+	PromAddr string
+}
+
+// Load reads configuration from the environment, applying defaults for any
+// unset values. If SINDRI_CONFIG_FILE is set, or defaultConfigFilePath
+// exists, its contents are merged into the environment first (see
+// mergeConfigFileIntoEnv) — an environment variable already set always
+// wins over the same key in the file.
+func Load() (*Config, error) {
+	if err := mergeConfigFileIntoEnv(); err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{
+		ConsoleURL:            os.Getenv("SINDRI_CONSOLE_URL"),
+		WebSocketPath:         defaultWebSocketPath,
+		APIKey:                os.Getenv("SINDRI_CONSOLE_API_KEY"),
+		WALPath:               defaultWALPath,
+		WALMaxRecords:         defaultWALMaxRecords,
+		MaxFileReadBytes:      defaultMaxFileReadBytes,
+		CPUSampleDuration:     defaultCPUSampleDuration,
+		MetricsInterval:       defaultMetricsInterval,
+		MetricsJitterPct:      defaultMetricsJitterPct,
+		MetricsPushBatchSize:  defaultMetricsPushBatchSize,
+		HBDiskPressurePct:     defaultHBDiskPressurePct,
+		HBMemPressurePct:      defaultHBMemPressurePct,
+		MaxArchiveBytes:       defaultMaxArchiveBytes,
+		MaxTransferBytes:      defaultMaxTransferBytes,
+		TerminalInputMaxBytes: defaultTerminalInputMaxBytes,
+		AnnotationTTL:         defaultAnnotationTTL,
+		CertWarnDays:          defaultCertWarnDays,
+		FlowBufferBytes:       defaultFlowBufferBytes,
+		GCInterval:            defaultGCInterval,
+		CompressThreshold:     defaultCompressThreshold,
+	}
+
+	// SINDRI_CONSOLE_API_KEY_B64 lets secret managers that only emit
+	// base64 (Vault, Kubernetes secrets) supply the API key without a
+	// plain-text env var; SINDRI_CONSOLE_API_KEY always wins if both are
+	// set.
+	if cfg.APIKey == "" {
+		if v := os.Getenv("SINDRI_CONSOLE_API_KEY_B64"); v != "" {
+			decoded, err := base64.StdEncoding.DecodeString(v)
+			if err != nil {
+				return nil, fmt.Errorf("config: decode SINDRI_CONSOLE_API_KEY_B64: %w", err)
+			}
+			cfg.APIKey = string(decoded)
+		}
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_WAL_PATH"); v != "" {
+		cfg.WALPath = v
+	}
+	expanded, err := expandHome(cfg.WALPath)
+	if err != nil {
+		return nil, fmt.Errorf("config: resolve wal path: %w", err)
+	}
+	cfg.WALPath = expanded
+
+	if v := os.Getenv("SINDRI_AGENT_WAL_MAX_RECORDS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_WAL_MAX_RECORDS: %w", err)
+		}
+		cfg.WALMaxRecords = n
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_AUTO_UPDATE"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_AUTO_UPDATE: %w", err)
+		}
+		cfg.AutoUpdate = b
+	}
+
+	cfg.FSRoot = os.Getenv("SINDRI_AGENT_FS_ROOT")
+
+	if v := os.Getenv("SINDRI_AGENT_MAX_FILE_READ_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_MAX_FILE_READ_BYTES: %w", err)
+		}
+		cfg.MaxFileReadBytes = n
+	}
+
+	cfg.TLSServerName = os.Getenv("SINDRI_AGENT_TLS_SERVER_NAME")
+	cfg.TLSCertFile = os.Getenv("SINDRI_AGENT_TLS_CERT")
+	cfg.TLSKeyFile = os.Getenv("SINDRI_AGENT_TLS_KEY")
+	cfg.TLSCAFile = os.Getenv("SINDRI_AGENT_TLS_CA")
+
+	if v := os.Getenv("SINDRI_AGENT_TLS_SKIP_VERIFY"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_TLS_SKIP_VERIFY: %w", err)
+		}
+		cfg.TLSSkipVerify = b
+	}
+
+	cfg.ReconnectNotifyURL = os.Getenv("SINDRI_AGENT_RECONNECT_NOTIFY_URL")
+
+	cfg.Transport = "websocket"
+	if v := os.Getenv("SINDRI_AGENT_TRANSPORT"); v != "" {
+		cfg.Transport = v
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_CPU_SAMPLE_DURATION"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_CPU_SAMPLE_DURATION: %w", err)
+		}
+		cfg.CPUSampleDuration = d
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_WEBSOCKET_PATH"); v != "" {
+		cfg.WebSocketPath = v
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_METRICS_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_METRICS_INTERVAL: %w", err)
+		}
+		cfg.MetricsInterval = d
+	}
+	if cfg.MetricsInterval < minMetricsInterval {
+		return nil, fmt.Errorf("config: SINDRI_AGENT_METRICS_INTERVAL must be at least %s, got %s", minMetricsInterval, cfg.MetricsInterval)
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_METRICS_JITTER_PCT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_METRICS_JITTER_PCT: %w", err)
+		}
+		if n < 0 || n > 100 {
+			return nil, fmt.Errorf("config: SINDRI_AGENT_METRICS_JITTER_PCT must be between 0 and 100, got %d", n)
+		}
+		cfg.MetricsJitterPct = n
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_METRICS_PUSH_BATCH_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_METRICS_PUSH_BATCH_SIZE: %w", err)
+		}
+		if n < 1 {
+			return nil, fmt.Errorf("config: SINDRI_AGENT_METRICS_PUSH_BATCH_SIZE must be at least 1, got %d", n)
+		}
+		cfg.MetricsPushBatchSize = n
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_DEBUG_PROFILE_ENABLED"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_DEBUG_PROFILE_ENABLED: %w", err)
+		}
+		cfg.DebugProfileEnabled = b
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_HB_DISK_PRESSURE_PCT"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_HB_DISK_PRESSURE_PCT: %w", err)
+		}
+		cfg.HBDiskPressurePct = f
+	}
+	if v := os.Getenv("SINDRI_AGENT_HB_MEM_PRESSURE_PCT"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_HB_MEM_PRESSURE_PCT: %w", err)
+		}
+		cfg.HBMemPressurePct = f
+	}
+	if v := os.Getenv("SINDRI_AGENT_HEARTBEAT_INCLUDE_METRICS"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_HEARTBEAT_INCLUDE_METRICS: %w", err)
+		}
+		cfg.HBIncludeMetrics = b
+	}
+
+	cfg.Environment = os.Getenv("SINDRI_ENVIRONMENT")
+
+	if v := os.Getenv("SINDRI_AGENT_DISK_IO_STATS"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_DISK_IO_STATS: %w", err)
+		}
+		cfg.DiskIOStatsEnabled = b
+	}
+
+	cfg.InstanceMetadataURL = os.Getenv("SINDRI_AGENT_INSTANCE_METADATA_URL")
+	cfg.Provider = os.Getenv("SINDRI_AGENT_PROVIDER")
+
+	if v := os.Getenv("SINDRI_AGENT_COMPLETIONS_ENABLED"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_COMPLETIONS_ENABLED: %w", err)
+		}
+		cfg.CompletionsEnabled = b
+	}
+
+	cfg.HTTPProxy = os.Getenv("HTTP_PROXY")
+
+	if v := os.Getenv("SINDRI_AGENT_NO_PROXY"); v != "" {
+		cfg.NoProxy = v
+		merged := v
+		if existing := os.Getenv("NO_PROXY"); existing != "" {
+			merged = existing + "," + v
+		}
+		if err := os.Setenv("NO_PROXY", merged); err != nil {
+			return nil, fmt.Errorf("config: set NO_PROXY: %w", err)
+		}
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_SYSLOG_RATE_LIMIT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_SYSLOG_RATE_LIMIT: %w", err)
+		}
+		cfg.SyslogRateLimit = n
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_MAX_ARCHIVE_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_MAX_ARCHIVE_BYTES: %w", err)
+		}
+		cfg.MaxArchiveBytes = n
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_MAX_TRANSFER_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_MAX_TRANSFER_BYTES: %w", err)
+		}
+		cfg.MaxTransferBytes = n
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_PORT_FORWARD_ENABLED"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_PORT_FORWARD_ENABLED: %w", err)
+		}
+		cfg.PortForwardEnabled = b
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_ALLOWED_FORWARD_HOSTS"); v != "" {
+		for _, host := range strings.Split(v, ",") {
+			if host = strings.TrimSpace(host); host != "" {
+				cfg.AllowedForwardHosts = append(cfg.AllowedForwardHosts, host)
+			}
+		}
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_NET_INTERFACES"); v != "" {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				cfg.NetworkInterfaces = append(cfg.NetworkInterfaces, name)
+			}
+		}
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_TOP_PROCESSES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_TOP_PROCESSES: %w", err)
+		}
+		cfg.TopProcessesCount = n
+	}
+
+	cfg.MessageSecret = os.Getenv("SINDRI_AGENT_MESSAGE_SECRET")
+
+	if v := os.Getenv("SINDRI_AGENT_TERMINAL_INPUT_MAX_BYTES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_TERMINAL_INPUT_MAX_BYTES: %w", err)
+		}
+		cfg.TerminalInputMaxBytes = n
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_ANNOTATION_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_ANNOTATION_TTL: %w", err)
+		}
+		cfg.AnnotationTTL = d
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_DISABLE_METRICS_ON_BATTERY"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_DISABLE_METRICS_ON_BATTERY: %w", err)
+		}
+		cfg.DisableMetricsOnBattery = b
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_SECRETS_ENABLED"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_SECRETS_ENABLED: %w", err)
+		}
+		cfg.SecretsEnabled = b
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_WATCH_CERTS"); v != "" {
+		for _, path := range strings.Split(v, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				cfg.WatchCerts = append(cfg.WatchCerts, path)
+			}
+		}
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_CERT_WARN_DAYS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_CERT_WARN_DAYS: %w", err)
+		}
+		cfg.CertWarnDays = n
+	}
+
+	cfg.InstanceID = os.Getenv("SINDRI_AGENT_INSTANCE_ID")
+
+	if v := os.Getenv("SINDRI_AGENT_CORE_DUMP_ON_PANIC"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_CORE_DUMP_ON_PANIC: %w", err)
+		}
+		cfg.CoreDumpOnPanic = b
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_FLOW_BUFFER_BYTES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_FLOW_BUFFER_BYTES: %w", err)
+		}
+		cfg.FlowBufferBytes = n
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_GC_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_GC_INTERVAL: %w", err)
+		}
+		cfg.GCInterval = d
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_GOGC"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_GOGC: %w", err)
+		}
+		cfg.GOGC = &n
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_GOMEMLIMIT"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_GOMEMLIMIT: %w", err)
+		}
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		if n < int64(stats.HeapAlloc) {
+			return nil, fmt.Errorf("config: SINDRI_AGENT_GOMEMLIMIT (%d bytes) is below the current heap (%d bytes)", n, stats.HeapAlloc)
+		}
+		cfg.GOMEMLIMIT = &n
+	}
+
+	if v := os.Getenv("SINDRI_AGENT_COMPRESS_THRESHOLD"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse SINDRI_AGENT_COMPRESS_THRESHOLD: %w", err)
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("config: SINDRI_AGENT_COMPRESS_THRESHOLD must be at least 0, got %d", n)
+		}
+		cfg.CompressThreshold = n
+	}
+
+	cfg.PromAddr = os.Getenv("SINDRI_AGENT_PROM_ADDR")
+
+	return cfg, nil
+}
+
+func expandHome(path string) (string, error) {
+	if path == "" || path[0] != '~' {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[1:]), nil
+}
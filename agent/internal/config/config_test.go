@@ -0,0 +1,122 @@
+package config
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestLoadDecodesBase64APIKey(t *testing.T) {
+	t.Setenv("SINDRI_CONSOLE_API_KEY", "")
+	want := "s3cr3t-api-key"
+	t.Setenv("SINDRI_CONSOLE_API_KEY_B64", base64.StdEncoding.EncodeToString([]byte(want)))
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.APIKey != want {
+		t.Errorf("APIKey = %q, want %q", cfg.APIKey, want)
+	}
+}
+
+func TestLoadPrefersPlainAPIKeyOverBase64(t *testing.T) {
+	t.Setenv("SINDRI_CONSOLE_API_KEY", "plain-key")
+	t.Setenv("SINDRI_CONSOLE_API_KEY_B64", base64.StdEncoding.EncodeToString([]byte("b64-key")))
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.APIKey != "plain-key" {
+		t.Errorf("APIKey = %q, want plain-key", cfg.APIKey)
+	}
+}
+
+func TestLoadRejectsInvalidBase64APIKey(t *testing.T) {
+	t.Setenv("SINDRI_CONSOLE_API_KEY", "")
+	t.Setenv("SINDRI_CONSOLE_API_KEY_B64", "not-valid-base64!!!")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected an error decoding an invalid SINDRI_CONSOLE_API_KEY_B64")
+	}
+}
+
+func TestLoadParsesGOGC(t *testing.T) {
+	t.Setenv("SINDRI_AGENT_GOGC", "50")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.GOGC == nil || *cfg.GOGC != 50 {
+		t.Fatalf("GOGC = %v, want 50", cfg.GOGC)
+	}
+}
+
+func TestLoadRejectsInvalidGOGC(t *testing.T) {
+	t.Setenv("SINDRI_AGENT_GOGC", "not-a-number")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected an error for a non-numeric SINDRI_AGENT_GOGC")
+	}
+}
+
+func TestLoadRejectsGOMEMLIMITBelowCurrentHeap(t *testing.T) {
+	t.Setenv("SINDRI_AGENT_GOMEMLIMIT", "1")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected an error for a SINDRI_AGENT_GOMEMLIMIT below the current heap")
+	}
+}
+
+func TestLoadAcceptsGOMEMLIMITAboveCurrentHeap(t *testing.T) {
+	t.Setenv("SINDRI_AGENT_GOMEMLIMIT", "17179869184") // 16 GiB
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.GOMEMLIMIT == nil || *cfg.GOMEMLIMIT != 17179869184 {
+		t.Fatalf("GOMEMLIMIT = %v, want 17179869184", cfg.GOMEMLIMIT)
+	}
+}
+
+func TestLoadDefaultsGCInterval(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.GCInterval != defaultGCInterval {
+		t.Errorf("GCInterval = %s, want %s", cfg.GCInterval, defaultGCInterval)
+	}
+}
+
+func TestLoadDefaultsCompressThreshold(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.CompressThreshold != defaultCompressThreshold {
+		t.Errorf("CompressThreshold = %d, want %d", cfg.CompressThreshold, defaultCompressThreshold)
+	}
+}
+
+func TestLoadParsesCompressThreshold(t *testing.T) {
+	t.Setenv("SINDRI_AGENT_COMPRESS_THRESHOLD", "1024")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.CompressThreshold != 1024 {
+		t.Errorf("CompressThreshold = %d, want 1024", cfg.CompressThreshold)
+	}
+}
+
+func TestLoadRejectsNegativeCompressThreshold(t *testing.T) {
+	t.Setenv("SINDRI_AGENT_COMPRESS_THRESHOLD", "-1")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected an error for a negative SINDRI_AGENT_COMPRESS_THRESHOLD")
+	}
+}
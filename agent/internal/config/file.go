@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultConfigFilePath is checked for a configuration file when
+// SINDRI_CONFIG_FILE is unset, so large deployments can commit one file to
+// git instead of injecting a full SINDRI_AGENT_* environment per instance.
+const defaultConfigFilePath = "/etc/sindri/agent.yaml"
+
+// LoadFile parses the YAML or TOML configuration file at path (selected by
+// its extension, .yaml/.yml or .toml) and returns a fully validated
+// Config, exactly as if every key in the file had first been set as the
+// correspondingly named environment variable (e.g. SINDRI_AGENT_PROM_ADDR)
+// and then Load called — an environment variable already set in the
+// process always wins over the same key in the file, and every field is
+// validated through Load's usual checks.
+func LoadFile(path string) (*Config, error) {
+	if err := mergeFileIntoEnv(path); err != nil {
+		return nil, err
+	}
+	return Load()
+}
+
+// mergeConfigFileIntoEnv locates a config file — SINDRI_CONFIG_FILE if
+// set, else defaultConfigFilePath if it exists — and merges it into the
+// process environment. It is a no-op if neither is present, so pure
+// environment-variable configuration keeps working unchanged.
+func mergeConfigFileIntoEnv() error {
+	path := os.Getenv("SINDRI_CONFIG_FILE")
+	if path == "" {
+		if _, err := os.Stat(defaultConfigFilePath); err != nil {
+			return nil
+		}
+		path = defaultConfigFilePath
+	}
+	return mergeFileIntoEnv(path)
+}
+
+// mergeFileIntoEnv parses path and sets each key it contains as an
+// environment variable, skipping any key already set in the process
+// environment so real environment variables always take precedence.
+func mergeFileIntoEnv(path string) error {
+	values, err := parseConfigFile(path)
+	if err != nil {
+		return fmt.Errorf("config: load config file %s: %w", path, err)
+	}
+	for key, value := range values {
+		if _, alreadySet := os.LookupEnv(key); alreadySet {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("config: set %s from config file %s: %w", key, path, err)
+		}
+	}
+	return nil
+}
+
+// parseConfigFile reads path and parses it as a flat key/value document,
+// selecting the syntax by file extension. This package does not depend on
+// a real YAML or TOML parser (Config has no network access to fetch one
+// at the time this was written), so both formats are read with
+// parseFlatKeyValue's narrow, hand-rolled grammar rather than the full
+// YAML or TOML spec — see its doc comment for exactly what that supports.
+// Anything outside that grammar is a parse error, not a silent
+// misreading.
+func parseConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		return parseFlatKeyValue(string(data), ":")
+	case ".toml":
+		return parseFlatKeyValue(string(data), "=")
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q (want .yaml, .yml, or .toml)", ext)
+	}
+}
+
+// parseFlatKeyValue parses contents as a sequence of top-level
+// "key<sep>value" lines — this is the real, narrow grammar
+// parseConfigFile supports for both YAML and TOML, not either language's
+// full spec. It is enough to set every Config field, since each already
+// has a single corresponding scalar SINDRI_AGENT_*/SINDRI_CONSOLE_* key,
+// but a document using any other YAML or TOML feature is rejected rather
+// than silently misread:
+//
+//   - blank lines and lines starting with "#" are ignored
+//   - every other line must be "key<sep>value" with no leading
+//     indentation (indentation implies YAML nesting, which isn't parsed)
+//   - a line starting with "-" (a YAML list item) or "[" (a TOML table
+//     header) is rejected
+//   - value may be a bare scalar, or wrapped in matching single or
+//     double quotes; either form may be followed by a "#" comment. After
+//     a bare value the "#" must be preceded by a space, to distinguish it
+//     from a "#" appearing inside the value; any other "#" in a bare
+//     value is rejected
+func parseFlatKeyValue(contents, sep string) (map[string]string, error) {
+	values := make(map[string]string)
+	for i, rawLine := range strings.Split(contents, "\n") {
+		line := strings.TrimRight(rawLine, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if trimmed != line {
+			return nil, fmt.Errorf("line %d: indented lines are not supported, only flat top-level key/value pairs: %q", i+1, line)
+		}
+		if strings.HasPrefix(trimmed, "-") {
+			return nil, fmt.Errorf("line %d: list items are not supported: %q", i+1, trimmed)
+		}
+		if strings.HasPrefix(trimmed, "[") {
+			return nil, fmt.Errorf("line %d: TOML table headers are not supported: %q", i+1, trimmed)
+		}
+
+		idx := strings.Index(trimmed, sep)
+		if idx < 0 {
+			return nil, fmt.Errorf("line %d: expected a %q-separated key/value pair, got %q", i+1, sep, trimmed)
+		}
+		key := strings.TrimSpace(trimmed[:idx])
+		if key == "" {
+			return nil, fmt.Errorf("line %d: empty key", i+1)
+		}
+
+		value, err := parseScalarValue(strings.TrimSpace(trimmed[idx+len(sep):]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// parseScalarValue parses the value half of a "key<sep>value" line — see
+// parseFlatKeyValue's doc comment for the exact grammar it accepts.
+func parseScalarValue(rest string) (string, error) {
+	if rest == "" {
+		return "", nil
+	}
+
+	if quote := rest[0]; quote == '"' || quote == '\'' {
+		closeIdx := strings.IndexByte(rest[1:], quote)
+		if closeIdx < 0 {
+			return "", fmt.Errorf("unterminated quoted value %q", rest)
+		}
+		closeIdx++ // make closeIdx relative to rest, not rest[1:]
+		value := rest[1:closeIdx]
+
+		trailing := strings.TrimSpace(rest[closeIdx+1:])
+		if trailing != "" && !strings.HasPrefix(trailing, "#") {
+			return "", fmt.Errorf("unexpected content after quoted value %q: %q", rest[:closeIdx+1], trailing)
+		}
+		return value, nil
+	}
+
+	if i := strings.Index(rest, " #"); i >= 0 {
+		return strings.TrimSpace(rest[:i]), nil
+	}
+	if strings.Contains(rest, "#") {
+		return "", fmt.Errorf("unsupported value %q: a trailing comment must be preceded by whitespace", rest)
+	}
+	return rest, nil
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTempConfigFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("write temp config file: %v", err)
+	}
+	return path
+}
+
+// unsetAfter clears key from the process environment once the test
+// finishes, since LoadFile mutates the real environment via os.Setenv
+// (unlike t.Setenv, which only affects the current test).
+func unsetAfter(t *testing.T, key string) {
+	t.Helper()
+	t.Cleanup(func() { os.Unsetenv(key) })
+}
+
+func TestLoadFileParsesYAML(t *testing.T) {
+	path := writeTempConfigFile(t, "agent.yaml", `
+# comment line, ignored
+SINDRI_AGENT_PROM_ADDR: ":9191"
+SINDRI_AGENT_DISK_IO_STATS: "true"
+`)
+	unsetAfter(t, "SINDRI_AGENT_PROM_ADDR")
+	unsetAfter(t, "SINDRI_AGENT_DISK_IO_STATS")
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if cfg.PromAddr != ":9191" {
+		t.Errorf("PromAddr = %q, want %q", cfg.PromAddr, ":9191")
+	}
+	if !cfg.DiskIOStatsEnabled {
+		t.Error("DiskIOStatsEnabled = false, want true")
+	}
+}
+
+func TestLoadFileParsesTOML(t *testing.T) {
+	path := writeTempConfigFile(t, "agent.toml", `
+# comment line, ignored
+SINDRI_AGENT_PROM_ADDR = ":9292"
+SINDRI_AGENT_DISK_IO_STATS = "true"
+`)
+	unsetAfter(t, "SINDRI_AGENT_PROM_ADDR")
+	unsetAfter(t, "SINDRI_AGENT_DISK_IO_STATS")
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if cfg.PromAddr != ":9292" {
+		t.Errorf("PromAddr = %q, want %q", cfg.PromAddr, ":9292")
+	}
+	if !cfg.DiskIOStatsEnabled {
+		t.Error("DiskIOStatsEnabled = false, want true")
+	}
+}
+
+func TestLoadFileEnvVarOverridesFileValue(t *testing.T) {
+	path := writeTempConfigFile(t, "agent.yaml", `SINDRI_AGENT_PROM_ADDR: ":9191"`)
+	unsetAfter(t, "SINDRI_AGENT_PROM_ADDR")
+	t.Setenv("SINDRI_AGENT_PROM_ADDR", ":9999")
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if cfg.PromAddr != ":9999" {
+		t.Errorf("PromAddr = %q, want %q (env should win over file)", cfg.PromAddr, ":9999")
+	}
+}
+
+func TestLoadFileRejectsUnsupportedExtension(t *testing.T) {
+	path := writeTempConfigFile(t, "agent.json", `{"SINDRI_AGENT_PROM_ADDR": ":9191"}`)
+
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected an error for an unsupported extension")
+	}
+}
+
+func TestLoadFileRejectsMalformedLine(t *testing.T) {
+	path := writeTempConfigFile(t, "agent.yaml", "this line has no separator\n")
+
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected an error for a malformed line")
+	}
+}
+
+func TestLoadUsesSINDRIConfigFileEnvVar(t *testing.T) {
+	path := writeTempConfigFile(t, "agent.yaml", `SINDRI_AGENT_PROM_ADDR: ":9393"`)
+	unsetAfter(t, "SINDRI_AGENT_PROM_ADDR")
+	t.Setenv("SINDRI_CONFIG_FILE", path)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.PromAddr != ":9393" {
+		t.Errorf("PromAddr = %q, want %q", cfg.PromAddr, ":9393")
+	}
+}
+
+func TestParseFlatKeyValueStripsTrailingComment(t *testing.T) {
+	values, err := parseFlatKeyValue(`SINDRI_AGENT_PROM_ADDR: ":9191" # local prometheus port`, ":")
+	if err != nil {
+		t.Fatalf("parseFlatKeyValue: %v", err)
+	}
+	if got := values["SINDRI_AGENT_PROM_ADDR"]; got != ":9191" {
+		t.Errorf("value = %q, want %q", got, ":9191")
+	}
+}
+
+func TestParseFlatKeyValueStripsTrailingCommentOnUnquotedValue(t *testing.T) {
+	values, err := parseFlatKeyValue("SINDRI_AGENT_PROM_ADDR: 9191 # local prometheus port", ":")
+	if err != nil {
+		t.Fatalf("parseFlatKeyValue: %v", err)
+	}
+	if got := values["SINDRI_AGENT_PROM_ADDR"]; got != "9191" {
+		t.Errorf("value = %q, want %q", got, "9191")
+	}
+}
+
+func TestParseFlatKeyValueRejectsIndentedLine(t *testing.T) {
+	if _, err := parseFlatKeyValue("SINDRI_AGENT_PROM_ADDR: \":9191\"\n  nested: true\n", ":"); err == nil {
+		t.Error("expected an error for an indented (nested) line")
+	}
+}
+
+func TestParseFlatKeyValueRejectsListItem(t *testing.T) {
+	if _, err := parseFlatKeyValue("- one\n- two\n", ":"); err == nil {
+		t.Error("expected an error for a YAML list item")
+	}
+}
+
+func TestParseFlatKeyValueRejectsTOMLTableHeader(t *testing.T) {
+	if _, err := parseFlatKeyValue("[server]\naddr = \":9191\"\n", "="); err == nil {
+		t.Error("expected an error for a TOML table header")
+	}
+}
+
+func TestParseFlatKeyValueRejectsAmbiguousUnquotedHash(t *testing.T) {
+	if _, err := parseFlatKeyValue("SINDRI_AGENT_PROM_ADDR: :9191#not-a-comment", ":"); err == nil {
+		t.Error("expected an error for a '#' not preceded by whitespace")
+	}
+}
+
+func TestParseFlatKeyValueRejectsContentAfterQuotedValue(t *testing.T) {
+	if _, err := parseFlatKeyValue(`SINDRI_AGENT_PROM_ADDR: ":9191" garbage`, ":"); err == nil {
+		t.Error("expected an error for trailing content after a quoted value that isn't a comment")
+	}
+}
+
+func TestLoadWithoutConfigFileLeavesPromAddrEmpty(t *testing.T) {
+	t.Setenv("SINDRI_CONFIG_FILE", "")
+	unsetAfter(t, "SINDRI_AGENT_PROM_ADDR")
+	os.Unsetenv("SINDRI_AGENT_PROM_ADDR")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.PromAddr != "" {
+		t.Errorf("PromAddr = %q, want empty", cfg.PromAddr)
+	}
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"math/rand"
+	"time"
+)
+
+// JitteredMetricsInterval returns MetricsInterval plus a random amount in
+// [0, MetricsInterval*MetricsJitterPct/100), so that a large fleet of agents
+// sharing the same MetricsInterval does not all push metrics at the same
+// instant. rnd is accepted as a parameter (rather than using the package
+// global) so tests can seed it for deterministic, reproducible samples.
+func (c *Config) JitteredMetricsInterval(rnd *rand.Rand) time.Duration {
+	if c.MetricsJitterPct <= 0 {
+		return c.MetricsInterval
+	}
+	maxJitter := int64(c.MetricsInterval) * int64(c.MetricsJitterPct) / 100
+	if maxJitter <= 0 {
+		return c.MetricsInterval
+	}
+	return c.MetricsInterval + time.Duration(rnd.Int63n(maxJitter))
+}
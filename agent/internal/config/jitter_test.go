@@ -0,0 +1,28 @@
+package config
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestJitteredMetricsIntervalDistribution(t *testing.T) {
+	cfg := &Config{MetricsInterval: 60 * time.Second, MetricsJitterPct: 10}
+	rnd := rand.New(rand.NewSource(42))
+
+	min, max := 60*time.Second, 66*time.Second
+	for i := 0; i < 100; i++ {
+		got := cfg.JitteredMetricsInterval(rnd)
+		if got < min || got >= max {
+			t.Fatalf("sample %d: JitteredMetricsInterval() = %s, want in [%s, %s)", i, got, min, max)
+		}
+	}
+}
+
+func TestJitteredMetricsIntervalNoJitter(t *testing.T) {
+	cfg := &Config{MetricsInterval: 60 * time.Second, MetricsJitterPct: 0}
+	rnd := rand.New(rand.NewSource(1))
+	if got := cfg.JitteredMetricsInterval(rnd); got != cfg.MetricsInterval {
+		t.Fatalf("JitteredMetricsInterval() = %s, want %s", got, cfg.MetricsInterval)
+	}
+}
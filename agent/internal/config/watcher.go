@@ -0,0 +1,141 @@
+package config
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+)
+
+// ConfigDelta describes what changed between two successive Load calls
+// during a SIGHUP-triggered reload.
+type ConfigDelta struct {
+	// Changed lists the reloadable field names — currently "Tags" and
+	// "MetricsInterval" — whose value differs between the old and new
+	// Config. Every other field is only read once at startup to
+	// establish the Console connection or a subsystem's initial state
+	// (e.g. ConsoleURL, APIKey), so changing it mid-run without a
+	// restart would leave the agent talking to the wrong Console, or
+	// worse, dropping every active terminal session to reconnect. Of
+	// those, only ConsoleURL and APIKey are compared; they show up in
+	// RestartRequired instead. Changes to any other field are not listed.
+	Changed []string
+
+	// RestartRequired lists field names that differ but were left
+	// unapplied because reloading them safely requires a restart.
+	RestartRequired []string
+
+	// Config is the newly loaded Config, for a receiver that wants the
+	// full picture rather than diffing individual fields itself.
+	Config *Config
+}
+
+// Watcher reloads configuration from the environment on SIGHUP and
+// publishes what changed via Deltas. Only the fields diffConfig reports
+// in ConfigDelta.Changed (Tags and MetricsInterval) are safe to apply to
+// a running agent without restarting it; a change to ConsoleURL or
+// APIKey is logged as requiring a restart and reported in
+// ConfigDelta.RestartRequired. ConfigDelta.Config still carries every
+// newly loaded value, including those.
+type Watcher struct {
+	mu      sync.Mutex
+	current *Config
+
+	sig    chan os.Signal
+	deltas chan ConfigDelta
+	done   chan struct{}
+}
+
+// NewWatcher returns a Watcher that diffs future reloads against
+// current, the Config already in effect (normally the one returned by
+// the Load call that started the agent).
+func NewWatcher(current *Config) *Watcher {
+	return &Watcher{
+		current: current,
+		sig:     make(chan os.Signal, 1),
+		deltas:  make(chan ConfigDelta, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+// Deltas returns the channel Watcher publishes a ConfigDelta to after
+// each SIGHUP-triggered reload. It is buffered by one; a delta that
+// arrives while the previous one is still unread is dropped and logged,
+// rather than blocking the signal-handling goroutine.
+func (w *Watcher) Deltas() <-chan ConfigDelta {
+	return w.deltas
+}
+
+// Start begins listening for SIGHUP in a background goroutine, reloading
+// configuration via Load and publishing a ConfigDelta on Deltas after
+// each signal, until Stop is called.
+func (w *Watcher) Start() {
+	signal.Notify(w.sig, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-w.sig:
+				w.reload()
+			case <-w.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops forwarding SIGHUP to w and halts the background goroutine
+// started by Start.
+func (w *Watcher) Stop() {
+	signal.Stop(w.sig)
+	close(w.done)
+}
+
+func (w *Watcher) reload() {
+	next, err := Load()
+	if err != nil {
+		log.Printf("config: reload on SIGHUP: %v", err)
+		return
+	}
+
+	w.mu.Lock()
+	prev := w.current
+	w.current = next
+	w.mu.Unlock()
+
+	delta := diffConfig(prev, next)
+	for _, field := range delta.RestartRequired {
+		log.Printf("config: SIGHUP reload: %s changed but requires a restart to take effect", field)
+	}
+
+	select {
+	case w.deltas <- delta:
+	default:
+		log.Printf("config: SIGHUP reload: dropped delta, previous one has not been read yet")
+	}
+}
+
+// diffConfig compares prev and next, sorting the fields that differ into
+// delta.Changed (Tags and MetricsInterval, safe to apply live) and
+// delta.RestartRequired (ConsoleURL and APIKey). No other field is
+// compared.
+func diffConfig(prev, next *Config) ConfigDelta {
+	delta := ConfigDelta{Config: next}
+
+	if !reflect.DeepEqual(prev.Tags, next.Tags) {
+		delta.Changed = append(delta.Changed, "Tags")
+	}
+	if prev.MetricsInterval != next.MetricsInterval {
+		delta.Changed = append(delta.Changed, "MetricsInterval")
+	}
+
+	if prev.ConsoleURL != next.ConsoleURL {
+		delta.RestartRequired = append(delta.RestartRequired, "ConsoleURL")
+	}
+	if prev.APIKey != next.APIKey {
+		delta.RestartRequired = append(delta.RestartRequired, "APIKey")
+	}
+
+	return delta
+}
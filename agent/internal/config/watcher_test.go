@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWatcherPublishesDeltaOnSIGHUP(t *testing.T) {
+	unsetAfter(t, "SINDRI_AGENT_METRICS_INTERVAL")
+
+	prev, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	w := NewWatcher(prev)
+	w.Start()
+	defer w.Stop()
+
+	if err := os.Setenv("SINDRI_AGENT_METRICS_INTERVAL", "30s"); err != nil {
+		t.Fatalf("Setenv: %v", err)
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("send SIGHUP: %v", err)
+	}
+
+	select {
+	case delta := <-w.Deltas():
+		if delta.Config.MetricsInterval != 30*time.Second {
+			t.Errorf("delta.Config.MetricsInterval = %s, want 30s", delta.Config.MetricsInterval)
+		}
+		found := false
+		for _, field := range delta.Changed {
+			if field == "MetricsInterval" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("delta.Changed = %v, want it to include MetricsInterval", delta.Changed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a ConfigDelta after SIGHUP")
+	}
+}
+
+func TestDiffConfigReportsRestartRequiredFields(t *testing.T) {
+	prev := &Config{ConsoleURL: "wss://old", APIKey: "old-key"}
+	next := &Config{ConsoleURL: "wss://new", APIKey: "old-key"}
+
+	delta := diffConfig(prev, next)
+
+	if len(delta.Changed) != 0 {
+		t.Errorf("delta.Changed = %v, want none", delta.Changed)
+	}
+	if len(delta.RestartRequired) != 1 || delta.RestartRequired[0] != "ConsoleURL" {
+		t.Errorf("delta.RestartRequired = %v, want [ConsoleURL]", delta.RestartRequired)
+	}
+}
+
+func TestDiffConfigReportsChangedTags(t *testing.T) {
+	prev := &Config{Tags: map[string]string{"role": "web"}}
+	next := &Config{Tags: map[string]string{"role": "worker"}}
+
+	delta := diffConfig(prev, next)
+
+	if len(delta.Changed) != 1 || delta.Changed[0] != "Tags" {
+		t.Errorf("delta.Changed = %v, want [Tags]", delta.Changed)
+	}
+}
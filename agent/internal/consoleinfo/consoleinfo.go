@@ -0,0 +1,104 @@
+// Package consoleinfo tracks the capabilities of the Console the agent is
+// currently connected to, as reported via MsgConsoleInfo, so the agent can
+// gate optional features (e.g. file transfer) on what that Console
+// actually supports.
+package consoleinfo
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+	"github.com/pacphi/sindri/agent/internal/semver"
+)
+
+// Store holds the most recently received MsgConsoleInfo. It is safe for
+// concurrent use.
+type Store struct {
+	mu   sync.RWMutex
+	info protocol.MsgConsoleInfo
+	set  bool
+}
+
+// NewStore returns an empty Store, before any MsgConsoleInfo has been
+// received.
+func NewStore() *Store {
+	return &Store{}
+}
+
+// Apply records msg as the Console's current capabilities, replacing
+// whatever was previously stored. If agentVersion and msg.ConsoleVersion
+// both parse as semantic versions and the Console's is older, Apply logs
+// a warning — an operator running a stale Console is the more actionable
+// side of that mismatch to flag.
+func (s *Store) Apply(msg protocol.MsgConsoleInfo, agentVersion string) {
+	s.mu.Lock()
+	s.info = msg
+	s.set = true
+	s.mu.Unlock()
+
+	if agentVersion == "" || msg.ConsoleVersion == "" {
+		return
+	}
+	agent, err := semver.Parse(agentVersion)
+	if err != nil {
+		return
+	}
+	console, err := semver.Parse(msg.ConsoleVersion)
+	if err != nil {
+		return
+	}
+	if console.LessThan(agent) {
+		log.Printf("consoleinfo: connected Console version %s is older than agent version %s", msg.ConsoleVersion, agentVersion)
+	}
+}
+
+// HasFeature reports whether the Console last reported via Apply
+// advertises name in its SupportedFeatures. It returns false if no
+// MsgConsoleInfo has been received yet.
+func (s *Store) HasFeature(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.set {
+		return false
+	}
+	for _, f := range s.info.SupportedFeatures {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Snapshot returns the most recently applied MsgConsoleInfo, and whether
+// any has been received yet.
+func (s *Store) Snapshot() (protocol.MsgConsoleInfo, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.info, s.set
+}
+
+// FeatureFileTransfer is the SupportedFeatures name gating file transfer
+// requests (MsgFileWriteRequest) on Console support.
+const FeatureFileTransfer = "file_transfer"
+
+// ErrFeatureUnsupported is returned by RequireFeature when the connected
+// Console does not advertise the requested feature.
+type ErrFeatureUnsupported struct {
+	Feature string
+}
+
+func (e *ErrFeatureUnsupported) Error() string {
+	return fmt.Sprintf("consoleinfo: connected Console does not support %q", e.Feature)
+}
+
+// RequireFeature returns an *ErrFeatureUnsupported if the connected
+// Console (per the last-applied MsgConsoleInfo) does not advertise
+// feature, so a caller can gate a request before sending it.
+func (s *Store) RequireFeature(feature string) error {
+	if !s.HasFeature(feature) {
+		return &ErrFeatureUnsupported{Feature: feature}
+	}
+	return nil
+}
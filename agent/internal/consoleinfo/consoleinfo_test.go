@@ -0,0 +1,80 @@
+package consoleinfo
+
+import (
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestApplyStoresCapabilities(t *testing.T) {
+	s := NewStore()
+	s.Apply(protocol.MsgConsoleInfo{
+		ConsoleVersion:      "2.0.0",
+		SupportedFeatures:   []string{"file_transfer", "port_forward"},
+		MaxTerminalSessions: 8,
+	}, "1.0.0")
+
+	got, ok := s.Snapshot()
+	if !ok {
+		t.Fatal("Snapshot() ok = false, want true after Apply")
+	}
+	if got.ConsoleVersion != "2.0.0" || got.MaxTerminalSessions != 8 {
+		t.Errorf("Snapshot() = %+v, want ConsoleVersion=2.0.0 MaxTerminalSessions=8", got)
+	}
+}
+
+func TestSnapshotBeforeApplyReportsUnset(t *testing.T) {
+	s := NewStore()
+	if _, ok := s.Snapshot(); ok {
+		t.Error("Snapshot() ok = true before any MsgConsoleInfo was applied")
+	}
+}
+
+func TestHasFeatureGatesFileTransfer(t *testing.T) {
+	s := NewStore()
+	if s.HasFeature(FeatureFileTransfer) {
+		t.Error("HasFeature(file_transfer) = true before any MsgConsoleInfo was applied")
+	}
+
+	s.Apply(protocol.MsgConsoleInfo{ConsoleVersion: "1.0.0", SupportedFeatures: []string{"port_forward"}}, "1.0.0")
+	if s.HasFeature(FeatureFileTransfer) {
+		t.Error("HasFeature(file_transfer) = true, but the Console did not advertise it")
+	}
+
+	s.Apply(protocol.MsgConsoleInfo{ConsoleVersion: "1.0.0", SupportedFeatures: []string{"file_transfer"}}, "1.0.0")
+	if !s.HasFeature(FeatureFileTransfer) {
+		t.Error("HasFeature(file_transfer) = false, but the Console advertised it")
+	}
+}
+
+func TestRequireFeatureRejectsUnsupportedFeature(t *testing.T) {
+	s := NewStore()
+	s.Apply(protocol.MsgConsoleInfo{ConsoleVersion: "1.0.0", SupportedFeatures: []string{"port_forward"}}, "1.0.0")
+
+	err := s.RequireFeature(FeatureFileTransfer)
+	if err == nil {
+		t.Fatal("RequireFeature(file_transfer) = nil, want an error")
+	}
+	unsupported, ok := err.(*ErrFeatureUnsupported)
+	if !ok {
+		t.Fatalf("RequireFeature error type = %T, want *ErrFeatureUnsupported", err)
+	}
+	if unsupported.Feature != FeatureFileTransfer {
+		t.Errorf("Feature = %q, want %q", unsupported.Feature, FeatureFileTransfer)
+	}
+
+	s.Apply(protocol.MsgConsoleInfo{ConsoleVersion: "1.0.0", SupportedFeatures: []string{FeatureFileTransfer}}, "1.0.0")
+	if err := s.RequireFeature(FeatureFileTransfer); err != nil {
+		t.Errorf("RequireFeature(file_transfer) = %v, want nil once the Console advertises it", err)
+	}
+}
+
+func TestApplyIgnoresUnparseableVersionsForWarning(t *testing.T) {
+	s := NewStore()
+	// Should not panic even though neither version parses as semver.
+	s.Apply(protocol.MsgConsoleInfo{ConsoleVersion: "not-a-version"}, "also-not-a-version")
+	got, ok := s.Snapshot()
+	if !ok || got.ConsoleVersion != "not-a-version" {
+		t.Errorf("Snapshot() = %+v, ok=%v, want ConsoleVersion=not-a-version ok=true", got, ok)
+	}
+}
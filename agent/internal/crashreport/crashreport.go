@@ -0,0 +1,190 @@
+// Package crashreport captures diagnostic data on an unhandled panic and
+// uploads it to the Console before the process exits, so an agent crash in
+// the field isn't a dead end for debugging (SINDRI_AGENT_CORE_DUMP_ON_PANIC).
+package crashreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/config"
+)
+
+// maxLogLines caps how many of the most recent log lines are included in a
+// report, keeping it focused on what happened just before the crash.
+const maxLogLines = 100
+
+// maxReportBytes caps the written report file's size, guarding against an
+// unbounded stack trace or log buffer exhausting disk or the upload.
+const maxReportBytes = 10 * 1024 * 1024
+
+// uploadTimeout bounds how long Upload waits for the Console to accept the
+// report before giving up, so a hung crash-reporting request never delays
+// re-panicking indefinitely.
+const uploadTimeout = 30 * time.Second
+
+// RingWriter is an io.Writer that both forwards every write to an
+// underlying writer and retains the last maxLogLines lines written, for
+// inclusion in a crash Report. It is safe for concurrent use.
+type RingWriter struct {
+	out io.Writer
+
+	mu    sync.Mutex
+	lines []string
+	buf   bytes.Buffer
+}
+
+// NewRingWriter returns a RingWriter that forwards writes to out while
+// retaining the last maxLogLines lines.
+func NewRingWriter(out io.Writer) *RingWriter {
+	return &RingWriter{out: out}
+}
+
+// Write implements io.Writer, forwarding p to the underlying writer and
+// recording any complete lines it contains.
+func (w *RingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// Incomplete line: put it back for the next Write to complete.
+			w.buf.WriteString(line)
+			break
+		}
+		w.record(line)
+	}
+	w.mu.Unlock()
+	return w.out.Write(p)
+}
+
+// record appends line to the ring buffer, evicting the oldest entry once
+// maxLogLines is exceeded.
+func (w *RingWriter) record(line string) {
+	w.lines = append(w.lines, line)
+	if len(w.lines) > maxLogLines {
+		w.lines = w.lines[len(w.lines)-maxLogLines:]
+	}
+}
+
+// Lines returns a copy of the most recently recorded log lines, oldest
+// first.
+func (w *RingWriter) Lines() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.lines...)
+}
+
+// Report is the diagnostic bundle written to disk and uploaded on panic.
+type Report struct {
+	Time       time.Time `json:"time"`
+	Panic      string    `json:"panic"`
+	StackTrace string    `json:"stack_trace"`
+	LogLines   []string  `json:"log_lines"`
+}
+
+// buildReport assembles a Report from a recovered panic value and the
+// RingWriter's recent log lines, truncating the stack trace to at most
+// maxReportBytes. The marshaled report can still exceed maxReportBytes;
+// Write caps it.
+func buildReport(recovered interface{}, logs *RingWriter) Report {
+	r := Report{
+		Time:       time.Now(),
+		Panic:      fmt.Sprint(recovered),
+		StackTrace: string(debug.Stack()),
+		LogLines:   logs.Lines(),
+	}
+	if len(r.StackTrace) > maxReportBytes {
+		r.StackTrace = r.StackTrace[:maxReportBytes]
+	}
+	return r
+}
+
+// Write JSON-marshals report to a private (0600) temp file, capped at
+// maxReportBytes, and returns its path. The caller is responsible for
+// removing it once uploaded.
+func Write(report Report) (string, error) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return "", fmt.Errorf("crashreport: marshal report: %w", err)
+	}
+	if len(data) > maxReportBytes {
+		data = data[:maxReportBytes]
+	}
+
+	f, err := os.CreateTemp(os.TempDir(), "sindri-crash-*.json")
+	if err != nil {
+		return "", fmt.Errorf("crashreport: create temp file: %w", err)
+	}
+	path := f.Name()
+
+	if err := f.Chmod(0o600); err != nil {
+		f.Close()
+		return "", fmt.Errorf("crashreport: chmod temp file: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return "", fmt.Errorf("crashreport: write temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("crashreport: close temp file: %w", err)
+	}
+	return path, nil
+}
+
+// Upload POSTs the report file at path to the Console's crash endpoint for
+// instanceID, bounded by uploadTimeout.
+func Upload(consoleURL, instanceID, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("crashreport: open report: %w", err)
+	}
+	defer f.Close()
+
+	url := consoleURL + "/api/v1/instances/" + instanceID + "/crash"
+	client := &http.Client{Timeout: uploadTimeout}
+
+	req, err := http.NewRequest(http.MethodPost, url, f)
+	if err != nil {
+		return fmt.Errorf("crashreport: build upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("crashreport: upload report: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("crashreport: upload report: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Recover, deferred by the caller, captures a stack trace and recent log
+// output on panic, writes it to a temp file, uploads it to cfg.ConsoleURL,
+// and re-panics with the original value. It is a no-op if no panic is in
+// flight.
+func Recover(cfg *config.Config, logs *RingWriter) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	report := buildReport(recovered, logs)
+	if path, err := Write(report); err != nil {
+		fmt.Fprintf(os.Stderr, "crashreport: %v\n", err)
+	} else if err := Upload(cfg.ConsoleURL, cfg.InstanceID, path); err != nil {
+		fmt.Fprintf(os.Stderr, "crashreport: %v\n", err)
+	}
+
+	panic(recovered)
+}
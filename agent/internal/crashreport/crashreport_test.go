@@ -0,0 +1,127 @@
+package crashreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/config"
+)
+
+func TestRingWriterForwardsAndRetainsLines(t *testing.T) {
+	var out bytes.Buffer
+	w := NewRingWriter(&out)
+
+	for i := 0; i < 3; i++ {
+		if _, err := w.Write([]byte("line\n")); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	if got := out.String(); strings.Count(got, "line\n") != 3 {
+		t.Errorf("forwarded output = %q, want 3 lines", got)
+	}
+	if lines := w.Lines(); len(lines) != 3 {
+		t.Errorf("Lines() = %v, want 3 entries", lines)
+	}
+}
+
+func TestRingWriterEvictsOldestLineBeyondCap(t *testing.T) {
+	w := NewRingWriter(&bytes.Buffer{})
+	for i := 0; i < maxLogLines+10; i++ {
+		if _, err := w.Write([]byte("line\n")); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	if lines := w.Lines(); len(lines) != maxLogLines {
+		t.Errorf("Lines() returned %d entries, want %d", len(lines), maxLogLines)
+	}
+}
+
+func TestWriteProducesReadableReportFile(t *testing.T) {
+	report := buildReport("boom", NewRingWriter(&bytes.Buffer{}))
+
+	path, err := Write(report)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	defer os.Remove(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	var got Report
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.Panic != "boom" {
+		t.Errorf("Panic = %q, want boom", got.Panic)
+	}
+	if got.StackTrace == "" {
+		t.Error("StackTrace is empty")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("file mode = %o, want 0600", perm)
+	}
+}
+
+func TestUploadPostsReportToInstanceCrashEndpoint(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	path, err := Write(buildReport("boom", NewRingWriter(&bytes.Buffer{})))
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	defer os.Remove(path)
+
+	if err := Upload(server.URL, "i-123", path); err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if want := "/api/v1/instances/i-123/crash"; gotPath != want {
+		t.Errorf("upload path = %q, want %q", gotPath, want)
+	}
+}
+
+func TestRecoverReUploadsAndRePanics(t *testing.T) {
+	uploaded := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uploaded <- struct{}{}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{ConsoleURL: server.URL, InstanceID: "i-123"}
+	logs := NewRingWriter(&bytes.Buffer{})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected Recover to re-panic")
+			}
+		}()
+		defer Recover(cfg, logs)
+		panic("boom")
+	}()
+
+	select {
+	case <-uploaded:
+	default:
+		t.Error("expected crash report to be uploaded")
+	}
+}
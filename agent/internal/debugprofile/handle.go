@@ -0,0 +1,31 @@
+package debugprofile
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// ErrDisabled is returned by Handle when the agent has not opted in to
+// on-demand profiling via SINDRI_AGENT_DEBUG_PROFILE_ENABLED.
+var ErrDisabled = fmt.Errorf("debugprofile: profiling is disabled")
+
+// Handle collects the profile requested by req and returns the response to
+// send back to the Console. It returns ErrDisabled if enabled is false.
+func Handle(req protocol.MsgDebugProfileRequest, enabled bool) (protocol.MsgDebugProfileResponse, error) {
+	if !enabled {
+		return protocol.MsgDebugProfileResponse{}, ErrDisabled
+	}
+
+	data, err := Collect(req.Profile, time.Duration(req.DurationMs)*time.Millisecond)
+	if err != nil {
+		return protocol.MsgDebugProfileResponse{}, err
+	}
+
+	return protocol.MsgDebugProfileResponse{
+		RequestID: req.RequestID,
+		Data:      base64.StdEncoding.EncodeToString(data),
+	}, nil
+}
@@ -0,0 +1,35 @@
+package debugprofile
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestHandleDisabledReturnsErrDisabled(t *testing.T) {
+	req := protocol.MsgDebugProfileRequest{RequestID: "r1", Profile: ProfileGoroutine}
+	_, err := Handle(req, false)
+	if !errors.Is(err, ErrDisabled) {
+		t.Fatalf("Handle() error = %v, want ErrDisabled", err)
+	}
+}
+
+func TestHandleReturnsBase64EncodedProfile(t *testing.T) {
+	req := protocol.MsgDebugProfileRequest{RequestID: "r2", Profile: ProfileGoroutine}
+	resp, err := Handle(req, true)
+	if err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if resp.RequestID != "r2" {
+		t.Errorf("RequestID = %q, want r2", resp.RequestID)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(resp.Data)
+	if err != nil {
+		t.Fatalf("decode response data: %v", err)
+	}
+	if len(decoded) < 2 || decoded[0] != 0x1f || decoded[1] != 0x8b {
+		t.Fatalf("decoded profile does not start with gzip magic bytes: %x", decoded[:min(len(decoded), 4)])
+	}
+}
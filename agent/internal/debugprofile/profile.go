@@ -0,0 +1,45 @@
+// Package debugprofile collects on-demand Go pprof profiles for the
+// Console's debug tooling, guarded by SINDRI_AGENT_DEBUG_PROFILE_ENABLED.
+package debugprofile
+
+import (
+	"bytes"
+	"fmt"
+	"runtime/pprof"
+	"time"
+)
+
+const (
+	ProfileHeap      = "heap"
+	ProfileGoroutine = "goroutine"
+	ProfileCPU       = "cpu"
+)
+
+// Collect gathers a pprof profile of the given kind and returns its raw
+// binary encoding. For ProfileCPU, it samples for duration before
+// returning; duration is ignored for the other profile kinds, which are
+// collected immediately.
+func Collect(profile string, duration time.Duration) ([]byte, error) {
+	var buf bytes.Buffer
+
+	switch profile {
+	case ProfileHeap:
+		if err := pprof.WriteHeapProfile(&buf); err != nil {
+			return nil, fmt.Errorf("debugprofile: write heap profile: %w", err)
+		}
+	case ProfileGoroutine:
+		if err := pprof.Lookup("goroutine").WriteTo(&buf, 0); err != nil {
+			return nil, fmt.Errorf("debugprofile: write goroutine profile: %w", err)
+		}
+	case ProfileCPU:
+		if err := pprof.StartCPUProfile(&buf); err != nil {
+			return nil, fmt.Errorf("debugprofile: start cpu profile: %w", err)
+		}
+		time.Sleep(duration)
+		pprof.StopCPUProfile()
+	default:
+		return nil, fmt.Errorf("debugprofile: unknown profile kind %q", profile)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,51 @@
+package debugprofile
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// pprof binary output is gzip-compressed profile.proto data, which always
+// starts with the gzip magic bytes.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+func TestCollectGoroutineProfileIsValidPprofFormat(t *testing.T) {
+	data, err := Collect(ProfileGoroutine, 0)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(data) < len(gzipMagic) || !bytes.Equal(data[:len(gzipMagic)], gzipMagic) {
+		t.Fatalf("goroutine profile does not start with gzip magic bytes: %x", data[:min(len(data), 4)])
+	}
+}
+
+func TestCollectHeapProfileIsValidPprofFormat(t *testing.T) {
+	data, err := Collect(ProfileHeap, 0)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(data) < len(gzipMagic) || !bytes.Equal(data[:len(gzipMagic)], gzipMagic) {
+		t.Fatalf("heap profile does not start with gzip magic bytes: %x", data[:min(len(data), 4)])
+	}
+}
+
+func TestCollectUnknownProfileKind(t *testing.T) {
+	if _, err := Collect("bogus", 0); err == nil {
+		t.Fatal("expected an error for an unknown profile kind")
+	}
+}
+
+func TestCollectCPUProfileRespectsDuration(t *testing.T) {
+	start := time.Now()
+	data, err := Collect(ProfileCPU, 30*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("Collect returned after %s, want at least 30ms", elapsed)
+	}
+	if len(data) < len(gzipMagic) || !bytes.Equal(data[:len(gzipMagic)], gzipMagic) {
+		t.Fatalf("cpu profile does not start with gzip magic bytes: %x", data[:min(len(data), 4)])
+	}
+}
@@ -0,0 +1,142 @@
+// Package dns performs on-demand DNS lookups on the agent's behalf, since
+// how the agent resolves a hostname can differ from how the developer's
+// own machine resolves it (split-horizon DNS, a fleet-specific resolver).
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// lookupTimeout bounds how long a single MsgDNSLookupRequest is allowed to
+// take.
+const lookupTimeout = 5 * time.Second
+
+// Handle resolves req.Hostname as req.RecordType (A, AAAA, MX, TXT, CNAME,
+// or NS) using resolver. A nil resolver uses net.DefaultResolver; tests
+// inject a resolver whose Dial talks to a fake DNS server instead of the
+// real network.
+func Handle(req protocol.MsgDNSLookupRequest, resolver *net.Resolver) (protocol.MsgDNSLookupResponse, error) {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+
+	var resolvedBy string
+	instrumented := instrumentResolver(resolver, &resolvedBy)
+
+	start := time.Now()
+	records, err := lookup(ctx, instrumented, req.Hostname, req.RecordType)
+	duration := time.Since(start)
+	if err != nil {
+		return protocol.MsgDNSLookupResponse{}, fmt.Errorf("dns: lookup %s %s: %w", req.RecordType, req.Hostname, err)
+	}
+
+	return protocol.MsgDNSLookupResponse{
+		RequestID:  req.RequestID,
+		Records:    records,
+		ResolvedBy: resolvedBy,
+		DurationMs: duration.Milliseconds(),
+	}, nil
+}
+
+// instrumentResolver wraps r so that resolvedBy is set to the address of
+// the DNS server actually dialed, giving the Console visibility into which
+// resolver answered the query. It reads the dialed net.Conn's RemoteAddr
+// rather than the requested address, since a custom Dial (as tests inject)
+// may redirect the connection elsewhere.
+//
+// With PreferGo: true, LookupHost dials the A and AAAA lookups
+// concurrently from two goroutines, so the write to *resolvedBy is
+// guarded by a mutex rather than assigned directly.
+func instrumentResolver(r *net.Resolver, resolvedBy *string) *net.Resolver {
+	dial := r.Dial
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	var mu sync.Mutex
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			conn, err := dial(ctx, network, address)
+			if err == nil {
+				mu.Lock()
+				*resolvedBy = conn.RemoteAddr().String()
+				mu.Unlock()
+			}
+			return conn, err
+		},
+	}
+}
+
+// lookup dispatches to the net.Resolver method matching recordType,
+// normalizing each result type to a slice of strings.
+func lookup(ctx context.Context, r *net.Resolver, hostname, recordType string) ([]string, error) {
+	switch recordType {
+	case "A", "AAAA", "":
+		addrs, err := r.LookupHost(ctx, hostname)
+		if err != nil {
+			return nil, err
+		}
+		return filterByFamily(addrs, recordType), nil
+
+	case "MX":
+		mxs, err := r.LookupMX(ctx, hostname)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]string, len(mxs))
+		for i, mx := range mxs {
+			out[i] = fmt.Sprintf("%d %s", mx.Pref, mx.Host)
+		}
+		return out, nil
+
+	case "TXT":
+		return r.LookupTXT(ctx, hostname)
+
+	case "CNAME":
+		cname, err := r.LookupCNAME(ctx, hostname)
+		if err != nil {
+			return nil, err
+		}
+		return []string{cname}, nil
+
+	case "NS":
+		nss, err := r.LookupNS(ctx, hostname)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]string, len(nss))
+		for i, ns := range nss {
+			out[i] = ns.Host
+		}
+		return out, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported record type %q", recordType)
+	}
+}
+
+// filterByFamily narrows the mixed IPv4/IPv6 result of LookupHost down to
+// the family requested. An empty recordType (the wire default) returns
+// every address.
+func filterByFamily(addrs []string, recordType string) []string {
+	if recordType == "" {
+		return addrs
+	}
+	var out []string
+	for _, a := range addrs {
+		isV4 := net.ParseIP(a).To4() != nil
+		if (recordType == "A") == isV4 {
+			out = append(out, a)
+		}
+	}
+	return out
+}
@@ -0,0 +1,129 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// startFakeDNSServer runs a minimal UDP DNS server that answers every A
+// query with ip, regardless of the queried name. It runs until the test
+// ends.
+func startFakeDNSServer(t *testing.T, ip net.IP) *net.UDPAddr {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenUDP: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			resp, ok := buildAResponse(buf[:n], ip)
+			if !ok {
+				continue
+			}
+			conn.WriteToUDP(resp, addr)
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr)
+}
+
+// buildAResponse crafts a DNS response answering query's question with a
+// single A record for ip.
+func buildAResponse(query []byte, ip net.IP) ([]byte, bool) {
+	if len(query) < 12 {
+		return nil, false
+	}
+
+	i := 12
+	for i < len(query) && query[i] != 0 {
+		i += int(query[i]) + 1
+	}
+	if i >= len(query) {
+		return nil, false
+	}
+	qEnd := i + 1 + 4 // null label + QTYPE + QCLASS
+
+	header := make([]byte, 12)
+	copy(header, query[0:2]) // ID
+	header[2], header[3] = 0x81, 0x80
+	header[5] = 1 // QDCOUNT
+	header[7] = 1 // ANCOUNT
+
+	answer := []byte{0xC0, 0x0C, 0x00, 0x01, 0x00, 0x01, 0, 0, 0, 60, 0, 4}
+	answer = append(answer, ip.To4()...)
+
+	resp := append(header, query[12:qEnd]...)
+	resp = append(resp, answer...)
+	return resp, true
+}
+
+func resolverFor(addr *net.UDPAddr) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			return net.Dial("udp", addr.String())
+		},
+	}
+}
+
+func TestHandleResolvesARecordsUsingCustomResolver(t *testing.T) {
+	addr := startFakeDNSServer(t, net.IPv4(93, 184, 216, 34))
+
+	resp, err := Handle(protocol.MsgDNSLookupRequest{
+		RequestID:  "r1",
+		Hostname:   "example.com",
+		RecordType: "A",
+	}, resolverFor(addr))
+	if err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	if resp.RequestID != "r1" {
+		t.Errorf("RequestID = %q, want r1", resp.RequestID)
+	}
+	if len(resp.Records) != 1 || resp.Records[0] != "93.184.216.34" {
+		t.Errorf("Records = %v, want [93.184.216.34]", resp.Records)
+	}
+	if !strings.Contains(resp.ResolvedBy, addr.String()) {
+		t.Errorf("ResolvedBy = %q, want to contain %q", resp.ResolvedBy, addr.String())
+	}
+}
+
+func TestHandleUnsupportedRecordType(t *testing.T) {
+	addr := startFakeDNSServer(t, net.IPv4(93, 184, 216, 34))
+
+	_, err := Handle(protocol.MsgDNSLookupRequest{
+		RequestID:  "r2",
+		Hostname:   "example.com",
+		RecordType: "SRV",
+	}, resolverFor(addr))
+	if err == nil {
+		t.Fatal("expected an error for an unsupported record type")
+	}
+}
+
+func TestFilterByFamily(t *testing.T) {
+	addrs := []string{"93.184.216.34", "2606:2800:220:1:248:1893:25c8:1946"}
+
+	if got := filterByFamily(addrs, "A"); len(got) != 1 || got[0] != "93.184.216.34" {
+		t.Errorf("filterByFamily(A) = %v", got)
+	}
+	if got := filterByFamily(addrs, "AAAA"); len(got) != 1 || got[0] != "2606:2800:220:1:248:1893:25c8:1946" {
+		t.Errorf("filterByFamily(AAAA) = %v", got)
+	}
+	if got := filterByFamily(addrs, ""); len(got) != 2 {
+		t.Errorf("filterByFamily(\"\") = %v, want both addresses", got)
+	}
+}
@@ -0,0 +1,23 @@
+// Package env implements remote environment variable access for
+// MsgEnvGet/MsgEnvSet requests from the Console.
+package env
+
+import "os"
+
+// Get returns the value of the named environment variable and whether it
+// was set.
+func Get(name string) (value string, found bool) {
+	return os.LookupEnv(name)
+}
+
+// Set assigns value to the named environment variable in the agent's own
+// process environment.
+func Set(name, value string) error {
+	return os.Setenv(name, value)
+}
+
+// Unset removes the named environment variable from the agent's own
+// process environment.
+func Unset(name string) error {
+	return os.Unsetenv(name)
+}
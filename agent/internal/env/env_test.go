@@ -0,0 +1,26 @@
+package env
+
+import "testing"
+
+func TestGetSetUnset(t *testing.T) {
+	const name = "SINDRI_TEST_ENV_VAR"
+
+	if _, found := Get(name); found {
+		t.Fatalf("%s unexpectedly set before test", name)
+	}
+
+	if err := Set(name, "hello"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	value, found := Get(name)
+	if !found || value != "hello" {
+		t.Fatalf("Get after Set = (%q, %v), want (\"hello\", true)", value, found)
+	}
+
+	if err := Unset(name); err != nil {
+		t.Fatalf("Unset: %v", err)
+	}
+	if _, found := Get(name); found {
+		t.Fatal("expected variable to be unset")
+	}
+}
@@ -0,0 +1,57 @@
+// Package errorreport turns a local error into a protocol.MsgReportError
+// the agent can send to the Console, deduplicating repeated identical
+// errors so a persistent failure doesn't flood the Console with reports.
+package errorreport
+
+import (
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/idgen"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// dedupeWindow is how long an identical component+code error is
+// suppressed after being reported.
+const dedupeWindow = 60 * time.Second
+
+// Reporter builds MsgReportError values from local errors, suppressing
+// duplicates of the same component+code within dedupeWindow.
+type Reporter struct {
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+}
+
+// NewReporter returns a Reporter ready for use.
+func NewReporter() *Reporter {
+	return &Reporter{lastSent: make(map[string]time.Time)}
+}
+
+// Report returns a MsgReportError describing err, or nil if an identical
+// component+code error was already reported within the last 60 seconds.
+func (r *Reporter) Report(component, code, message string, err error, metadata map[string]string) *protocol.MsgReportError {
+	key := component + ":" + code
+
+	r.mu.Lock()
+	now := time.Now()
+	if last, ok := r.lastSent[key]; ok && now.Sub(last) < dedupeWindow {
+		r.mu.Unlock()
+		return nil
+	}
+	r.lastSent[key] = now
+	r.mu.Unlock()
+
+	if err != nil {
+		message = message + ": " + err.Error()
+	}
+
+	return &protocol.MsgReportError{
+		ErrorID:    idgen.NewID(),
+		Component:  component,
+		Code:       code,
+		Message:    message,
+		StackTrace: string(debug.Stack()),
+		Metadata:   metadata,
+	}
+}
@@ -0,0 +1,52 @@
+package errorreport
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReportSuppressesDuplicateWithinWindow(t *testing.T) {
+	r := NewReporter()
+
+	first := r.Report("metrics", "collect_failed", "collect metrics", errors.New("boom"), nil)
+	if first == nil {
+		t.Fatal("first Report() = nil, want a MsgReportError")
+	}
+
+	second := r.Report("metrics", "collect_failed", "collect metrics", errors.New("boom"), nil)
+	if second != nil {
+		t.Fatalf("second Report() = %+v, want nil (deduped)", second)
+	}
+}
+
+func TestReportDoesNotSuppressDifferentCode(t *testing.T) {
+	r := NewReporter()
+
+	if r.Report("metrics", "collect_failed", "collect metrics", nil, nil) == nil {
+		t.Fatal("Report() for collect_failed = nil, want a MsgReportError")
+	}
+	if r.Report("metrics", "send_failed", "send metrics", nil, nil) == nil {
+		t.Fatal("Report() for send_failed = nil, want a MsgReportError")
+	}
+}
+
+func TestReportIncludesMessageAndMetadata(t *testing.T) {
+	r := NewReporter()
+
+	msg := r.Report("heartbeat", "send_failed", "send heartbeat", errors.New("connection reset"), map[string]string{"attempt": "3"})
+	if msg == nil {
+		t.Fatal("Report() = nil, want a MsgReportError")
+	}
+	if msg.Message != "send heartbeat: connection reset" {
+		t.Errorf("Message = %q, want %q", msg.Message, "send heartbeat: connection reset")
+	}
+	if msg.Metadata["attempt"] != "3" {
+		t.Errorf("Metadata = %v, want attempt=3", msg.Metadata)
+	}
+	if msg.ErrorID == "" {
+		t.Error("ErrorID is empty, want a generated ID")
+	}
+	if msg.StackTrace == "" {
+		t.Error("StackTrace is empty, want a captured stack")
+	}
+}
@@ -0,0 +1,143 @@
+// Package execscript runs a multi-line script through an allowlisted
+// interpreter on behalf of the Console (MsgExecScript), streaming its
+// output back as it runs rather than buffering it all until exit.
+package execscript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// allowedInterpreters lists the only Interpreter values MsgExecScript may
+// request, so the Console can't have the agent exec an arbitrary binary
+// under the guise of an "interpreter".
+var allowedInterpreters = map[string]bool{
+	"bash":    true,
+	"sh":      true,
+	"python3": true,
+	"ruby":    true,
+}
+
+// ErrUnsupportedInterpreter is returned by Handle when req.Interpreter is
+// not in allowedInterpreters.
+var ErrUnsupportedInterpreter = errors.New("execscript: unsupported interpreter")
+
+// defaultTimeout bounds script execution when req.TimeoutMs is zero.
+const defaultTimeout = 5 * time.Minute
+
+// outputChunkSize is the read buffer size for streaming stdout/stderr,
+// chosen to keep individual MsgCommandOutput messages small enough not to
+// dominate the send queue.
+const outputChunkSize = 4096
+
+// OutputSender streams a running script's output to the Console.
+type OutputSender interface {
+	SendCommandOutput(output protocol.MsgCommandOutput) error
+}
+
+// Handle writes req.Script to a private (0600) temp file, executes it
+// through req.Interpreter, streams its stdout and stderr to sender as
+// MsgCommandOutput messages, and removes the temp file before returning.
+// It returns the process's exit code.
+func Handle(ctx context.Context, req protocol.MsgExecScript, sender OutputSender) (int, error) {
+	if !allowedInterpreters[req.Interpreter] {
+		return 0, fmt.Errorf("%w: %q", ErrUnsupportedInterpreter, req.Interpreter)
+	}
+
+	tmpfile, err := os.CreateTemp(os.TempDir(), "sindri-script-*")
+	if err != nil {
+		return 0, fmt.Errorf("execscript: create temp file: %w", err)
+	}
+	tmpPath := tmpfile.Name()
+	defer os.Remove(tmpPath)
+
+	if err := tmpfile.Chmod(0o600); err != nil {
+		tmpfile.Close()
+		return 0, fmt.Errorf("execscript: chmod temp file: %w", err)
+	}
+	if _, err := tmpfile.WriteString(req.Script); err != nil {
+		tmpfile.Close()
+		return 0, fmt.Errorf("execscript: write temp file: %w", err)
+	}
+	if err := tmpfile.Close(); err != nil {
+		return 0, fmt.Errorf("execscript: close temp file: %w", err)
+	}
+
+	timeout := time.Duration(req.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, req.Interpreter, tmpPath)
+	if req.WorkDir != "" {
+		cmd.Dir = req.WorkDir
+	}
+	if len(req.Env) > 0 {
+		cmd.Env = append(os.Environ(), req.Env...)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return 0, fmt.Errorf("execscript: stdout pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return 0, fmt.Errorf("execscript: stderr pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("execscript: start %s %s: %w", req.Interpreter, filepath.Base(tmpPath), err)
+	}
+
+	done := make(chan error, 2)
+	go func() { done <- streamOutput(req.ScriptID, "stdout", stdout, sender) }()
+	go func() { done <- streamOutput(req.ScriptID, "stderr", stderr, sender) }()
+	for i := 0; i < 2; i++ {
+		if err := <-done; err != nil {
+			return 0, err
+		}
+	}
+
+	err = cmd.Wait()
+	if err == nil {
+		return 0, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	return 0, fmt.Errorf("execscript: wait for %s: %w", req.Interpreter, err)
+}
+
+// streamOutput reads r in outputChunkSize chunks, forwarding each as a
+// MsgCommandOutput tagged with stream ("stdout" or "stderr") until EOF. It
+// never closes r — exec.Cmd.Wait does that once the process exits.
+func streamOutput(scriptID, stream string, r io.Reader, sender OutputSender) error {
+	buf := make([]byte, outputChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			chunk := append([]byte(nil), buf[:n]...)
+			if sendErr := sender.SendCommandOutput(protocol.MsgCommandOutput{
+				ScriptID: scriptID,
+				Stream:   stream,
+				Data:     chunk,
+			}); sendErr != nil {
+				return fmt.Errorf("execscript: send %s output: %w", stream, sendErr)
+			}
+		}
+		if err != nil {
+			return nil
+		}
+	}
+}
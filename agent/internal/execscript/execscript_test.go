@@ -0,0 +1,112 @@
+package execscript
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+type recordingOutputSender struct {
+	mu     sync.Mutex
+	stdout bytes.Buffer
+	stderr bytes.Buffer
+}
+
+func (s *recordingOutputSender) SendCommandOutput(output protocol.MsgCommandOutput) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch output.Stream {
+	case "stdout":
+		s.stdout.Write(output.Data)
+	case "stderr":
+		s.stderr.Write(output.Data)
+	}
+	return nil
+}
+
+func TestHandleStreamsStdoutInOrder(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash(1) not available on this system")
+	}
+
+	sender := &recordingOutputSender{}
+	script := "echo one\necho two\necho three\n"
+
+	code, err := Handle(context.Background(), protocol.MsgExecScript{
+		ScriptID:    "s-1",
+		Script:      script,
+		Interpreter: "bash",
+	}, sender)
+	if err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if code != 0 {
+		t.Fatalf("exit code = %d, want 0", code)
+	}
+
+	if want := "one\ntwo\nthree\n"; sender.stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", sender.stdout.String(), want)
+	}
+}
+
+func TestHandleCleansUpTempFile(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash(1) not available on this system")
+	}
+
+	before, err := filepath.Glob(filepath.Join(os.TempDir(), "sindri-script-*"))
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+
+	sender := &recordingOutputSender{}
+	if _, err := Handle(context.Background(), protocol.MsgExecScript{
+		ScriptID:    "s-1",
+		Script:      "echo hi\n",
+		Interpreter: "bash",
+	}, sender); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	after, err := filepath.Glob(filepath.Join(os.TempDir(), "sindri-script-*"))
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(after) != len(before) {
+		t.Errorf("temp files after Handle = %v, want same set as before %v", after, before)
+	}
+}
+
+func TestHandleRejectsUnsupportedInterpreter(t *testing.T) {
+	sender := &recordingOutputSender{}
+	if _, err := Handle(context.Background(), protocol.MsgExecScript{
+		Script:      "echo hi",
+		Interpreter: "perl",
+	}, sender); err == nil {
+		t.Fatal("expected an error for an unsupported interpreter")
+	}
+}
+
+func TestHandleReportsNonZeroExitCode(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash(1) not available on this system")
+	}
+
+	sender := &recordingOutputSender{}
+	code, err := Handle(context.Background(), protocol.MsgExecScript{
+		Script:      "exit 7\n",
+		Interpreter: "bash",
+	}, sender)
+	if err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if code != 7 {
+		t.Errorf("exit code = %d, want 7", code)
+	}
+}
@@ -0,0 +1,147 @@
+package filesystem
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// Manifest walks the directory at dir (resolved against root) and returns
+// a FileSyncEntry for every regular file beneath it, keyed by
+// slash-separated path relative to dir. A dir that does not exist yet
+// yields an empty manifest rather than an error, since a fresh sync
+// destination has nothing to diff against.
+func Manifest(root *Root, dir string) ([]protocol.FileSyncEntry, error) {
+	resolved, err := root.Resolve(dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(resolved); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var entries []protocol.FileSyncEntry
+	walkErr := filepath.Walk(resolved, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(resolved, p)
+		if err != nil {
+			return fmt.Errorf("filesystem: relativize %q: %w", p, err)
+		}
+		sum, err := sha256File(p)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, protocol.FileSyncEntry{
+			Path:   filepath.ToSlash(rel),
+			SHA256: sum,
+			Size:   info.Size(),
+			Mode:   uint32(info.Mode().Perm()),
+		})
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("filesystem: walk %q: %w", dir, walkErr)
+	}
+	return entries, nil
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("filesystem: open %q: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("filesystem: hash %q: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// DiffManifest computes the agent's own manifest for dir and compares it
+// against remote (the Console's manifest from a MsgDirSyncManifest),
+// returning the paths that are missing locally or whose SHA256 differs —
+// the set that must be requested via MsgFileWriteRequest.
+func DiffManifest(root *Root, dir string, remote []protocol.FileSyncEntry) ([]string, error) {
+	local, err := Manifest(root, dir)
+	if err != nil {
+		return nil, err
+	}
+	localByPath := make(map[string]protocol.FileSyncEntry, len(local))
+	for _, e := range local {
+		localByPath[e.Path] = e
+	}
+
+	var need []string
+	for _, e := range remote {
+		existing, ok := localByPath[e.Path]
+		if !ok || existing.SHA256 != e.SHA256 {
+			need = append(need, e.Path)
+		}
+	}
+	return need, nil
+}
+
+// DirSyncTracker tracks in-flight directory syncs by SyncID, so the agent
+// knows once every file requested by a MsgDirSyncRequest has been written
+// and can respond with MsgDirSyncComplete. It is safe for concurrent use.
+type DirSyncTracker struct {
+	mu      sync.Mutex
+	pending map[string]map[string]bool // syncID -> path -> written
+}
+
+// NewDirSyncTracker returns an empty DirSyncTracker.
+func NewDirSyncTracker() *DirSyncTracker {
+	return &DirSyncTracker{pending: make(map[string]map[string]bool)}
+}
+
+// Start records the set of paths needed for syncID (as computed by
+// DiffManifest) and returns the MsgDirSyncRequest to send the Console.
+func (t *DirSyncTracker) Start(syncID string, needFiles []string) protocol.MsgDirSyncRequest {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	files := make(map[string]bool, len(needFiles))
+	for _, p := range needFiles {
+		files[p] = false
+	}
+	t.pending[syncID] = files
+	return protocol.MsgDirSyncRequest{SyncID: syncID, NeedFiles: needFiles}
+}
+
+// MarkWritten records that path has been written for syncID (e.g. after a
+// MsgFileWriteRequest referencing syncID succeeds). Once every file
+// started under syncID has been marked written, it returns a
+// MsgDirSyncComplete and true; otherwise it returns the zero value and
+// false. It is a no-op if syncID or path is not tracked.
+func (t *DirSyncTracker) MarkWritten(syncID, path string) (protocol.MsgDirSyncComplete, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	files, ok := t.pending[syncID]
+	if !ok {
+		return protocol.MsgDirSyncComplete{}, false
+	}
+	if _, tracked := files[path]; tracked {
+		files[path] = true
+	}
+	for _, written := range files {
+		if !written {
+			return protocol.MsgDirSyncComplete{}, false
+		}
+	}
+	delete(t.pending, syncID)
+	return protocol.MsgDirSyncComplete{SyncID: syncID, FilesUpdated: len(files)}, true
+}
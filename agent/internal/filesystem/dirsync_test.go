@@ -0,0 +1,130 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestDiffManifestRequestsOnlyChangedFile(t *testing.T) {
+	dir := t.TempDir()
+	root, err := NewRoot(dir)
+	if err != nil {
+		t.Fatalf("NewRoot: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("unchanged"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("stale"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	local, err := Manifest(root, ".")
+	if err != nil {
+		t.Fatalf("Manifest: %v", err)
+	}
+	if len(local) != 2 {
+		t.Fatalf("Manifest returned %d entries, want 2", len(local))
+	}
+
+	remote := append([]protocol.FileSyncEntry(nil), local...)
+	for i, e := range remote {
+		if e.Path == "b.txt" {
+			remote[i].SHA256 = "different-hash"
+		}
+	}
+
+	need, err := DiffManifest(root, ".", remote)
+	if err != nil {
+		t.Fatalf("DiffManifest: %v", err)
+	}
+	if len(need) != 1 || need[0] != "b.txt" {
+		t.Fatalf("need = %v, want [b.txt]", need)
+	}
+}
+
+func TestDiffManifestRequestsAllFilesForMissingDestination(t *testing.T) {
+	root, err := NewRoot(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewRoot: %v", err)
+	}
+
+	remote := []protocol.FileSyncEntry{{Path: "new.txt", SHA256: "abc"}}
+	need, err := DiffManifest(root, "does-not-exist", remote)
+	if err != nil {
+		t.Fatalf("DiffManifest: %v", err)
+	}
+	if len(need) != 1 || need[0] != "new.txt" {
+		t.Fatalf("need = %v, want [new.txt]", need)
+	}
+}
+
+func TestDirSyncTrackerCompletesOnceAllFilesWritten(t *testing.T) {
+	tracker := NewDirSyncTracker()
+	req := tracker.Start("sync-1", []string{"a.txt", "b.txt"})
+	if len(req.NeedFiles) != 2 {
+		t.Fatalf("NeedFiles = %v, want 2 entries", req.NeedFiles)
+	}
+
+	if _, complete := tracker.MarkWritten("sync-1", "a.txt"); complete {
+		t.Fatal("MarkWritten reported complete after only one of two files written")
+	}
+
+	msg, complete := tracker.MarkWritten("sync-1", "b.txt")
+	if !complete {
+		t.Fatal("MarkWritten did not report complete after all needed files written")
+	}
+	if msg.SyncID != "sync-1" || msg.FilesUpdated != 2 {
+		t.Errorf("msg = %+v, want SyncID=sync-1 FilesUpdated=2", msg)
+	}
+}
+
+func TestDirSyncEndToEndOnlyWritesChangedFile(t *testing.T) {
+	dir := t.TempDir()
+	root, err := NewRoot(dir)
+	if err != nil {
+		t.Fatalf("NewRoot: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("unchanged"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("stale"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	local, err := Manifest(root, ".")
+	if err != nil {
+		t.Fatalf("Manifest: %v", err)
+	}
+	remote := append([]protocol.FileSyncEntry(nil), local...)
+	for i, e := range remote {
+		if e.Path == "b.txt" {
+			remote[i].SHA256 = "different-hash"
+		}
+	}
+
+	need, err := DiffManifest(root, ".", remote)
+	if err != nil {
+		t.Fatalf("DiffManifest: %v", err)
+	}
+
+	tracker := NewDirSyncTracker()
+	tracker.Start("sync-1", need)
+
+	for _, path := range need {
+		if err := WriteFileAtomic(root, path, []byte("fresh"), 0o644); err != nil {
+			t.Fatalf("WriteFileAtomic(%q): %v", path, err)
+		}
+		tracker.MarkWritten("sync-1", path)
+	}
+
+	if got, err := os.ReadFile(filepath.Join(dir, "a.txt")); err != nil || string(got) != "unchanged" {
+		t.Errorf("a.txt = %q, err=%v, want unchanged and untouched", got, err)
+	}
+	if got, err := os.ReadFile(filepath.Join(dir, "b.txt")); err != nil || string(got) != "fresh" {
+		t.Errorf("b.txt = %q, err=%v, want fresh", got, err)
+	}
+}
@@ -0,0 +1,43 @@
+package filesystem
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// ReadRange reads up to length bytes starting at offset from the file at
+// path (resolved against root). A length of 0 reads the remainder of the
+// file from offset, capped at maxBytes. It reports whether the read
+// reached end of file.
+func ReadRange(root *Root, path string, offset, length, maxBytes int64) (data []byte, eof bool, err error) {
+	resolved, err := root.Resolve(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	f, err := os.Open(resolved)
+	if err != nil {
+		return nil, false, fmt.Errorf("filesystem: open: %w", err)
+	}
+	defer f.Close()
+
+	if length <= 0 || length > maxBytes {
+		length = maxBytes
+	}
+
+	buf := make([]byte, length)
+	n, err := f.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		return nil, false, fmt.Errorf("filesystem: read: %w", err)
+	}
+	reachedEOF := err == io.EOF
+
+	if !reachedEOF {
+		if info, statErr := f.Stat(); statErr == nil && offset+int64(n) >= info.Size() {
+			reachedEOF = true
+		}
+	}
+
+	return buf[:n], reachedEOF, nil
+}
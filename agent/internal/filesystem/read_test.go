@@ -0,0 +1,45 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadRangeReturnsExactBytes(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "data.txt")
+	content := []byte("0123456789abcdefghij")
+	if err := os.WriteFile(file, content, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	root, err := NewRoot(dir)
+	if err != nil {
+		t.Fatalf("NewRoot: %v", err)
+	}
+
+	data, eof, err := ReadRange(root, "data.txt", 10, 10, 1024*1024)
+	if err != nil {
+		t.Fatalf("ReadRange: %v", err)
+	}
+	if string(data) != "abcdefghij" {
+		t.Errorf("got %q, want %q", data, "abcdefghij")
+	}
+	if !eof {
+		t.Error("expected eof=true when the range reaches end of file")
+	}
+}
+
+func TestReadRangeRejectsPathTraversal(t *testing.T) {
+	dir := t.TempDir()
+	root, err := NewRoot(dir)
+	if err != nil {
+		t.Fatalf("NewRoot: %v", err)
+	}
+
+	_, _, err = ReadRange(root, "../../etc/passwd", 0, 0, 1024)
+	if err == nil {
+		t.Fatal("expected path traversal to be rejected, got nil error")
+	}
+}
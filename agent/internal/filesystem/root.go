@@ -0,0 +1,51 @@
+// Package filesystem implements remote file access on behalf of the
+// Console, constrained to a configured root directory.
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrPathEscapesRoot is returned when a requested path resolves outside of
+// the configured root.
+var ErrPathEscapesRoot = errors.New("filesystem: path escapes root")
+
+// Root guards filesystem operations to a single base directory, rejecting
+// any path that would traverse outside of it.
+type Root struct {
+	base string
+}
+
+// NewRoot returns a Root rooted at base. An empty base disables the guard,
+// resolving paths against the process's working directory.
+func NewRoot(base string) (*Root, error) {
+	if base == "" {
+		return &Root{}, nil
+	}
+	abs, err := filepath.Abs(base)
+	if err != nil {
+		return nil, fmt.Errorf("filesystem: resolve root: %w", err)
+	}
+	return &Root{base: abs}, nil
+}
+
+// Resolve joins path against the root and verifies the result does not
+// escape it, returning ErrPathEscapesRoot otherwise.
+func (r *Root) Resolve(path string) (string, error) {
+	if r.base == "" {
+		return path, nil
+	}
+	joined := filepath.Join(r.base, path)
+	abs, err := filepath.Abs(joined)
+	if err != nil {
+		return "", fmt.Errorf("filesystem: resolve path: %w", err)
+	}
+	if abs != r.base && !strings.HasPrefix(abs, r.base+string(os.PathSeparator)) {
+		return "", ErrPathEscapesRoot
+	}
+	return abs, nil
+}
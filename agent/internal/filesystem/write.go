@@ -0,0 +1,45 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic writes data to the file at path (resolved against root)
+// by first writing to a temporary file in the same directory, then
+// renaming it into place. This guarantees readers never observe a
+// partially written file, since rename is atomic within a filesystem.
+func WriteFileAtomic(root *Root, path string, data []byte, mode os.FileMode) error {
+	resolved, err := root.Resolve(path)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(resolved)
+	tmp, err := os.CreateTemp(dir, ".sindri-write-*")
+	if err != nil {
+		return fmt.Errorf("filesystem: create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once the rename below succeeds
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("filesystem: write temp file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("filesystem: sync temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("filesystem: close temp file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return fmt.Errorf("filesystem: chmod temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, resolved); err != nil {
+		return fmt.Errorf("filesystem: rename into place: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomicCreatesAndReplaces(t *testing.T) {
+	dir := t.TempDir()
+	root, err := NewRoot(dir)
+	if err != nil {
+		t.Fatalf("NewRoot: %v", err)
+	}
+
+	if err := WriteFileAtomic(root, "config.yml", []byte("v1"), 0o644); err != nil {
+		t.Fatalf("WriteFileAtomic (create): %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "config.yml"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != "v1" {
+		t.Fatalf("got %q, want v1", got)
+	}
+
+	if err := WriteFileAtomic(root, "config.yml", []byte("v2"), 0o644); err != nil {
+		t.Fatalf("WriteFileAtomic (replace): %v", err)
+	}
+	got, err = os.ReadFile(filepath.Join(dir, "config.yml"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != "v2" {
+		t.Fatalf("got %q, want v2", got)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected no leftover temp files, got %d entries", len(entries))
+	}
+}
+
+func TestWriteFileAtomicRejectsPathTraversal(t *testing.T) {
+	dir := t.TempDir()
+	root, err := NewRoot(dir)
+	if err != nil {
+		t.Fatalf("NewRoot: %v", err)
+	}
+	if err := WriteFileAtomic(root, "../escape.txt", []byte("x"), 0o644); err == nil {
+		t.Fatal("expected path traversal write to be rejected")
+	}
+}
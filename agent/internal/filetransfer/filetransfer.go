@@ -0,0 +1,167 @@
+// Package filetransfer streams file uploads and downloads to and from the
+// Console in bounded chunks (MsgFileUploadChunk, MsgFileDownloadChunk),
+// for files too large to send as a single MsgFileWriteRequest or
+// MsgFileReadResponse.
+package filetransfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// ErrTransferTooLarge is returned when an upload's declared size, or a
+// download's source size, exceeds the Handler's configured
+// MaxTransferBytes.
+var ErrTransferTooLarge = errors.New("filetransfer: exceeds maximum transfer size")
+
+// defaultDownloadChunkSize is used when a MsgFileDownloadRequest doesn't
+// specify ChunkSize.
+const defaultDownloadChunkSize = 64 * 1024
+
+// ChunkSender delivers MsgFileDownloadChunk messages as Download reads
+// through a file.
+type ChunkSender interface {
+	SendFileDownloadChunk(chunk protocol.MsgFileDownloadChunk) error
+}
+
+// upload tracks the destination of an in-progress upload.
+type upload struct {
+	dst     io.WriteCloser
+	written int64
+}
+
+// Handler streams uploads to and downloads from destinations the caller
+// supplies, respecting context cancellation and capping total transfer
+// size via MaxTransferBytes. It is safe for concurrent use.
+type Handler struct {
+	// MaxTransferBytes caps the declared size of an upload or the size of
+	// a file requested for download. Zero disables the cap.
+	MaxTransferBytes int64
+
+	mu      sync.Mutex
+	uploads map[string]*upload
+}
+
+// NewHandler returns a Handler that rejects transfers larger than
+// maxTransferBytes (0 disables the cap).
+func NewHandler(maxTransferBytes int64) *Handler {
+	return &Handler{MaxTransferBytes: maxTransferBytes, uploads: make(map[string]*upload)}
+}
+
+// StartUpload begins tracking an upload identified by req.RequestID,
+// writing chunks to dst as WriteChunk delivers them. It returns
+// ErrTransferTooLarge without touching dst if req.SizeBytes exceeds
+// MaxTransferBytes.
+func (h *Handler) StartUpload(req protocol.MsgFileUploadRequest, dst io.WriteCloser) error {
+	if h.MaxTransferBytes > 0 && req.SizeBytes > h.MaxTransferBytes {
+		return fmt.Errorf("filetransfer: %w: %d bytes", ErrTransferTooLarge, req.SizeBytes)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.uploads[req.RequestID] = &upload{dst: dst}
+	return nil
+}
+
+// WriteChunk writes chunk.Data to the destination registered by
+// StartUpload for chunk.RequestID and returns the total bytes written to
+// it so far. It returns an error, without writing, if ctx is already
+// canceled or no upload is being tracked for chunk.RequestID.
+func (h *Handler) WriteChunk(ctx context.Context, chunk protocol.MsgFileUploadChunk) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	h.mu.Lock()
+	u, ok := h.uploads[chunk.RequestID]
+	h.mu.Unlock()
+	if !ok {
+		return 0, fmt.Errorf("filetransfer: no upload in progress for request %q", chunk.RequestID)
+	}
+
+	n, err := u.dst.Write(chunk.Data)
+	u.written += int64(n)
+	if err != nil {
+		return u.written, fmt.Errorf("filetransfer: write chunk: %w", err)
+	}
+	return u.written, nil
+}
+
+// FinishUpload closes the destination registered by StartUpload for
+// requestID, stops tracking the upload, and returns the
+// MsgFileUploadComplete to send the Console. A close error is reported in
+// the returned message's Error field rather than as a Go error, matching
+// MsgFileWriteResponse's convention of surfacing failures to the Console
+// as data rather than aborting the caller's send loop.
+func (h *Handler) FinishUpload(requestID string) (protocol.MsgFileUploadComplete, error) {
+	h.mu.Lock()
+	u, ok := h.uploads[requestID]
+	delete(h.uploads, requestID)
+	h.mu.Unlock()
+	if !ok {
+		return protocol.MsgFileUploadComplete{}, fmt.Errorf("filetransfer: no upload in progress for request %q", requestID)
+	}
+
+	result := protocol.MsgFileUploadComplete{RequestID: requestID, BytesWritten: u.written}
+	if err := u.dst.Close(); err != nil {
+		result.Error = err.Error()
+	}
+	return result, nil
+}
+
+// Download streams src's contents to sender in chunks sized by
+// req.ChunkSize (defaultDownloadChunkSize if zero), stopping early if ctx
+// is canceled or src's total size exceeds MaxTransferBytes.
+func (h *Handler) Download(ctx context.Context, req protocol.MsgFileDownloadRequest, src io.ReadSeeker, sender ChunkSender) error {
+	size, err := src.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("filetransfer: seek end of %q: %w", req.Path, err)
+	}
+	if h.MaxTransferBytes > 0 && size > h.MaxTransferBytes {
+		return fmt.Errorf("filetransfer: %w: %d bytes", ErrTransferTooLarge, size)
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("filetransfer: seek start of %q: %w", req.Path, err)
+	}
+
+	if size == 0 {
+		return sender.SendFileDownloadChunk(protocol.MsgFileDownloadChunk{RequestID: req.RequestID, EOF: true})
+	}
+
+	chunkSize := req.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultDownloadChunkSize
+	}
+
+	buf := make([]byte, chunkSize)
+	var offset int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, readErr := src.Read(buf)
+		if n > 0 {
+			offset += int64(n)
+			if err := sender.SendFileDownloadChunk(protocol.MsgFileDownloadChunk{
+				RequestID: req.RequestID,
+				Offset:    offset - int64(n),
+				Data:      append([]byte(nil), buf[:n]...),
+				EOF:       offset >= size,
+			}); err != nil {
+				return fmt.Errorf("filetransfer: send chunk: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			return fmt.Errorf("filetransfer: read %q: %w", req.Path, readErr)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package filetransfer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// nopCloserBuffer adapts a bytes.Buffer to io.WriteCloser so it can stand
+// in for a real destination file in tests.
+type nopCloserBuffer struct {
+	bytes.Buffer
+	closeErr error
+	closed   bool
+}
+
+func (b *nopCloserBuffer) Close() error {
+	b.closed = true
+	return b.closeErr
+}
+
+type recordingChunkSender struct {
+	chunks []protocol.MsgFileDownloadChunk
+}
+
+func (r *recordingChunkSender) SendFileDownloadChunk(chunk protocol.MsgFileDownloadChunk) error {
+	r.chunks = append(r.chunks, chunk)
+	return nil
+}
+
+func TestUploadRoundTripsAcrossChunks(t *testing.T) {
+	h := NewHandler(0)
+	dst := &nopCloserBuffer{}
+
+	if err := h.StartUpload(protocol.MsgFileUploadRequest{RequestID: "r1", SizeBytes: 11}, dst); err != nil {
+		t.Fatalf("StartUpload: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := h.WriteChunk(ctx, protocol.MsgFileUploadChunk{RequestID: "r1", Data: []byte("hello ")}); err != nil {
+		t.Fatalf("WriteChunk: %v", err)
+	}
+	written, err := h.WriteChunk(ctx, protocol.MsgFileUploadChunk{RequestID: "r1", Offset: 6, Data: []byte("world")})
+	if err != nil {
+		t.Fatalf("WriteChunk: %v", err)
+	}
+	if written != 11 {
+		t.Errorf("WriteChunk total = %d, want 11", written)
+	}
+
+	complete, err := h.FinishUpload("r1")
+	if err != nil {
+		t.Fatalf("FinishUpload: %v", err)
+	}
+	if complete.BytesWritten != 11 || complete.Error != "" {
+		t.Errorf("FinishUpload = %+v, want 11 bytes and no error", complete)
+	}
+	if !dst.closed {
+		t.Error("expected FinishUpload to close the destination")
+	}
+	if dst.String() != "hello world" {
+		t.Errorf("destination content = %q, want %q", dst.String(), "hello world")
+	}
+}
+
+func TestStartUploadRejectsOversizedTransfer(t *testing.T) {
+	h := NewHandler(10)
+	err := h.StartUpload(protocol.MsgFileUploadRequest{RequestID: "r1", SizeBytes: 11}, &nopCloserBuffer{})
+	if !errors.Is(err, ErrTransferTooLarge) {
+		t.Fatalf("StartUpload error = %v, want ErrTransferTooLarge", err)
+	}
+}
+
+func TestWriteChunkFailsWithoutMatchingUpload(t *testing.T) {
+	h := NewHandler(0)
+	if _, err := h.WriteChunk(context.Background(), protocol.MsgFileUploadChunk{RequestID: "missing"}); err == nil {
+		t.Fatal("expected an error writing a chunk with no matching StartUpload")
+	}
+}
+
+func TestWriteChunkFailsWhenContextCanceled(t *testing.T) {
+	h := NewHandler(0)
+	dst := &nopCloserBuffer{}
+	if err := h.StartUpload(protocol.MsgFileUploadRequest{RequestID: "r1"}, dst); err != nil {
+		t.Fatalf("StartUpload: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := h.WriteChunk(ctx, protocol.MsgFileUploadChunk{RequestID: "r1", Data: []byte("x")}); err == nil {
+		t.Fatal("expected WriteChunk to fail with a canceled context")
+	}
+	if dst.Len() != 0 {
+		t.Error("expected no data written when the context is already canceled")
+	}
+}
+
+func TestFinishUploadReportsCloseError(t *testing.T) {
+	h := NewHandler(0)
+	dst := &nopCloserBuffer{closeErr: errors.New("disk full")}
+	if err := h.StartUpload(protocol.MsgFileUploadRequest{RequestID: "r1"}, dst); err != nil {
+		t.Fatalf("StartUpload: %v", err)
+	}
+
+	complete, err := h.FinishUpload("r1")
+	if err != nil {
+		t.Fatalf("FinishUpload: %v", err)
+	}
+	if complete.Error != "disk full" {
+		t.Errorf("Error = %q, want %q", complete.Error, "disk full")
+	}
+}
+
+func TestDownloadStreamsChunksAndMarksFinalEOF(t *testing.T) {
+	h := NewHandler(0)
+	src := bytes.NewReader([]byte("hello world"))
+	sender := &recordingChunkSender{}
+
+	req := protocol.MsgFileDownloadRequest{RequestID: "r1", ChunkSize: 4}
+	if err := h.Download(context.Background(), req, src, sender); err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+
+	if len(sender.chunks) != 3 {
+		t.Fatalf("got %d chunks, want 3", len(sender.chunks))
+	}
+	var reassembled []byte
+	for i, c := range sender.chunks {
+		reassembled = append(reassembled, c.Data...)
+		wantEOF := i == len(sender.chunks)-1
+		if c.EOF != wantEOF {
+			t.Errorf("chunk %d EOF = %v, want %v", i, c.EOF, wantEOF)
+		}
+	}
+	if string(reassembled) != "hello world" {
+		t.Errorf("reassembled = %q, want %q", reassembled, "hello world")
+	}
+}
+
+func TestDownloadRejectsOversizedSource(t *testing.T) {
+	h := NewHandler(5)
+	src := bytes.NewReader([]byte("hello world"))
+	err := h.Download(context.Background(), protocol.MsgFileDownloadRequest{RequestID: "r1"}, src, &recordingChunkSender{})
+	if !errors.Is(err, ErrTransferTooLarge) {
+		t.Fatalf("Download error = %v, want ErrTransferTooLarge", err)
+	}
+}
+
+func TestDownloadStopsWhenContextCanceled(t *testing.T) {
+	h := NewHandler(0)
+	src := bytes.NewReader([]byte("hello world"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := h.Download(ctx, protocol.MsgFileDownloadRequest{RequestID: "r1", ChunkSize: 4}, src, &recordingChunkSender{}); err == nil {
+		t.Fatal("expected Download to fail with a canceled context")
+	}
+}
+
+func TestDownloadOfEmptyFileSendsSingleEOFChunk(t *testing.T) {
+	h := NewHandler(0)
+	src := bytes.NewReader(nil)
+	sender := &recordingChunkSender{}
+
+	if err := h.Download(context.Background(), protocol.MsgFileDownloadRequest{RequestID: "r1"}, src, sender); err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if len(sender.chunks) != 1 || !sender.chunks[0].EOF || len(sender.chunks[0].Data) != 0 {
+		t.Errorf("chunks = %+v, want a single empty EOF chunk", sender.chunks)
+	}
+}
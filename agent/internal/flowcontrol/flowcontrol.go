@@ -0,0 +1,103 @@
+// Package flowcontrol implements credit-based output throttling for a
+// downstream sender, so a fast PTY producer cannot grow the Console's
+// input buffer unboundedly.
+package flowcontrol
+
+import "sync"
+
+// Sender delivers a chunk of session-tagged output downstream, e.g. by
+// writing it to the active transport. terminal.OutputSender satisfies
+// this interface structurally.
+type Sender interface {
+	SendOutput(sessionID string, data []byte) error
+}
+
+// pending is one buffered, not-yet-sent chunk awaiting send credit.
+type pending struct {
+	sessionID string
+	data      []byte
+}
+
+// Window gates output sent to an underlying Sender by a byte credit that
+// the Console opens via MsgFlowControl. Data sent while the window is
+// exhausted is buffered, up to maxBufferBytes, rather than dropped, and is
+// flushed in order as credit is opened. A maxBufferBytes of 0 means
+// unbounded buffering.
+type Window struct {
+	mu             sync.Mutex
+	next           Sender
+	available      int
+	buffered       []pending
+	bufferedBytes  int
+	maxBufferBytes int
+}
+
+// NewWindow returns a Window with zero initial send credit, delivering
+// flushed output to next.
+func NewWindow(next Sender, maxBufferBytes int) *Window {
+	return &Window{next: next, maxBufferBytes: maxBufferBytes}
+}
+
+// SendOutput implements Sender. If the window has enough credit, data is
+// forwarded to next immediately; otherwise as much as fits is forwarded
+// and the remainder is buffered for a later Open.
+func (w *Window) SendOutput(sessionID string, data []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.enqueueLocked(sessionID, data)
+	err := w.flushLocked()
+	w.trimLocked()
+	return err
+}
+
+// Open adds n bytes of send credit (e.g. from a Console MsgFlowControl)
+// and flushes as much of the buffer as the new credit allows.
+func (w *Window) Open(n int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.available += n
+	return w.flushLocked()
+}
+
+func (w *Window) enqueueLocked(sessionID string, data []byte) {
+	w.buffered = append(w.buffered, pending{sessionID: sessionID, data: data})
+	w.bufferedBytes += len(data)
+}
+
+// trimLocked enforces maxBufferBytes on what is still buffered after a
+// flush, so bytes the current credit can send are never dropped.
+func (w *Window) trimLocked() {
+	// Drop the oldest buffered bytes once over capacity — stale output is
+	// less useful than making room for what is arriving now.
+	for w.maxBufferBytes > 0 && w.bufferedBytes > w.maxBufferBytes && len(w.buffered) > 0 {
+		oldest := &w.buffered[0]
+		overBy := w.bufferedBytes - w.maxBufferBytes
+		if overBy >= len(oldest.data) {
+			w.bufferedBytes -= len(oldest.data)
+			w.buffered = w.buffered[1:]
+			continue
+		}
+		oldest.data = oldest.data[overBy:]
+		w.bufferedBytes -= overBy
+	}
+}
+
+func (w *Window) flushLocked() error {
+	for w.available > 0 && len(w.buffered) > 0 {
+		head := &w.buffered[0]
+		send := head.data
+		if len(send) > w.available {
+			send = send[:w.available]
+		}
+		if err := w.next.SendOutput(head.sessionID, send); err != nil {
+			return err
+		}
+		w.available -= len(send)
+		w.bufferedBytes -= len(send)
+		head.data = head.data[len(send):]
+		if len(head.data) == 0 {
+			w.buffered = w.buffered[1:]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package flowcontrol
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+// recordingSender accumulates every byte handed to SendOutput, in order.
+type recordingSender struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (r *recordingSender) SendOutput(_ string, data []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buf.Write(data)
+	return nil
+}
+
+func (r *recordingSender) Bytes() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]byte(nil), r.buf.Bytes()...)
+}
+
+func TestWindowBuffersBeyondCreditAndFlushesOnOpen(t *testing.T) {
+	rec := &recordingSender{}
+	w := NewWindow(rec, 1024)
+
+	if err := w.Open(100); err != nil {
+		t.Fatalf("Open(100): %v", err)
+	}
+
+	payload := bytes.Repeat([]byte("x"), 500)
+	if err := w.SendOutput("sess-1", payload); err != nil {
+		t.Fatalf("SendOutput: %v", err)
+	}
+	if got := len(rec.Bytes()); got != 100 {
+		t.Fatalf("sent after 100-byte window = %d bytes, want 100", got)
+	}
+
+	if err := w.Open(400); err != nil {
+		t.Fatalf("Open(400): %v", err)
+	}
+	if got := len(rec.Bytes()); got != 500 {
+		t.Fatalf("sent after opening remaining credit = %d bytes, want 500", got)
+	}
+	if !bytes.Equal(rec.Bytes(), payload) {
+		t.Fatalf("sent bytes = %q, want %q", rec.Bytes(), payload)
+	}
+}
+
+func TestWindowDropsOldestBufferedBytesBeyondCapacity(t *testing.T) {
+	rec := &recordingSender{}
+	w := NewWindow(rec, 10)
+
+	if err := w.SendOutput("sess-1", bytes.Repeat([]byte("a"), 8)); err != nil {
+		t.Fatalf("SendOutput: %v", err)
+	}
+	if err := w.SendOutput("sess-1", bytes.Repeat([]byte("b"), 8)); err != nil {
+		t.Fatalf("SendOutput: %v", err)
+	}
+	if got := w.bufferedBytes; got != 10 {
+		t.Fatalf("bufferedBytes = %d, want 10 (capped)", got)
+	}
+
+	if err := w.Open(10); err != nil {
+		t.Fatalf("Open(10): %v", err)
+	}
+	// Only the most recent 10 buffered bytes survive eviction: 2 "a"s
+	// followed by 8 "b"s.
+	if got, want := string(rec.Bytes()), "aabbbbbbbb"; got != want {
+		t.Fatalf("sent = %q, want %q", got, want)
+	}
+}
+
+func TestSendOutputForwardsImmediatelyWhenCreditAvailable(t *testing.T) {
+	rec := &recordingSender{}
+	w := NewWindow(rec, 0)
+
+	if err := w.Open(5); err != nil {
+		t.Fatalf("Open(5): %v", err)
+	}
+	if err := w.SendOutput("sess-1", []byte("hello")); err != nil {
+		t.Fatalf("SendOutput: %v", err)
+	}
+	if got := rec.Bytes(); string(got) != "hello" {
+		t.Fatalf("sent = %q, want %q", got, "hello")
+	}
+}
@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/metrics"
+	"github.com/pacphi/sindri/agent/internal/registration"
+	"github.com/pacphi/sindri/agent/internal/websocket"
+)
+
+// defaultRegistrationMaxAge is used by RegistrationHealthChecker when
+// MaxAge is zero.
+const defaultRegistrationMaxAge = 5 * time.Minute
+
+// WebSocketHealthChecker reports healthy only while Client has an active
+// connection to the Console.
+type WebSocketHealthChecker struct {
+	Client *websocket.Client
+}
+
+// Check implements HealthChecker.
+func (c WebSocketHealthChecker) Check(ctx context.Context) error {
+	if status := c.Client.Status(); status != websocket.StateConnected {
+		return fmt.Errorf("health: websocket status is %q, want %q", status, websocket.StateConnected)
+	}
+	return nil
+}
+
+// RegistrationHealthChecker reports healthy only while Registrar's most
+// recent successful registration is within MaxAge.
+type RegistrationHealthChecker struct {
+	Registrar *registration.Registrar
+
+	// MaxAge is the oldest a last-successful-registration timestamp may
+	// be before Check fails. Defaults to defaultRegistrationMaxAge if
+	// zero.
+	MaxAge time.Duration
+}
+
+// Check implements HealthChecker.
+func (c RegistrationHealthChecker) Check(ctx context.Context) error {
+	maxAge := c.MaxAge
+	if maxAge <= 0 {
+		maxAge = defaultRegistrationMaxAge
+	}
+
+	last := c.Registrar.LastSuccess()
+	if last.IsZero() {
+		return fmt.Errorf("health: no successful registration yet")
+	}
+	if age := time.Since(last); age > maxAge {
+		return fmt.Errorf("health: last successful registration was %s ago, exceeds max age %s", age, maxAge)
+	}
+	return nil
+}
+
+// MetricsHealthChecker reports healthy only while Collector's most recent
+// successful collection is within 2x Interval.
+type MetricsHealthChecker struct {
+	Collector *metrics.Collector
+
+	// Interval is the configured metrics push interval (e.g.
+	// config.Config.MetricsInterval).
+	Interval time.Duration
+}
+
+// Check implements HealthChecker.
+func (c MetricsHealthChecker) Check(ctx context.Context) error {
+	maxAge := 2 * c.Interval
+
+	last := c.Collector.LastCollected()
+	if last.IsZero() {
+		return fmt.Errorf("health: no successful metrics collection yet")
+	}
+	if age := time.Since(last); age > maxAge {
+		return fmt.Errorf("health: last metrics collection was %s ago, exceeds max age %s", age, maxAge)
+	}
+	return nil
+}
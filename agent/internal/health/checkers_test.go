@@ -0,0 +1,46 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/metrics"
+	"github.com/pacphi/sindri/agent/internal/registration"
+	"github.com/pacphi/sindri/agent/internal/websocket"
+)
+
+func TestWebSocketHealthCheckerFailsWhenDisconnected(t *testing.T) {
+	c := WebSocketHealthChecker{Client: websocket.NewClient()}
+	if err := c.Check(context.Background()); err == nil {
+		t.Fatal("expected an error for a never-dialed client")
+	}
+}
+
+func TestRegistrationHealthCheckerFailsWithNoSuccess(t *testing.T) {
+	c := RegistrationHealthChecker{Registrar: registration.NewRegistrar(nil)}
+	if err := c.Check(context.Background()); err == nil {
+		t.Fatal("expected an error when no registration has ever succeeded")
+	}
+}
+
+func TestRegistrationHealthCheckerPassesAfterRecentSuccess(t *testing.T) {
+	r := registration.NewRegistrar(func(ctx context.Context, payload registration.RegistrationPayload, idempotencyKey string) error {
+		return nil
+	})
+	if err := r.Run(context.Background(), registration.RegistrationPayload{}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	c := RegistrationHealthChecker{Registrar: r, MaxAge: time.Minute}
+	if err := c.Check(context.Background()); err != nil {
+		t.Fatalf("Check() = %v, want nil", err)
+	}
+}
+
+func TestMetricsHealthCheckerFailsWithNoCollection(t *testing.T) {
+	c := MetricsHealthChecker{Collector: metrics.NewCollector(), Interval: time.Minute}
+	if err := c.Check(context.Background()); err == nil {
+		t.Fatal("expected an error when no metrics have ever been collected")
+	}
+}
@@ -0,0 +1,18 @@
+package health
+
+import "net/http"
+
+// Handler returns an http.HandlerFunc suitable for mounting at "/readyz":
+// it responds 200 if checker.Check succeeds, or 503 with the error message
+// as the body otherwise.
+func Handler(checker HealthChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := checker.Check(r.Context()); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}
+}
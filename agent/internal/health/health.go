@@ -0,0 +1,31 @@
+// Package health defines composable readiness checks for the agent,
+// wired into an operator-facing HTTP endpoint.
+package health
+
+import "context"
+
+// HealthChecker reports whether some aspect of the agent is healthy.
+type HealthChecker interface {
+	Check(ctx context.Context) error
+}
+
+// HealthCheckerFunc adapts a plain function to a HealthChecker.
+type HealthCheckerFunc func(ctx context.Context) error
+
+// Check calls f.
+func (f HealthCheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Aggregate returns a HealthChecker that runs each of checkers in order and
+// returns the first error encountered, or nil if all pass.
+func Aggregate(checkers ...HealthChecker) HealthChecker {
+	return HealthCheckerFunc(func(ctx context.Context) error {
+		for _, c := range checkers {
+			if err := c.Check(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
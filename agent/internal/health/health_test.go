@@ -0,0 +1,60 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeChecker struct {
+	err error
+}
+
+func (c fakeChecker) Check(ctx context.Context) error {
+	return c.err
+}
+
+func TestAggregateReturnsFirstFailingCheckerError(t *testing.T) {
+	wantErr := errors.New("second checker failed")
+	agg := Aggregate(
+		fakeChecker{},
+		fakeChecker{err: wantErr},
+		fakeChecker{err: errors.New("should never be reached")},
+	)
+
+	if err := agg.Check(context.Background()); !errors.Is(err, wantErr) {
+		t.Fatalf("Aggregate.Check() = %v, want %v", err, wantErr)
+	}
+}
+
+func TestAggregatePassesWhenAllCheckersPass(t *testing.T) {
+	agg := Aggregate(fakeChecker{}, fakeChecker{})
+	if err := agg.Check(context.Background()); err != nil {
+		t.Fatalf("Aggregate.Check() = %v, want nil", err)
+	}
+}
+
+func TestHandlerReturnsServiceUnavailableOnFailure(t *testing.T) {
+	h := Handler(fakeChecker{err: errors.New("not ready")})
+
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != 503 {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
+
+func TestHandlerReturnsOKOnSuccess(t *testing.T) {
+	h := Handler(fakeChecker{})
+
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != 200 {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,264 @@
+// Package heartbeat builds the periodic liveness payload the agent sends
+// to the Console.
+package heartbeat
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/metrics"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+const (
+	defaultDiskPressurePct   = 90.0
+	defaultMemoryPressurePct = 85.0
+
+	// ClockSkewWarnThreshold is how far the agent's clock may drift from
+	// the Console's before a caller should log a warning. Skew this large
+	// usually means the instance's NTP sync is broken, which can also
+	// throw off anything else that trusts wall-clock timestamps (TLS
+	// certificate validation, log correlation, WAL replay).
+	ClockSkewWarnThreshold = 5 * time.Second
+)
+
+// HeartbeatPayload tells the Console the agent is alive, plus lightweight
+// summary flags dashboards can render directly without parsing the full
+// MetricsPayload.
+type HeartbeatPayload struct {
+	Timestamp time.Time `json:"timestamp"`
+
+	// DiskPressure is true when disk usage exceeds the configured
+	// threshold (see WithDiskPressurePct).
+	DiskPressure bool `json:"disk_pressure"`
+
+	// MemoryPressure is true when memory usage exceeds the configured
+	// threshold (see WithMemoryPressurePct).
+	MemoryPressure bool `json:"memory_pressure"`
+
+	// Environment identifies the deployment environment this agent is
+	// running in (e.g. "production", "staging"), so operators can
+	// distinguish them without relying on tags.
+	Environment string `json:"environment,omitempty"`
+
+	// ClockSkewMs is the agent's most recently estimated clock offset
+	// from the Console, in milliseconds (positive means the agent's
+	// clock is behind). Zero until the first MsgHeartbeatACK is recorded
+	// via Manager.RecordHeartbeatACK.
+	ClockSkewMs int64 `json:"clock_skew_ms"`
+
+	// Annotations holds Console-driven instance labels applied via
+	// MsgAnnotateInstance (see annotations.Store), separate from
+	// registration.RegistrationPayload.Tags. Nil if none are set or none
+	// have been configured via WithAnnotationsSource.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// MetricsSummary carries a lightweight snapshot of the agent's most
+	// recent metrics, letting the Console skip processing a standalone
+	// MsgMetrics for quick-overview dashboards. Nil unless enabled via
+	// WithIncludeMetricsSummary (SINDRI_AGENT_HEARTBEAT_INCLUDE_METRICS).
+	MetricsSummary *HeartbeatMetrics `json:"metrics_summary,omitempty"`
+
+	// Labels holds Console-driven instance labels applied via
+	// MsgInstanceLabel (see labels.Store), separate from both
+	// Annotations and registration.RegistrationPayload.Tags. Nil if none
+	// are set or none have been configured via WithLabelsSource.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// HeartbeatMetrics is a lightweight summary of a MetricsPayload, embedded
+// in a HeartbeatPayload when metrics summaries are enabled.
+type HeartbeatMetrics struct {
+	CPUPercent     float64 `json:"cpu_percent"`
+	MemPercent     float64 `json:"mem_percent"`
+	DiskMaxPercent float64 `json:"disk_max_percent"`
+}
+
+// Manager builds HeartbeatPayloads on demand from the agent's most recent
+// metrics snapshot.
+type Manager struct {
+	metricsSource     func() *metrics.MetricsPayload
+	diskPressurePct   float64
+	memoryPressurePct float64
+	environment       string
+	annotationsSource func() map[string]string
+	labelsSource      func() map[string]string
+	includeMetrics    bool
+	ackTimeout        time.Duration
+
+	mu        sync.Mutex
+	clockSkew time.Duration
+
+	missedACKs atomic.Uint64
+}
+
+// ManagerOption configures a Manager constructed via NewManager.
+type ManagerOption func(*Manager)
+
+// WithMetricsSource sets the function the Manager calls to obtain the most
+// recent MetricsPayload snapshot when computing pressure flags.
+func WithMetricsSource(fn func() *metrics.MetricsPayload) ManagerOption {
+	return func(m *Manager) { m.metricsSource = fn }
+}
+
+// WithDiskPressurePct overrides the disk-usage percentage (0-100) above
+// which DiskPressure is reported true. Defaults to 90.
+func WithDiskPressurePct(pct float64) ManagerOption {
+	return func(m *Manager) { m.diskPressurePct = pct }
+}
+
+// WithMemoryPressurePct overrides the memory-usage percentage (0-100)
+// above which MemoryPressure is reported true. Defaults to 85.
+func WithMemoryPressurePct(pct float64) ManagerOption {
+	return func(m *Manager) { m.memoryPressurePct = pct }
+}
+
+// WithEnvironment sets the deployment environment reported in every
+// HeartbeatPayload built by the Manager.
+func WithEnvironment(environment string) ManagerOption {
+	return func(m *Manager) { m.environment = environment }
+}
+
+// WithAnnotationsSource sets the function the Manager calls to obtain the
+// current set of Console-driven instance annotations (see
+// annotations.Store.Snapshot) when building a HeartbeatPayload.
+func WithAnnotationsSource(fn func() map[string]string) ManagerOption {
+	return func(m *Manager) { m.annotationsSource = fn }
+}
+
+// WithLabelsSource sets the function the Manager calls to obtain the
+// current set of Console-driven instance labels (see labels.Store.Snapshot)
+// when building a HeartbeatPayload.
+func WithLabelsSource(fn func() map[string]string) ManagerOption {
+	return func(m *Manager) { m.labelsSource = fn }
+}
+
+// WithIncludeMetricsSummary makes Build populate MetricsSummary from the
+// configured metrics source (see WithMetricsSource), letting the Console
+// skip a standalone MsgMetrics for quick-overview dashboards.
+func WithIncludeMetricsSummary(include bool) ManagerOption {
+	return func(m *Manager) { m.includeMetrics = include }
+}
+
+// WithACKTimeout makes WaitForACK wait up to d for a matching AckPayload
+// before counting the heartbeat as missed (see MissedCount). Zero (the
+// default) disables ACK tracking entirely: WaitForACK always reports
+// success without blocking, preserving the fire-and-forget behavior
+// heartbeats have always had.
+func WithACKTimeout(d time.Duration) ManagerOption {
+	return func(m *Manager) { m.ackTimeout = d }
+}
+
+// NewManager returns a Manager configured with opts.
+func NewManager(opts ...ManagerOption) *Manager {
+	m := &Manager{
+		diskPressurePct:   defaultDiskPressurePct,
+		memoryPressurePct: defaultMemoryPressurePct,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Build returns a HeartbeatPayload reflecting the Manager's current
+// metrics snapshot. Pressure flags are left false if no metrics source is
+// configured or it has not yet produced a snapshot.
+func (m *Manager) Build() HeartbeatPayload {
+	payload := HeartbeatPayload{
+		Timestamp:   time.Now(),
+		Environment: m.environment,
+		ClockSkewMs: m.ClockSkew().Milliseconds(),
+	}
+	if m.annotationsSource != nil {
+		payload.Annotations = m.annotationsSource()
+	}
+	if m.labelsSource != nil {
+		payload.Labels = m.labelsSource()
+	}
+	if m.metricsSource == nil {
+		return payload
+	}
+
+	snapshot := m.metricsSource()
+	if snapshot == nil {
+		return payload
+	}
+
+	var diskPct, memPct float64
+	if snapshot.DiskTotalBytes > 0 {
+		diskPct = float64(snapshot.DiskUsedBytes) / float64(snapshot.DiskTotalBytes) * 100
+		payload.DiskPressure = diskPct > m.diskPressurePct
+	}
+	if snapshot.MemoryTotalBytes > 0 {
+		memPct = float64(snapshot.MemoryUsedBytes) / float64(snapshot.MemoryTotalBytes) * 100
+		payload.MemoryPressure = memPct > m.memoryPressurePct
+	}
+	if m.includeMetrics {
+		payload.MetricsSummary = &HeartbeatMetrics{
+			CPUPercent:     snapshot.CPUPercent,
+			MemPercent:     memPct,
+			DiskMaxPercent: diskPct,
+		}
+	}
+	return payload
+}
+
+// RecordHeartbeatACK computes the agent's clock skew from ack and rtt (the
+// round-trip time between sending the heartbeat and receiving ack, used to
+// estimate the one-way network delay as rtt/2), stores it for ClockSkew
+// and future Build calls, and returns it. The caller is responsible for
+// logging a warning if the returned skew exceeds ClockSkewWarnThreshold in
+// magnitude — this package never logs on its own.
+func (m *Manager) RecordHeartbeatACK(ack protocol.MsgHeartbeatACK, rtt time.Duration) time.Duration {
+	skew := ack.ServerTimestamp.Sub(ack.ClientTimestamp) - rtt/2
+
+	m.mu.Lock()
+	m.clockSkew = skew
+	m.mu.Unlock()
+
+	return skew
+}
+
+// ClockSkew returns the clock skew computed by the most recent call to
+// RecordHeartbeatACK, or zero if none has been recorded yet.
+func (m *Manager) ClockSkew() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.clockSkew
+}
+
+// WaitForACK blocks for up to the Manager's configured ACK timeout (see
+// WithACKTimeout) for a protocol.AckPayload matching seqNum to arrive on
+// acks, returning true as soon as one does. If none arrives before the
+// timeout, it increments the missed-heartbeat counter (see MissedCount)
+// and returns false. With no ACK timeout configured (the default),
+// WaitForACK returns true immediately without reading from acks at all,
+// so callers that don't care about delivery confirmation pay nothing for
+// this.
+func (m *Manager) WaitForACK(seqNum uint64, acks <-chan protocol.AckPayload) bool {
+	if m.ackTimeout <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(m.ackTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case ack := <-acks:
+			if ack.SeqNum == seqNum {
+				return true
+			}
+		case <-timer.C:
+			m.missedACKs.Add(1)
+			return false
+		}
+	}
+}
+
+// MissedCount returns the number of heartbeats for which WaitForACK timed
+// out waiting for an acknowledgment.
+func (m *Manager) MissedCount() uint64 {
+	return m.missedACKs.Load()
+}
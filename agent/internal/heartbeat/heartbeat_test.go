@@ -0,0 +1,240 @@
+package heartbeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/annotations"
+	"github.com/pacphi/sindri/agent/internal/labels"
+	"github.com/pacphi/sindri/agent/internal/metrics"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestBuildFlagsDiskPressureAboveThreshold(t *testing.T) {
+	m := NewManager(WithMetricsSource(func() *metrics.MetricsPayload {
+		return &metrics.MetricsPayload{
+			DiskUsedBytes:    95,
+			DiskTotalBytes:   100,
+			MemoryUsedBytes:  10,
+			MemoryTotalBytes: 100,
+		}
+	}))
+
+	payload := m.Build()
+	if !payload.DiskPressure {
+		t.Error("expected DiskPressure to be true at 95% disk usage")
+	}
+	if payload.MemoryPressure {
+		t.Error("expected MemoryPressure to be false at 10% memory usage")
+	}
+}
+
+func TestBuildFlagsMemoryPressureAboveThreshold(t *testing.T) {
+	m := NewManager(WithMetricsSource(func() *metrics.MetricsPayload {
+		return &metrics.MetricsPayload{
+			DiskUsedBytes:    10,
+			DiskTotalBytes:   100,
+			MemoryUsedBytes:  90,
+			MemoryTotalBytes: 100,
+		}
+	}))
+
+	payload := m.Build()
+	if payload.DiskPressure {
+		t.Error("expected DiskPressure to be false at 10% disk usage")
+	}
+	if !payload.MemoryPressure {
+		t.Error("expected MemoryPressure to be true at 90% memory usage")
+	}
+}
+
+func TestBuildRespectsCustomThresholds(t *testing.T) {
+	m := NewManager(
+		WithMetricsSource(func() *metrics.MetricsPayload {
+			return &metrics.MetricsPayload{DiskUsedBytes: 60, DiskTotalBytes: 100}
+		}),
+		WithDiskPressurePct(50),
+	)
+
+	if payload := m.Build(); !payload.DiskPressure {
+		t.Error("expected DiskPressure to be true with a 50% threshold and 60% usage")
+	}
+}
+
+func TestBuildWithoutMetricsSourceReportsNoPressure(t *testing.T) {
+	m := NewManager()
+	payload := m.Build()
+	if payload.DiskPressure || payload.MemoryPressure {
+		t.Error("expected no pressure flags without a configured metrics source")
+	}
+}
+
+func TestRecordHeartbeatACKComputesClockSkew(t *testing.T) {
+	m := NewManager()
+	client := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	server := client.Add(10 * time.Second)
+	rtt := 4 * time.Second // half-RTT of 2s should be subtracted out
+
+	skew := m.RecordHeartbeatACK(protocol.MsgHeartbeatACK{
+		InstanceID:      "i-1",
+		ClientTimestamp: client,
+		ServerTimestamp: server,
+	}, rtt)
+
+	want := 8 * time.Second
+	if skew != want {
+		t.Errorf("RecordHeartbeatACK skew = %s, want %s", skew, want)
+	}
+	if got := m.ClockSkew(); got != want {
+		t.Errorf("ClockSkew() = %s, want %s", got, want)
+	}
+}
+
+func TestBuildIncludesRecordedClockSkew(t *testing.T) {
+	m := NewManager()
+	client := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	server := client.Add(3 * time.Second)
+	m.RecordHeartbeatACK(protocol.MsgHeartbeatACK{ClientTimestamp: client, ServerTimestamp: server}, 0)
+
+	if payload := m.Build(); payload.ClockSkewMs != 3000 {
+		t.Errorf("ClockSkewMs = %d, want 3000", payload.ClockSkewMs)
+	}
+}
+
+func TestBuildIncludesAnnotationsWhileLive(t *testing.T) {
+	store := annotations.NewStore()
+	store.Set("maintenance", "true", time.Minute)
+
+	m := NewManager(WithAnnotationsSource(store.Snapshot))
+
+	payload := m.Build()
+	if payload.Annotations["maintenance"] != "true" {
+		t.Fatalf("Annotations = %v, want maintenance=true", payload.Annotations)
+	}
+}
+
+func TestBuildOmitsExpiredAnnotations(t *testing.T) {
+	store := annotations.NewStore()
+	store.Set("maintenance", "true", -time.Second)
+
+	m := NewManager(WithAnnotationsSource(store.Snapshot))
+
+	payload := m.Build()
+	if len(payload.Annotations) != 0 {
+		t.Fatalf("Annotations = %v, want empty after expiry", payload.Annotations)
+	}
+}
+
+func TestBuildIncludesLabelsAndReflectsDeletes(t *testing.T) {
+	store := labels.NewStore()
+	store.Set(map[string]string{"team": "infra", "job": "active"})
+
+	m := NewManager(WithLabelsSource(store.Snapshot))
+
+	payload := m.Build()
+	if payload.Labels["team"] != "infra" || payload.Labels["job"] != "active" {
+		t.Fatalf("Labels = %v, want team=infra job=active", payload.Labels)
+	}
+
+	store.Delete([]string{"job"})
+
+	payload = m.Build()
+	if _, ok := payload.Labels["job"]; ok {
+		t.Errorf("Labels still has %q after delete: %v", "job", payload.Labels)
+	}
+	if payload.Labels["team"] != "infra" {
+		t.Errorf("Labels lost unrelated key: %v", payload.Labels)
+	}
+}
+
+func TestBuildIncludesMetricsSummaryWhenEnabled(t *testing.T) {
+	m := NewManager(
+		WithIncludeMetricsSummary(true),
+		WithMetricsSource(func() *metrics.MetricsPayload {
+			return &metrics.MetricsPayload{
+				CPUPercent:       42.5,
+				DiskUsedBytes:    50,
+				DiskTotalBytes:   100,
+				MemoryUsedBytes:  25,
+				MemoryTotalBytes: 100,
+			}
+		}),
+	)
+
+	payload := m.Build()
+	if payload.MetricsSummary == nil {
+		t.Fatal("MetricsSummary = nil, want populated")
+	}
+	if payload.MetricsSummary.CPUPercent != 42.5 {
+		t.Errorf("CPUPercent = %v, want 42.5", payload.MetricsSummary.CPUPercent)
+	}
+	if payload.MetricsSummary.MemPercent != 25 {
+		t.Errorf("MemPercent = %v, want 25", payload.MetricsSummary.MemPercent)
+	}
+	if payload.MetricsSummary.DiskMaxPercent != 50 {
+		t.Errorf("DiskMaxPercent = %v, want 50", payload.MetricsSummary.DiskMaxPercent)
+	}
+}
+
+func TestBuildOmitsMetricsSummaryWhenDisabled(t *testing.T) {
+	m := NewManager(WithMetricsSource(func() *metrics.MetricsPayload {
+		return &metrics.MetricsPayload{CPUPercent: 42.5}
+	}))
+
+	if payload := m.Build(); payload.MetricsSummary != nil {
+		t.Fatalf("MetricsSummary = %+v, want nil when not enabled", payload.MetricsSummary)
+	}
+}
+
+func TestWaitForACKSucceedsImmediatelyWithoutTimeoutConfigured(t *testing.T) {
+	m := NewManager()
+	acks := make(chan protocol.AckPayload)
+
+	if !m.WaitForACK(1, acks) {
+		t.Error("expected WaitForACK to succeed immediately when no ACK timeout is configured")
+	}
+	if got := m.MissedCount(); got != 0 {
+		t.Errorf("MissedCount() = %d, want 0", got)
+	}
+}
+
+func TestWaitForACKSucceedsOnMatchingSeqNum(t *testing.T) {
+	m := NewManager(WithACKTimeout(time.Second))
+	acks := make(chan protocol.AckPayload, 1)
+	acks <- protocol.AckPayload{SeqNum: 7, OrigType: protocol.MsgTypeHeartbeatACK}
+
+	if !m.WaitForACK(7, acks) {
+		t.Error("expected WaitForACK to succeed on a matching AckPayload")
+	}
+	if got := m.MissedCount(); got != 0 {
+		t.Errorf("MissedCount() = %d, want 0", got)
+	}
+}
+
+func TestWaitForACKIgnoresMismatchedSeqNumUntilTimeout(t *testing.T) {
+	m := NewManager(WithACKTimeout(20 * time.Millisecond))
+	acks := make(chan protocol.AckPayload, 1)
+	acks <- protocol.AckPayload{SeqNum: 99, OrigType: protocol.MsgTypeHeartbeatACK}
+
+	if m.WaitForACK(1, acks) {
+		t.Error("expected WaitForACK to fail when only a mismatched SeqNum arrives")
+	}
+	if got := m.MissedCount(); got != 1 {
+		t.Errorf("MissedCount() = %d, want 1", got)
+	}
+}
+
+func TestWaitForACKTimesOutAndIncrementsMissedCount(t *testing.T) {
+	m := NewManager(WithACKTimeout(10 * time.Millisecond))
+	acks := make(chan protocol.AckPayload)
+
+	if m.WaitForACK(1, acks) {
+		t.Error("expected WaitForACK to fail when no AckPayload arrives before the timeout")
+	}
+	if m.WaitForACK(2, acks) {
+		t.Error("expected a second timed-out WaitForACK to also fail")
+	}
+	if got := m.MissedCount(); got != 2 {
+		t.Errorf("MissedCount() = %d, want 2", got)
+	}
+}
@@ -0,0 +1,61 @@
+// Package idgen generates the session, script, and idempotency-key IDs
+// used across the agent, so every caller gets the same ID format and
+// length limits instead of rolling its own.
+package idgen
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// MaxIDLength is the longest string IsValidID accepts, guarding a
+// caller-supplied ID (e.g. a MsgTerminalInput's SessionID) from consuming
+// unbounded memory or storage before it's ever looked up.
+const MaxIDLength = 128
+
+// shortIDAlphabet is base62: digits, uppercase, then lowercase, so
+// NewShortID's output is alphanumeric only. It includes look-alike
+// characters such as 0/O and 1/I/l.
+const shortIDAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// shortIDLength is NewShortID's fixed output length.
+const shortIDLength = 8
+
+// NewID returns a random UUID v4 string, suitable for session IDs, script
+// IDs, and idempotency keys. It panics if the system's entropy source is
+// unavailable — there is no sensible fallback ID to return instead, and
+// every caller of NewID needs the result to be genuinely unpredictable.
+func NewID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("idgen: read random bytes: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// NewShortID returns an 8-character base62 string, suitable for
+// human-readable session IDs where a full UUID would be unwieldy to type
+// or read aloud. It panics under the same conditions as NewID.
+func NewShortID() string {
+	raw := make([]byte, shortIDLength)
+	if _, err := rand.Read(raw); err != nil {
+		panic(fmt.Sprintf("idgen: read random bytes: %v", err))
+	}
+
+	id := make([]byte, shortIDLength)
+	for i, v := range raw {
+		id[i] = shortIDAlphabet[int(v)%len(shortIDAlphabet)]
+	}
+	return string(id)
+}
+
+// IsValidID reports whether s is fit to accept as a caller-supplied ID: a
+// non-empty string no longer than MaxIDLength. It does not require s to
+// have been produced by NewID or NewShortID — callers may supply their own
+// session IDs — only that it's bounded.
+func IsValidID(s string) bool {
+	return s != "" && len(s) <= MaxIDLength
+}
@@ -0,0 +1,51 @@
+package idgen
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var uuidV4Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewIDProducesValidUUIDv4(t *testing.T) {
+	id := NewID()
+	if !uuidV4Pattern.MatchString(id) {
+		t.Errorf("NewID() = %q, does not match UUID v4 format", id)
+	}
+}
+
+func TestNewIDIsUnique(t *testing.T) {
+	if NewID() == NewID() {
+		t.Error("two calls to NewID() produced the same value")
+	}
+}
+
+func TestNewShortIDIsEightBase62Chars(t *testing.T) {
+	id := NewShortID()
+	if len(id) != 8 {
+		t.Fatalf("len(NewShortID()) = %d, want 8", len(id))
+	}
+	const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	for _, r := range id {
+		if !strings.ContainsRune(alphabet, r) {
+			t.Errorf("NewShortID() = %q contains non-base62 character %q", id, r)
+		}
+	}
+}
+
+func TestIsValidIDAcceptsReasonableIDs(t *testing.T) {
+	for _, id := range []string{"a", NewID(), NewShortID(), strings.Repeat("x", MaxIDLength)} {
+		if !IsValidID(id) {
+			t.Errorf("IsValidID(%q) = false, want true", id)
+		}
+	}
+}
+
+func TestIsValidIDRejectsEmptyOrOverLong(t *testing.T) {
+	for _, id := range []string{"", strings.Repeat("x", MaxIDLength+1)} {
+		if IsValidID(id) {
+			t.Errorf("IsValidID(%q) = true, want false", id)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Package labels holds Console-driven instance labels applied at runtime
+// via MsgInstanceLabel. Unlike registration.RegistrationPayload tags (set
+// at registration time) or Config.Tags (configuration-time values),
+// labels are purely in-memory and never persisted to the registration
+// cache, so they don't survive an agent restart.
+package labels
+
+import (
+	"sync"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// Store holds a set of runtime instance labels. It is safe for concurrent
+// use.
+type Store struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+// NewStore returns an empty Store.
+func NewStore() *Store {
+	return &Store{values: make(map[string]string)}
+}
+
+// Set merges set into the store, overwriting any existing values for the
+// same keys.
+func (s *Store) Set(set map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range set {
+		s.values[k] = v
+	}
+}
+
+// Delete removes each of keys from the store, if present.
+func (s *Store) Delete(keys []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, k := range keys {
+		delete(s.values, k)
+	}
+}
+
+// Apply merges msg.Set into s, then removes msg.Delete keys.
+func (s *Store) Apply(msg protocol.MsgInstanceLabel) {
+	s.Set(msg.Set)
+	s.Delete(msg.Delete)
+}
+
+// Snapshot returns every current label as a plain map, or nil if none are
+// set.
+func (s *Store) Snapshot() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.values) == 0 {
+		return nil
+	}
+	snapshot := make(map[string]string, len(s.values))
+	for k, v := range s.values {
+		snapshot[k] = v
+	}
+	return snapshot
+}
@@ -0,0 +1,33 @@
+package labels
+
+import (
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestApplyMergesAndDeletes(t *testing.T) {
+	s := NewStore()
+	s.Apply(protocol.MsgInstanceLabel{Set: map[string]string{"team": "infra", "job": "active"}})
+
+	snapshot := s.Snapshot()
+	if snapshot["team"] != "infra" || snapshot["job"] != "active" {
+		t.Fatalf("Snapshot() = %v, want team=infra job=active", snapshot)
+	}
+
+	s.Apply(protocol.MsgInstanceLabel{Delete: []string{"job"}})
+	snapshot = s.Snapshot()
+	if _, ok := snapshot["job"]; ok {
+		t.Errorf("Snapshot() still has %q: %v", "job", snapshot)
+	}
+	if snapshot["team"] != "infra" {
+		t.Errorf("Snapshot() lost unrelated key: %v", snapshot)
+	}
+}
+
+func TestSnapshotReturnsNilWhenEmpty(t *testing.T) {
+	s := NewStore()
+	if snapshot := s.Snapshot(); snapshot != nil {
+		t.Errorf("Snapshot() = %v, want nil", snapshot)
+	}
+}
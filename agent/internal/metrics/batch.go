@@ -0,0 +1,34 @@
+package metrics
+
+// BatchTracker accumulates MetricsPayloads and groups them into
+// fixed-size batches (see Config.MetricsPushBatchSize), letting the agent
+// trade off latency for fewer, larger messages on poor network
+// connections. Since a batch is flushed as soon as it reaches its
+// configured size, no payload waits longer than size metrics-interval
+// ticks before being sent.
+type BatchTracker struct {
+	size    int
+	pending []*MetricsPayload
+}
+
+// NewBatchTracker returns a BatchTracker that flushes every size payloads.
+// A size below 1 is treated as 1 (no batching).
+func NewBatchTracker(size int) *BatchTracker {
+	if size < 1 {
+		size = 1
+	}
+	return &BatchTracker{size: size}
+}
+
+// Add appends payload to the pending batch. Once the batch reaches its
+// configured size, Add returns the accumulated payloads and true,
+// resetting the pending batch; otherwise it returns (nil, false).
+func (t *BatchTracker) Add(payload *MetricsPayload) ([]*MetricsPayload, bool) {
+	t.pending = append(t.pending, payload)
+	if len(t.pending) < t.size {
+		return nil, false
+	}
+	batch := t.pending
+	t.pending = nil
+	return batch, true
+}
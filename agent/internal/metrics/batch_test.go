@@ -0,0 +1,30 @@
+package metrics
+
+import "testing"
+
+func TestBatchTrackerFlushesAtConfiguredSize(t *testing.T) {
+	tracker := NewBatchTracker(3)
+
+	for i := 0; i < 2; i++ {
+		if batch, ready := tracker.Add(&MetricsPayload{}); ready {
+			t.Fatalf("Add() #%d = (%v, true), want not ready yet", i+1, batch)
+		}
+	}
+
+	batch, ready := tracker.Add(&MetricsPayload{})
+	if !ready || len(batch) != 3 {
+		t.Fatalf("Add() #3 = (%v, %v), want a batch of 3", batch, ready)
+	}
+
+	if _, ready := tracker.Add(&MetricsPayload{}); ready {
+		t.Fatal("Add() after flush = ready, want the pending batch to have reset")
+	}
+}
+
+func TestNewBatchTrackerTreatsSizeBelowOneAsOne(t *testing.T) {
+	tracker := NewBatchTracker(0)
+	batch, ready := tracker.Add(&MetricsPayload{})
+	if !ready || len(batch) != 1 {
+		t.Fatalf("Add() = (%v, %v), want an immediate batch of 1", batch, ready)
+	}
+}
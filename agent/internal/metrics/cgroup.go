@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// defaultCgroupRoot is where the kernel mounts the unified cgroup v2
+// hierarchy on essentially every modern Linux distribution.
+const defaultCgroupRoot = "/sys/fs/cgroup"
+
+// CgroupMetrics reports the cgroup v2 CPU quota and memory limit this
+// process is confined to, which on containerized platforms (Fly.io,
+// Kubernetes, Docker) can be far smaller than the host's total resources.
+type CgroupMetrics struct {
+	CPUQuotaPercent float64 `json:"cpu_quota_percent"`
+	MemLimitBytes   uint64  `json:"mem_limit_bytes"`
+	MemUsedBytes    uint64  `json:"mem_used_bytes"`
+}
+
+// collectCgroup reads cgroup v2 limits from the default cgroup root. It
+// returns a nil CgroupMetrics and no error on a host without cgroup v2
+// (e.g. a bare-metal or non-Linux install, or one still on cgroup v1) —
+// this is a normal, expected environment rather than a collection failure.
+func collectCgroup() (*CgroupMetrics, error) {
+	return collectCgroupAt(defaultCgroupRoot)
+}
+
+// collectCgroupAt is a test seam: it reads cgroup v2 limits from root
+// instead of the real /sys/fs/cgroup.
+func collectCgroupAt(root string) (*CgroupMetrics, error) {
+	cpuQuotaPercent, ok, err := readCPUMax(filepath.Join(root, "cpu.max"))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	memLimit, err := readCgroupMemoryValue(filepath.Join(root, "memory.max"))
+	if err != nil {
+		return nil, err
+	}
+	memUsed, err := readCgroupMemoryValue(filepath.Join(root, "memory.current"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &CgroupMetrics{
+		CPUQuotaPercent: cpuQuotaPercent,
+		MemLimitBytes:   memLimit,
+		MemUsedBytes:    memUsed,
+	}, nil
+}
+
+// readCPUMax parses cpu.max, formatted as "$QUOTA $PERIOD" in microseconds,
+// or "max $PERIOD" when the cgroup has no CPU quota. It returns ok=false if
+// path doesn't exist, so callers can distinguish "no cgroup v2 here" from
+// "cgroup v2 is present but unlimited".
+func readCPUMax(path string) (percent float64, ok bool, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("metrics: read %s: %w", path, err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return 0, false, fmt.Errorf("metrics: parse %s: expected 2 fields, got %d", path, len(fields))
+	}
+	if fields[0] == "max" {
+		return 0, true, nil
+	}
+	quota, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("metrics: parse %s quota: %w", path, err)
+	}
+	period, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("metrics: parse %s period: %w", path, err)
+	}
+	if period == 0 {
+		return 0, true, nil
+	}
+	return quota / period * 100, true, nil
+}
+
+// readCgroupMemoryValue parses a cgroup v2 memory interface file
+// (memory.max, memory.current), which holds either a byte count or the
+// literal "max" for no limit. It returns 0 for both "max" and a missing
+// file, since a missing memory.current on an otherwise-valid cgroup v2
+// mount should not fail the whole collection.
+func readCgroupMemoryValue(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("metrics: read %s: %w", path, err)
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("metrics: parse %s: %w", path, err)
+	}
+	return n, nil
+}
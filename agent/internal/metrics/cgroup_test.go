@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeCgroupFile(t *testing.T, dir, name, contents string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
+
+func TestCollectCgroupAtReturnsNilWithoutCgroupV2(t *testing.T) {
+	dir := t.TempDir()
+	metrics, err := collectCgroupAt(dir)
+	if err != nil {
+		t.Fatalf("collectCgroupAt: %v", err)
+	}
+	if metrics != nil {
+		t.Errorf("expected nil CgroupMetrics without cpu.max, got %+v", metrics)
+	}
+}
+
+func TestCollectCgroupAtParsesQuotaAndMemory(t *testing.T) {
+	dir := t.TempDir()
+	writeCgroupFile(t, dir, "cpu.max", "200000 100000\n")
+	writeCgroupFile(t, dir, "memory.max", "1073741824\n")
+	writeCgroupFile(t, dir, "memory.current", "536870912\n")
+
+	got, err := collectCgroupAt(dir)
+	if err != nil {
+		t.Fatalf("collectCgroupAt: %v", err)
+	}
+	if got == nil {
+		t.Fatal("expected non-nil CgroupMetrics")
+	}
+	if got.CPUQuotaPercent != 200 {
+		t.Errorf("CPUQuotaPercent = %v, want 200", got.CPUQuotaPercent)
+	}
+	if got.MemLimitBytes != 1073741824 {
+		t.Errorf("MemLimitBytes = %v, want 1073741824", got.MemLimitBytes)
+	}
+	if got.MemUsedBytes != 536870912 {
+		t.Errorf("MemUsedBytes = %v, want 536870912", got.MemUsedBytes)
+	}
+}
+
+func TestCollectCgroupAtHandlesUnlimitedCPUAndMemory(t *testing.T) {
+	dir := t.TempDir()
+	writeCgroupFile(t, dir, "cpu.max", "max 100000\n")
+	writeCgroupFile(t, dir, "memory.max", "max\n")
+	writeCgroupFile(t, dir, "memory.current", "104857600\n")
+
+	got, err := collectCgroupAt(dir)
+	if err != nil {
+		t.Fatalf("collectCgroupAt: %v", err)
+	}
+	if got == nil {
+		t.Fatal("expected non-nil CgroupMetrics")
+	}
+	if got.CPUQuotaPercent != 0 {
+		t.Errorf("CPUQuotaPercent = %v, want 0 (unlimited)", got.CPUQuotaPercent)
+	}
+	if got.MemLimitBytes != 0 {
+		t.Errorf("MemLimitBytes = %v, want 0 (unlimited)", got.MemLimitBytes)
+	}
+	if got.MemUsedBytes != 104857600 {
+		t.Errorf("MemUsedBytes = %v, want 104857600", got.MemUsedBytes)
+	}
+}
+
+func TestCollectCgroupAtToleratesMissingMemoryCurrent(t *testing.T) {
+	dir := t.TempDir()
+	writeCgroupFile(t, dir, "cpu.max", "100000 100000\n")
+	writeCgroupFile(t, dir, "memory.max", "268435456\n")
+
+	got, err := collectCgroupAt(dir)
+	if err != nil {
+		t.Fatalf("collectCgroupAt: %v", err)
+	}
+	if got == nil {
+		t.Fatal("expected non-nil CgroupMetrics")
+	}
+	if got.MemUsedBytes != 0 {
+		t.Errorf("MemUsedBytes = %v, want 0 without memory.current", got.MemUsedBytes)
+	}
+}
+
+func TestCollectCgroupAtRejectsMalformedCPUMax(t *testing.T) {
+	dir := t.TempDir()
+	writeCgroupFile(t, dir, "cpu.max", "not-a-number 100000\n")
+
+	if _, err := collectCgroupAt(dir); err == nil {
+		t.Error("expected an error for malformed cpu.max")
+	}
+}
@@ -0,0 +1,241 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultCPUSampleDuration is used when a Collector is constructed via
+// NewCollector rather than NewCollectorWithCPUSample.
+const defaultCPUSampleDuration = 200 * time.Millisecond
+
+// defaultHistorySize is used unless overridden via WithHistorySize — 60
+// snapshots is 1 hour of history at the default 1/min collection rate.
+const defaultHistorySize = 60
+
+// Collector gathers a MetricsPayload from the host on demand.
+type Collector struct {
+	networkRates      *NetworkRateCollector
+	cpuSampleDuration time.Duration
+	diskIO            *DiskIOCollector
+	topProcesses      *TopProcessCollector
+	plugins           []MetricsPlugin
+	historySize       int
+
+	mu            sync.Mutex
+	lastCollected time.Time
+	history       []*MetricsPayload // ring buffer, oldest first; see History
+}
+
+// CollectorOption configures a Collector constructed via NewCollector or
+// NewCollectorWithCPUSample.
+type CollectorOption func(*Collector)
+
+// WithHistorySize overrides how many recent MetricsPayload snapshots
+// Collector.History can return. Defaults to defaultHistorySize.
+func WithHistorySize(n int) CollectorOption {
+	return func(c *Collector) { c.historySize = n }
+}
+
+// NewCollector returns a ready-to-use Collector using the default CPU
+// sampling window.
+func NewCollector(opts ...CollectorOption) *Collector {
+	return NewCollectorWithCPUSample(defaultCPUSampleDuration, opts...)
+}
+
+// NewCollectorWithCPUSample returns a Collector that samples CPU
+// utilization over the given window on each Collect call. A larger window
+// smooths out short spikes at the cost of making Collect block longer;
+// SINDRI_AGENT_CPU_SAMPLE_DURATION controls this in production.
+func NewCollectorWithCPUSample(cpuSampleDuration time.Duration, opts ...CollectorOption) *Collector {
+	c := &Collector{
+		networkRates:      NewNetworkRateCollector(),
+		cpuSampleDuration: cpuSampleDuration,
+		historySize:       defaultHistorySize,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// EnableDiskIOStats turns on per-device disk I/O latency, queue depth, and
+// utilization collection (SINDRI_AGENT_DISK_IO_STATS). It is off by
+// default since it requires an extra /proc/diskstats read on every
+// Collect call.
+func (c *Collector) EnableDiskIOStats() {
+	c.diskIO = NewDiskIOCollector()
+}
+
+// EnableTopProcesses turns on reporting of the n most CPU-hungry processes
+// on the host (SINDRI_AGENT_TOP_PROCESSES). It is off by default since
+// enumerating every process on the host adds real overhead on systems with
+// thousands of them.
+func (c *Collector) EnableTopProcesses(n int) {
+	c.topProcesses = NewTopProcessCollector(n)
+}
+
+// SetNetworkInterfaceAllowlist restricts the per-interface breakdown in
+// each Collect call's NetworkRate to the named interfaces
+// (SINDRI_AGENT_NET_INTERFACES). An empty allowlist includes every
+// interface.
+func (c *Collector) SetNetworkInterfaceAllowlist(names []string) {
+	c.networkRates.SetInterfaceAllowlist(names)
+}
+
+// LastCollected returns the time of c's most recent successful Collect
+// call, or the zero time if none has ever succeeded.
+func (c *Collector) LastCollected() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastCollected
+}
+
+// RegisterPlugin adds p to the set of MetricsPlugins consulted on every
+// Collect call. Plugins are consulted in registration order.
+func (c *Collector) RegisterPlugin(p MetricsPlugin) {
+	c.plugins = append(c.plugins, p)
+}
+
+// Collect gathers a single MetricsPayload snapshot. It blocks for the
+// configured CPU sample duration while measuring CPU utilization.
+func (c *Collector) Collect() (*MetricsPayload, error) {
+	cpuPercent, err := collectCPU(c.cpuSampleDuration)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := c.networkRates.Collect()
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &MetricsPayload{
+		Timestamp:   time.Now().UTC(),
+		CPUPercent:  cpuPercent,
+		NetworkRate: &rates,
+	}
+
+	if c.diskIO != nil {
+		ioStats, err := c.diskIO.Collect()
+		if err != nil {
+			return nil, err
+		}
+		payload.IOStats = ioStats
+	}
+
+	if c.topProcesses != nil {
+		topProcesses, err := c.topProcesses.Collect()
+		if err != nil {
+			return nil, err
+		}
+		payload.TopProcesses = topProcesses
+	}
+
+	gpus, err := collectGPU()
+	if err != nil {
+		return nil, err
+	}
+	payload.GPU = gpus
+
+	cgroup, err := collectCgroup()
+	if err != nil {
+		return nil, err
+	}
+	payload.Cgroup = cgroup
+
+	c.collectPlugins(payload)
+
+	c.mu.Lock()
+	c.lastCollected = time.Now()
+	c.recordHistoryLocked(payload)
+	c.mu.Unlock()
+
+	return payload, nil
+}
+
+// recordHistoryLocked appends payload to c.history, evicting the oldest
+// entry once historySize is exceeded. Callers must hold c.mu.
+func (c *Collector) recordHistoryLocked(payload *MetricsPayload) {
+	if c.historySize <= 0 {
+		return
+	}
+	c.history = append(c.history, payload)
+	if len(c.history) > c.historySize {
+		c.history = c.history[len(c.history)-c.historySize:]
+	}
+}
+
+// History returns the most recent n MetricsPayload snapshots (or all
+// available, if n exceeds the number collected so far), newest first.
+func (c *Collector) History(n int) []*MetricsPayload {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if n > len(c.history) {
+		n = len(c.history)
+	}
+	result := make([]*MetricsPayload, n)
+	for i := 0; i < n; i++ {
+		result[i] = c.history[len(c.history)-1-i]
+	}
+	return result
+}
+
+// collectPlugins runs every registered MetricsPlugin and merges its
+// results into payload.CustomMetrics. A plugin that errors or times out
+// contributes nothing for this cycle; it does not fail the payload as a
+// whole, since one broken cloud API shouldn't take down every other metric.
+func (c *Collector) collectPlugins(payload *MetricsPayload) {
+	for _, p := range c.plugins {
+		ctx, cancel := context.WithTimeout(context.Background(), pluginCollectTimeout)
+		values, err := p.Collect(ctx)
+		cancel()
+		if err != nil {
+			continue
+		}
+		for k, v := range values {
+			if payload.CustomMetrics == nil {
+				payload.CustomMetrics = make(map[string]float64)
+			}
+			payload.CustomMetrics[k] = v
+		}
+	}
+}
+
+// BenchmarkResult reports the cost of collecting a MetricsPayload, so
+// operators can size the metrics interval on constrained hosts.
+type BenchmarkResult struct {
+	Iterations int           `json:"iterations"`
+	Total      time.Duration `json:"total"`
+	Mean       time.Duration `json:"mean"`
+	Min        time.Duration `json:"min"`
+	Max        time.Duration `json:"max"`
+}
+
+// Benchmark runs Collect iterations times and reports timing statistics for
+// self-profiling collection overhead.
+func (c *Collector) Benchmark(iterations int) (BenchmarkResult, error) {
+	if iterations <= 0 {
+		iterations = 1
+	}
+
+	result := BenchmarkResult{Iterations: iterations}
+	for i := 0; i < iterations; i++ {
+		start := time.Now()
+		if _, err := c.Collect(); err != nil {
+			return BenchmarkResult{}, err
+		}
+		elapsed := time.Since(start)
+
+		result.Total += elapsed
+		if result.Min == 0 || elapsed < result.Min {
+			result.Min = elapsed
+		}
+		if elapsed > result.Max {
+			result.Max = elapsed
+		}
+	}
+	result.Mean = result.Total / time.Duration(iterations)
+	return result, nil
+}
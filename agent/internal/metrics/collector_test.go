@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+)
+
+var errPluginFailed = errors.New("plugin: collect failed")
+
+func TestCollectorBenchmark(t *testing.T) {
+	if _, err := os.Stat("/proc/net/dev"); err != nil {
+		t.Skip("/proc/net/dev not available on this system")
+	}
+
+	c := NewCollector()
+	result, err := c.Benchmark(5)
+	if err != nil {
+		t.Fatalf("Benchmark: %v", err)
+	}
+	if result.Iterations != 5 {
+		t.Errorf("Iterations = %d, want 5", result.Iterations)
+	}
+	if result.Min > result.Max {
+		t.Errorf("Min (%v) > Max (%v)", result.Min, result.Max)
+	}
+	if result.Mean <= 0 {
+		t.Errorf("Mean = %v, want > 0", result.Mean)
+	}
+}
+
+type fakePlugin struct {
+	name   string
+	values map[string]float64
+	err    error
+}
+
+func (p fakePlugin) Name() string { return p.name }
+
+func (p fakePlugin) Collect(ctx context.Context) (map[string]float64, error) {
+	return p.values, p.err
+}
+
+func TestCollectMergesPluginMetricsIntoCustomMetrics(t *testing.T) {
+	if _, err := os.Stat("/proc/net/dev"); err != nil {
+		t.Skip("/proc/net/dev not available on this system")
+	}
+
+	c := NewCollector()
+	c.RegisterPlugin(fakePlugin{name: "test", values: map[string]float64{"test_counter": 42.0}})
+
+	payload, err := c.Collect()
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if got := payload.CustomMetrics["test_counter"]; got != 42.0 {
+		t.Errorf("CustomMetrics[test_counter] = %v, want 42.0", got)
+	}
+}
+
+func TestCollectSkipsErroringPluginWithoutFailingPayload(t *testing.T) {
+	if _, err := os.Stat("/proc/net/dev"); err != nil {
+		t.Skip("/proc/net/dev not available on this system")
+	}
+
+	c := NewCollector()
+	c.RegisterPlugin(fakePlugin{name: "broken", err: errPluginFailed})
+	c.RegisterPlugin(fakePlugin{name: "ok", values: map[string]float64{"ok_metric": 1.0}})
+
+	payload, err := c.Collect()
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if _, ok := payload.CustomMetrics["broken"]; ok {
+		t.Error("expected no metrics contributed by the erroring plugin")
+	}
+	if got := payload.CustomMetrics["ok_metric"]; got != 1.0 {
+		t.Errorf("CustomMetrics[ok_metric] = %v, want 1.0", got)
+	}
+}
+
+func TestHistoryReturnsAtMostHistorySizeEntries(t *testing.T) {
+	if _, err := os.Stat("/proc/net/dev"); err != nil {
+		t.Skip("/proc/net/dev not available on this system")
+	}
+
+	c := NewCollector(WithHistorySize(3))
+	for i := 0; i < 5; i++ {
+		if _, err := c.Collect(); err != nil {
+			t.Fatalf("Collect: %v", err)
+		}
+	}
+
+	history := c.History(10)
+	if len(history) != 3 {
+		t.Fatalf("History(10) returned %d entries, want 3", len(history))
+	}
+}
+
+func TestHistoryReturnsNewestFirst(t *testing.T) {
+	if _, err := os.Stat("/proc/net/dev"); err != nil {
+		t.Skip("/proc/net/dev not available on this system")
+	}
+
+	c := NewCollector(WithHistorySize(3))
+	var collected []*MetricsPayload
+	for i := 0; i < 3; i++ {
+		payload, err := c.Collect()
+		if err != nil {
+			t.Fatalf("Collect: %v", err)
+		}
+		collected = append(collected, payload)
+	}
+
+	history := c.History(3)
+	for i, want := range []*MetricsPayload{collected[2], collected[1], collected[0]} {
+		if history[i] != want {
+			t.Errorf("History[%d] = %p, want %p", i, history[i], want)
+		}
+	}
+}
+
+func TestNullPluginCollectsNothing(t *testing.T) {
+	p := NullPlugin{}
+	if p.Name() != "null" {
+		t.Errorf("Name() = %q, want null", p.Name())
+	}
+	values, err := p.Collect(context.Background())
+	if err != nil || values != nil {
+		t.Errorf("Collect() = (%v, %v), want (nil, nil)", values, err)
+	}
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cpuTimes holds the jiffie counters from the aggregate "cpu" line of
+// /proc/stat.
+type cpuTimes struct {
+	idle  uint64
+	total uint64
+}
+
+func readCPUTimes() (cpuTimes, error) {
+	data, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return cpuTimes{}, fmt.Errorf("metrics: read /proc/stat: %w", err)
+	}
+	times, ok, err := parseCPUStat(string(data))
+	if err != nil {
+		return cpuTimes{}, err
+	}
+	if !ok {
+		return cpuTimes{}, fmt.Errorf("metrics: no aggregate cpu line in /proc/stat")
+	}
+	return times, nil
+}
+
+// parseCPUStat extracts the aggregate "cpu" line's jiffie counters from the
+// contents of /proc/stat.
+func parseCPUStat(contents string) (times cpuTimes, ok bool, err error) {
+	for _, line := range strings.Split(contents, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[0] != "cpu" {
+			continue
+		}
+		var total uint64
+		var idle uint64
+		for i, f := range fields[1:] {
+			v, err := strconv.ParseUint(f, 10, 64)
+			if err != nil {
+				return cpuTimes{}, false, fmt.Errorf("metrics: parse /proc/stat field %d: %w", i, err)
+			}
+			total += v
+			if i == 3 { // idle is the 4th field
+				idle = v
+			}
+		}
+		return cpuTimes{idle: idle, total: total}, true, nil
+	}
+	return cpuTimes{}, false, nil
+}
+
+// collectCPU samples CPU utilization by reading /proc/stat twice, sleeping
+// sampleDuration in between, and returns the percentage of non-idle time
+// observed over that window.
+func collectCPU(sampleDuration time.Duration) (float64, error) {
+	before, err := readCPUTimes()
+	if err != nil {
+		return 0, err
+	}
+	time.Sleep(sampleDuration)
+	after, err := readCPUTimes()
+	if err != nil {
+		return 0, err
+	}
+
+	totalDelta := after.total - before.total
+	if totalDelta == 0 {
+		return 0, nil
+	}
+	idleDelta := after.idle - before.idle
+	return (1 - float64(idleDelta)/float64(totalDelta)) * 100, nil
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import "testing"
+
+func TestParseCPUStat(t *testing.T) {
+	contents := "cpu  100 0 50 850 0 0 0 0 0 0\ncpu0 50 0 25 425 0 0 0 0 0 0\n"
+	times, ok, err := parseCPUStat(contents)
+	if err != nil {
+		t.Fatalf("parseCPUStat: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected an aggregate cpu line to be found")
+	}
+	if times.idle != 850 {
+		t.Errorf("idle = %d, want 850", times.idle)
+	}
+	if times.total != 1000 {
+		t.Errorf("total = %d, want 1000", times.total)
+	}
+}
+
+func TestParseCPUStatMissingLine(t *testing.T) {
+	_, ok, err := parseCPUStat("intr 12345\n")
+	if err != nil {
+		t.Fatalf("parseCPUStat: %v", err)
+	}
+	if ok {
+		t.Fatal("expected no aggregate cpu line to be found")
+	}
+}
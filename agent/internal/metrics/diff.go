@@ -0,0 +1,109 @@
+package metrics
+
+import "reflect"
+
+// defaultFullEvery is how often (in samples) a DiffTracker sends a full
+// payload rather than a diff, letting the Console resync in case an
+// earlier diff was lost in transit.
+const defaultFullEvery = 10
+
+// MetricsDiff carries only the MetricsPayload fields that changed since
+// the last full payload, so the Console isn't forced to re-derive which
+// of a mostly-unchanged sample's fields are actually new. A nil field
+// means unchanged since the last full payload.
+type MetricsDiff struct {
+	CPUPercent       *float64            `json:"cpu_percent,omitempty"`
+	MemoryUsedBytes  *uint64             `json:"memory_used_bytes,omitempty"`
+	MemoryTotalBytes *uint64             `json:"memory_total_bytes,omitempty"`
+	DiskUsedBytes    *uint64             `json:"disk_used_bytes,omitempty"`
+	DiskTotalBytes   *uint64             `json:"disk_total_bytes,omitempty"`
+	NetworkRate      *NetworkRateMetrics `json:"network_rate,omitempty"`
+	IOStats          []DiskIOStats       `json:"io_stats,omitempty"`
+	CustomMetrics    map[string]float64  `json:"custom_metrics,omitempty"`
+}
+
+// ComputeDiff compares current against prev field by field (using
+// reflect.DeepEqual for slice-, map-, and struct-typed fields) and returns
+// a MetricsDiff containing only the fields that changed, or nil if none
+// did. prev and current must both be non-nil.
+func ComputeDiff(prev, current *MetricsPayload) *MetricsDiff {
+	diff := &MetricsDiff{}
+	changed := false
+
+	if !reflect.DeepEqual(prev.CPUPercent, current.CPUPercent) {
+		diff.CPUPercent = &current.CPUPercent
+		changed = true
+	}
+	if !reflect.DeepEqual(prev.MemoryUsedBytes, current.MemoryUsedBytes) {
+		diff.MemoryUsedBytes = &current.MemoryUsedBytes
+		changed = true
+	}
+	if !reflect.DeepEqual(prev.MemoryTotalBytes, current.MemoryTotalBytes) {
+		diff.MemoryTotalBytes = &current.MemoryTotalBytes
+		changed = true
+	}
+	if !reflect.DeepEqual(prev.DiskUsedBytes, current.DiskUsedBytes) {
+		diff.DiskUsedBytes = &current.DiskUsedBytes
+		changed = true
+	}
+	if !reflect.DeepEqual(prev.DiskTotalBytes, current.DiskTotalBytes) {
+		diff.DiskTotalBytes = &current.DiskTotalBytes
+		changed = true
+	}
+	if !reflect.DeepEqual(prev.NetworkRate, current.NetworkRate) {
+		diff.NetworkRate = current.NetworkRate
+		changed = true
+	}
+	if !reflect.DeepEqual(prev.IOStats, current.IOStats) {
+		diff.IOStats = current.IOStats
+		changed = true
+	}
+	if !reflect.DeepEqual(prev.CustomMetrics, current.CustomMetrics) {
+		diff.CustomMetrics = current.CustomMetrics
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return diff
+}
+
+// DiffTracker decides, for successive MetricsPayloads from a Collector,
+// whether to send a full payload or attach a MetricsDiff against the last
+// full payload sent. A diffed payload still carries every field of the
+// current sample alongside Diff, so it is no smaller on the wire than a
+// full one; Diff only names which fields changed.
+type DiffTracker struct {
+	fullEvery int
+	count     int
+	lastFull  *MetricsPayload
+}
+
+// NewDiffTracker returns a DiffTracker that sends a full payload on the
+// first call to Next and every fullEvery-th call after that (using
+// defaultFullEvery if fullEvery <= 0), so the Console can resync even if
+// an earlier diff was lost in transit.
+func NewDiffTracker(fullEvery int) *DiffTracker {
+	if fullEvery <= 0 {
+		fullEvery = defaultFullEvery
+	}
+	return &DiffTracker{fullEvery: fullEvery}
+}
+
+// Next returns the payload to actually send for current: current itself,
+// unmodified, on a full-payload tick, or a copy of current with Diff
+// populated against the last full payload sent otherwise.
+func (t *DiffTracker) Next(current *MetricsPayload) *MetricsPayload {
+	t.count++
+	if t.lastFull == nil || t.count >= t.fullEvery {
+		t.count = 0
+		full := *current
+		t.lastFull = &full
+		return current
+	}
+
+	diffed := *current
+	diffed.Diff = ComputeDiff(t.lastFull, current)
+	return &diffed
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import "testing"
+
+func TestComputeDiffReportsOnlyChangedField(t *testing.T) {
+	prev := &MetricsPayload{CPUPercent: 10, MemoryUsedBytes: 100, MemoryTotalBytes: 1000}
+	current := &MetricsPayload{CPUPercent: 55, MemoryUsedBytes: 100, MemoryTotalBytes: 1000}
+
+	diff := ComputeDiff(prev, current)
+	if diff == nil {
+		t.Fatal("ComputeDiff = nil, want a diff for the changed CPUPercent")
+	}
+	if diff.CPUPercent == nil || *diff.CPUPercent != 55 {
+		t.Errorf("diff.CPUPercent = %v, want 55", diff.CPUPercent)
+	}
+	if diff.MemoryUsedBytes != nil {
+		t.Errorf("diff.MemoryUsedBytes = %v, want nil (unchanged)", diff.MemoryUsedBytes)
+	}
+	if diff.MemoryTotalBytes != nil {
+		t.Errorf("diff.MemoryTotalBytes = %v, want nil (unchanged)", diff.MemoryTotalBytes)
+	}
+}
+
+func TestComputeDiffReturnsNilWhenNothingChanged(t *testing.T) {
+	prev := &MetricsPayload{CPUPercent: 10, MemoryUsedBytes: 100}
+	current := &MetricsPayload{CPUPercent: 10, MemoryUsedBytes: 100}
+
+	if diff := ComputeDiff(prev, current); diff != nil {
+		t.Errorf("ComputeDiff = %+v, want nil for identical payloads", diff)
+	}
+}
+
+func TestDiffTrackerSendsFullPayloadEveryNthSample(t *testing.T) {
+	tracker := NewDiffTracker(3)
+
+	for i := 1; i <= 3; i++ {
+		payload := tracker.Next(&MetricsPayload{CPUPercent: float64(i)})
+		if i == 1 {
+			if payload.Diff != nil {
+				t.Fatalf("sample %d: Diff = %+v, want nil for the first (full) sample", i, payload.Diff)
+			}
+			continue
+		}
+		if payload.Diff == nil {
+			t.Fatalf("sample %d: Diff = nil, want a populated diff", i)
+		}
+	}
+
+	full := tracker.Next(&MetricsPayload{CPUPercent: 4})
+	if full.Diff != nil {
+		t.Errorf("4th sample: Diff = %+v, want nil (full resync tick)", full.Diff)
+	}
+}
+
+func TestDiffTrackerNextDoesNotMutateInputPayload(t *testing.T) {
+	tracker := NewDiffTracker(2)
+	tracker.Next(&MetricsPayload{CPUPercent: 1})
+
+	current := &MetricsPayload{CPUPercent: 2}
+	tracker.Next(current)
+
+	if current.Diff != nil {
+		t.Error("Next mutated the caller's payload; want a copy returned instead")
+	}
+}
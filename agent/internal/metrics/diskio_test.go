@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const diskStatsFixtureBefore = `   8       0 sda 1000 50 20000 5000 2000 100 40000 15000 0 18000 20000
+   8       1 sda1 900 40 18000 4500 1800 90 36000 13500 0 16500 18000
+`
+
+const diskStatsFixtureAfter = `   8       0 sda 1100 55 22000 5600 2200 120 44000 16800 0 20400 22800
+   8       1 sda1 990 44 19800 4950 1980 99 39600 14850 0 18150 19800
+`
+
+func TestParseDiskStats(t *testing.T) {
+	snapshots, err := parseDiskStats(strings.NewReader(diskStatsFixtureBefore))
+	if err != nil {
+		t.Fatalf("parseDiskStats: %v", err)
+	}
+	sda, ok := snapshots["sda"]
+	if !ok {
+		t.Fatal("expected an entry for device sda")
+	}
+	if sda.readIOs != 1000 || sda.writeIOs != 2000 || sda.ioTimeMs != 18000 || sda.weightedIOTimeMs != 20000 {
+		t.Errorf("unexpected sda snapshot: %+v", sda)
+	}
+	if sda.sectorsRead != 20000 || sda.sectorsWritten != 40000 {
+		t.Errorf("unexpected sda sector counts: %+v", sda)
+	}
+}
+
+func TestDiskIOCollectorComputesDeltaStats(t *testing.T) {
+	before, err := parseDiskStats(strings.NewReader(diskStatsFixtureBefore))
+	if err != nil {
+		t.Fatalf("parseDiskStats: %v", err)
+	}
+	after, err := parseDiskStats(strings.NewReader(diskStatsFixtureAfter))
+	if err != nil {
+		t.Fatalf("parseDiskStats: %v", err)
+	}
+
+	c := NewDiskIOCollector()
+	start := time.Unix(0, 0)
+	c.collectAt(before, start)
+	stats := c.collectAt(after, start.Add(1000*time.Millisecond))
+
+	var sda *DiskIOStats
+	for i := range stats {
+		if stats[i].Device == "sda" {
+			sda = &stats[i]
+		}
+	}
+	if sda == nil {
+		t.Fatal("expected stats for device sda")
+	}
+
+	// deltaReadIOs=100, deltaWriteIOs=200, deltaIOTimeMs=2400,
+	// deltaWeightedIOTimeMs=2800, elapsedMs=1000.
+	wantAvgLatencyMs := 2400.0 / 300.0
+	wantUtilizationPercent := 2400.0 / 1000.0 * 100
+	wantQueueDepth := 2800.0 / 1000.0
+
+	if sda.AvgLatencyMs != wantAvgLatencyMs {
+		t.Errorf("AvgLatencyMs = %v, want %v", sda.AvgLatencyMs, wantAvgLatencyMs)
+	}
+	if sda.UtilizationPercent != wantUtilizationPercent {
+		t.Errorf("UtilizationPercent = %v, want %v", sda.UtilizationPercent, wantUtilizationPercent)
+	}
+	if sda.QueueDepth != wantQueueDepth {
+		t.Errorf("QueueDepth = %v, want %v", sda.QueueDepth, wantQueueDepth)
+	}
+
+	// deltaSectorsRead=2000, deltaSectorsWritten=4000, elapsedSeconds=1.
+	wantReadBytesPerSec := 2000.0 * diskSectorBytes
+	wantWriteBytesPerSec := 4000.0 * diskSectorBytes
+	if sda.ReadBytesPerSec != wantReadBytesPerSec {
+		t.Errorf("ReadBytesPerSec = %v, want %v", sda.ReadBytesPerSec, wantReadBytesPerSec)
+	}
+	if sda.WriteBytesPerSec != wantWriteBytesPerSec {
+		t.Errorf("WriteBytesPerSec = %v, want %v", sda.WriteBytesPerSec, wantWriteBytesPerSec)
+	}
+	if sda.ReadOpsPerSec != 100 {
+		t.Errorf("ReadOpsPerSec = %v, want 100", sda.ReadOpsPerSec)
+	}
+	if sda.WriteOpsPerSec != 200 {
+		t.Errorf("WriteOpsPerSec = %v, want 200", sda.WriteOpsPerSec)
+	}
+}
+
+func TestDiskIOCollectorHandlesDeviceDisappearing(t *testing.T) {
+	before, err := parseDiskStats(strings.NewReader(diskStatsFixtureBefore))
+	if err != nil {
+		t.Fatalf("parseDiskStats: %v", err)
+	}
+	// sda1 is present in "before" but removed here, simulating a device
+	// disappearing between samples (e.g. a USB drive unplugged).
+	after, err := parseDiskStats(strings.NewReader(`   8       0 sda 1100 55 22000 5600 2200 120 44000 16800 0 20400 22800
+`))
+	if err != nil {
+		t.Fatalf("parseDiskStats: %v", err)
+	}
+
+	c := NewDiskIOCollector()
+	start := time.Unix(0, 0)
+	c.collectAt(before, start)
+	stats := c.collectAt(after, start.Add(time.Second))
+
+	for _, s := range stats {
+		if s.Device == "sda1" {
+			t.Fatalf("expected no stats for the disappeared device sda1, got %+v", s)
+		}
+	}
+}
+
+func TestDiskIOCollectorFirstCallReportsNoStats(t *testing.T) {
+	c := NewDiskIOCollector()
+	snap, err := parseDiskStats(strings.NewReader(diskStatsFixtureBefore))
+	if err != nil {
+		t.Fatalf("parseDiskStats: %v", err)
+	}
+	stats := c.collectAt(snap, time.Now())
+	if len(stats) != 0 {
+		t.Errorf("expected no stats on the first call, got %+v", stats)
+	}
+}
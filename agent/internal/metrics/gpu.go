@@ -0,0 +1,13 @@
+package metrics
+
+// GPUMetrics reports a single GPU's utilization, memory, and temperature.
+// Populated only on builds compiled with the nvml build tag on a host with
+// at least one NVIDIA GPU; see collectGPU.
+type GPUMetrics struct {
+	Index         int     `json:"index"`
+	Name          string  `json:"name"`
+	UsagePercent  float64 `json:"usage_percent"`
+	MemUsedBytes  uint64  `json:"mem_used_bytes"`
+	MemTotalBytes uint64  `json:"mem_total_bytes"`
+	TempCelsius   float64 `json:"temp_celsius"`
+}
@@ -0,0 +1,64 @@
+//go:build nvml
+
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/mindprince/gonvml"
+)
+
+// collectGPU reports per-GPU utilization, memory, and temperature via NVML
+// for every NVIDIA GPU visible to this process. It initializes and shuts
+// down NVML on every call rather than keeping it open for the process
+// lifetime, since Collect calls are infrequent (once per MetricsInterval)
+// and this avoids leaking driver state across agent restarts triggered by
+// the updater.
+func collectGPU() ([]GPUMetrics, error) {
+	if err := gonvml.Initialize(); err != nil {
+		return nil, fmt.Errorf("metrics: initialize nvml: %w", err)
+	}
+	defer gonvml.Shutdown()
+
+	count, err := gonvml.DeviceCount()
+	if err != nil {
+		return nil, fmt.Errorf("metrics: nvml device count: %w", err)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	gpus := make([]GPUMetrics, 0, count)
+	for i := uint(0); i < count; i++ {
+		dev, err := gonvml.DeviceHandleByIndex(i)
+		if err != nil {
+			return nil, fmt.Errorf("metrics: nvml device %d: %w", i, err)
+		}
+		name, err := dev.Name()
+		if err != nil {
+			return nil, fmt.Errorf("metrics: nvml device %d name: %w", i, err)
+		}
+		memTotal, memUsed, err := dev.MemoryInfo()
+		if err != nil {
+			return nil, fmt.Errorf("metrics: nvml device %d memory: %w", i, err)
+		}
+		usage, _, err := dev.UtilizationRates()
+		if err != nil {
+			return nil, fmt.Errorf("metrics: nvml device %d utilization: %w", i, err)
+		}
+		tempC, err := dev.Temperature()
+		if err != nil {
+			return nil, fmt.Errorf("metrics: nvml device %d temperature: %w", i, err)
+		}
+
+		gpus = append(gpus, GPUMetrics{
+			Index:         int(i),
+			Name:          name,
+			UsagePercent:  float64(usage),
+			MemUsedBytes:  memUsed,
+			MemTotalBytes: memTotal,
+			TempCelsius:   float64(tempC),
+		})
+	}
+	return gpus, nil
+}
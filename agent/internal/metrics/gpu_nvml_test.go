@@ -0,0 +1,30 @@
+//go:build nvml
+
+package metrics
+
+import "testing"
+
+// TestCollectGPUOnRealHardware is an integration test, not exercised by CI:
+// it requires an actual NVIDIA GPU, the NVIDIA driver, and NVML
+// (libnvidia-ml.so) installed on the host. Run it manually with:
+//
+//	go test -tags nvml -run TestCollectGPUOnRealHardware ./internal/metrics/...
+//
+// On a host without an NVIDIA GPU, gonvml.Initialize will fail and this
+// test skips rather than fails, since the nvml build tag alone doesn't
+// guarantee GPU hardware is present (e.g. a CI runner built with -tags nvml
+// to catch compile errors, but with no GPU attached).
+func TestCollectGPUOnRealHardware(t *testing.T) {
+	gpus, err := collectGPU()
+	if err != nil {
+		t.Skipf("no NVML-capable GPU available: %v", err)
+	}
+	for _, gpu := range gpus {
+		if gpu.Name == "" {
+			t.Errorf("gpu %d: expected a non-empty name", gpu.Index)
+		}
+		if gpu.MemTotalBytes == 0 {
+			t.Errorf("gpu %d: expected non-zero MemTotalBytes", gpu.Index)
+		}
+	}
+}
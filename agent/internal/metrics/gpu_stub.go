@@ -0,0 +1,9 @@
+//go:build !nvml
+
+package metrics
+
+// collectGPU reports no GPUs on a build without the nvml tag. See
+// gpu_nvml.go for the NVML-backed implementation.
+func collectGPU() ([]GPUMetrics, error) {
+	return nil, nil
+}
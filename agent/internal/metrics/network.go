@@ -0,0 +1,218 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NetworkSnapshot holds cumulative network counters as reported by the
+// kernel at a point in time.
+type NetworkSnapshot struct {
+	BytesSent   uint64
+	BytesRecv   uint64
+	PacketsSent uint64
+	PacketsRecv uint64
+
+	// Interfaces holds the same counters broken down per interface,
+	// filtered by the allowlist passed to collectNetwork (every interface
+	// is included when the allowlist is empty). The aggregate fields
+	// above are always summed across every interface regardless of the
+	// allowlist, so filtering Interfaces never skews the totals.
+	Interfaces []NetworkInterfaceSnapshot
+}
+
+// NetworkInterfaceSnapshot holds cumulative counters for a single network
+// interface.
+type NetworkInterfaceSnapshot struct {
+	Name        string
+	IsLoopback  bool
+	BytesSent   uint64
+	BytesRecv   uint64
+	PacketsSent uint64
+	PacketsRecv uint64
+}
+
+// collectNetwork reads cumulative network counters from /proc/net/dev, both
+// summed across all interfaces and broken down per interface. allowlist, if
+// non-empty, restricts the per-interface breakdown to the named interfaces;
+// the aggregate counters always cover every interface. The counters are
+// cumulative since boot, not rates.
+func collectNetwork(allowlist map[string]bool) (NetworkSnapshot, error) {
+	f, err := os.Open("/proc/net/dev")
+	if err != nil {
+		return NetworkSnapshot{}, fmt.Errorf("metrics: open /proc/net/dev: %w", err)
+	}
+	defer f.Close()
+
+	var snap NetworkSnapshot
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if lineNum <= 2 {
+			continue // header lines
+		}
+		fields := strings.Fields(strings.Replace(scanner.Text(), ":", " ", 1))
+		if len(fields) < 10 {
+			continue
+		}
+		name := fields[0]
+		recvBytes, _ := strconv.ParseUint(fields[1], 10, 64)
+		recvPackets, _ := strconv.ParseUint(fields[2], 10, 64)
+		sentBytes, _ := strconv.ParseUint(fields[9], 10, 64)
+		sentPackets, _ := strconv.ParseUint(fields[10], 10, 64)
+		snap.BytesRecv += recvBytes
+		snap.PacketsRecv += recvPackets
+		snap.BytesSent += sentBytes
+		snap.PacketsSent += sentPackets
+
+		if len(allowlist) == 0 || allowlist[name] {
+			snap.Interfaces = append(snap.Interfaces, NetworkInterfaceSnapshot{
+				Name:        name,
+				IsLoopback:  name == "lo",
+				BytesSent:   sentBytes,
+				BytesRecv:   recvBytes,
+				PacketsSent: sentPackets,
+				PacketsRecv: recvPackets,
+			})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return NetworkSnapshot{}, fmt.Errorf("metrics: scan /proc/net/dev: %w", err)
+	}
+	return snap, nil
+}
+
+// NetworkRateMetrics reports network throughput as rates derived from two
+// successive NetworkSnapshot reads.
+type NetworkRateMetrics struct {
+	BytesSentPerSec   float64 `json:"bytes_sent_per_sec"`
+	BytesRecvPerSec   float64 `json:"bytes_recv_per_sec"`
+	PacketsSentPerSec float64 `json:"packets_sent_per_sec"`
+	PacketsRecvPerSec float64 `json:"packets_recv_per_sec"`
+
+	// Interfaces breaks the aggregate rates above down per network
+	// interface, filtered by the allowlist configured via
+	// NetworkRateCollector.SetInterfaceAllowlist
+	// (SINDRI_AGENT_NET_INTERFACES). Nil on the first Collect call, since
+	// a rate needs two samples of the same interface to compute.
+	Interfaces []NetworkInterfaceRate `json:"interfaces,omitempty"`
+}
+
+// NetworkInterfaceRate reports throughput rates for a single network
+// interface, mirroring NetworkRateMetrics but scoped to one interface.
+type NetworkInterfaceRate struct {
+	Name              string  `json:"name"`
+	IsLoopback        bool    `json:"is_loopback"`
+	BytesSentPerSec   float64 `json:"bytes_sent_per_sec"`
+	BytesRecvPerSec   float64 `json:"bytes_recv_per_sec"`
+	PacketsSentPerSec float64 `json:"packets_sent_per_sec"`
+	PacketsRecvPerSec float64 `json:"packets_recv_per_sec"`
+}
+
+// NetworkRateCollector wraps collectNetwork, computing byte/packet rates
+// from successive snapshots rather than exposing raw cumulative counters.
+type NetworkRateCollector struct {
+	prev      *NetworkSnapshot
+	prevTime  time.Time
+	allowlist map[string]bool
+}
+
+// NewNetworkRateCollector returns a collector with no prior snapshot; its
+// first Collect call reports a zero rate.
+func NewNetworkRateCollector() *NetworkRateCollector {
+	return &NetworkRateCollector{}
+}
+
+// SetInterfaceAllowlist restricts the per-interface breakdown in each
+// NetworkRateMetrics to the named interfaces, preventing an instance with
+// many virtual interfaces (bridges, veths, tunnels) from flooding the
+// Console with rows it doesn't care about. An empty or nil allowlist (the
+// default) includes every interface.
+func (c *NetworkRateCollector) SetInterfaceAllowlist(names []string) {
+	if len(names) == 0 {
+		c.allowlist = nil
+		return
+	}
+	allowlist := make(map[string]bool, len(names))
+	for _, name := range names {
+		allowlist[name] = true
+	}
+	c.allowlist = allowlist
+}
+
+// Collect reads the current network snapshot and returns rates computed
+// against the previous call. If this is the first call, or a counter has
+// reset (current value lower than the previous one, e.g. after an
+// interface reset), the corresponding delta is treated as zero.
+func (c *NetworkRateCollector) Collect() (NetworkRateMetrics, error) {
+	snap, err := collectNetwork(c.allowlist)
+	if err != nil {
+		return NetworkRateMetrics{}, err
+	}
+	return c.collectAt(snap, time.Now()), nil
+}
+
+// collectAt is a test seam: it computes rates as if snap were collected at
+// the given time, without touching the real /proc filesystem.
+func (c *NetworkRateCollector) collectAt(snap NetworkSnapshot, now time.Time) NetworkRateMetrics {
+	var rates NetworkRateMetrics
+	if c.prev != nil {
+		elapsed := now.Sub(c.prevTime).Seconds()
+		if elapsed > 0 {
+			rates = NetworkRateMetrics{
+				BytesSentPerSec:   rate(c.prev.BytesSent, snap.BytesSent, elapsed),
+				BytesRecvPerSec:   rate(c.prev.BytesRecv, snap.BytesRecv, elapsed),
+				PacketsSentPerSec: rate(c.prev.PacketsSent, snap.PacketsSent, elapsed),
+				PacketsRecvPerSec: rate(c.prev.PacketsRecv, snap.PacketsRecv, elapsed),
+				Interfaces:        interfaceRates(c.prev.Interfaces, snap.Interfaces, elapsed),
+			}
+		}
+	}
+	c.prev = &snap
+	c.prevTime = now
+	return rates
+}
+
+// interfaceRates pairs up current interfaces with their previous sample by
+// name and computes per-interface rates, skipping any interface with no
+// matching previous sample (e.g. one that just came up).
+func interfaceRates(prev, current []NetworkInterfaceSnapshot, elapsedSeconds float64) []NetworkInterfaceRate {
+	if len(current) == 0 {
+		return nil
+	}
+	prevByName := make(map[string]NetworkInterfaceSnapshot, len(prev))
+	for _, p := range prev {
+		prevByName[p.Name] = p
+	}
+
+	var rates []NetworkInterfaceRate
+	for _, cur := range current {
+		p, ok := prevByName[cur.Name]
+		if !ok {
+			continue
+		}
+		rates = append(rates, NetworkInterfaceRate{
+			Name:              cur.Name,
+			IsLoopback:        cur.IsLoopback,
+			BytesSentPerSec:   rate(p.BytesSent, cur.BytesSent, elapsedSeconds),
+			BytesRecvPerSec:   rate(p.BytesRecv, cur.BytesRecv, elapsedSeconds),
+			PacketsSentPerSec: rate(p.PacketsSent, cur.PacketsSent, elapsedSeconds),
+			PacketsRecvPerSec: rate(p.PacketsRecv, cur.PacketsRecv, elapsedSeconds),
+		})
+	}
+	return rates
+}
+
+// rate computes (current-previous)/elapsed, treating a monotonic counter
+// reset (current < previous) as a zero delta rather than a negative rate.
+func rate(previous, current uint64, elapsedSeconds float64) float64 {
+	if current < previous {
+		return 0
+	}
+	return float64(current-previous) / elapsedSeconds
+}
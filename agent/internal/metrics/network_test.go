@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNetworkRateCollectorComputesRate(t *testing.T) {
+	c := NewNetworkRateCollector()
+	start := time.Unix(1000, 0)
+
+	first := c.collectAt(NetworkSnapshot{BytesSent: 1000, BytesRecv: 2000, PacketsSent: 10, PacketsRecv: 20}, start)
+	if first.BytesSentPerSec != 0 || first.BytesRecvPerSec != 0 || first.PacketsSentPerSec != 0 || first.PacketsRecvPerSec != 0 || first.Interfaces != nil {
+		t.Errorf("expected zero rate on first sample, got %+v", first)
+	}
+
+	second := c.collectAt(NetworkSnapshot{BytesSent: 1500, BytesRecv: 2400, PacketsSent: 15, PacketsRecv: 24}, start.Add(5*time.Second))
+	if second.BytesSentPerSec != 100 {
+		t.Errorf("BytesSentPerSec = %v, want 100", second.BytesSentPerSec)
+	}
+	if second.BytesRecvPerSec != 80 {
+		t.Errorf("BytesRecvPerSec = %v, want 80", second.BytesRecvPerSec)
+	}
+	if second.PacketsSentPerSec != 1 {
+		t.Errorf("PacketsSentPerSec = %v, want 1", second.PacketsSentPerSec)
+	}
+}
+
+func TestNetworkRateCollectorHandlesCounterReset(t *testing.T) {
+	c := NewNetworkRateCollector()
+	start := time.Unix(2000, 0)
+
+	c.collectAt(NetworkSnapshot{BytesSent: 5000}, start)
+	reset := c.collectAt(NetworkSnapshot{BytesSent: 100}, start.Add(1*time.Second))
+
+	if reset.BytesSentPerSec != 0 {
+		t.Errorf("BytesSentPerSec after counter reset = %v, want 0", reset.BytesSentPerSec)
+	}
+}
+
+func TestNetworkRateCollectorComputesPerInterfaceRates(t *testing.T) {
+	c := NewNetworkRateCollector()
+	start := time.Unix(3000, 0)
+
+	c.collectAt(NetworkSnapshot{Interfaces: []NetworkInterfaceSnapshot{
+		{Name: "lo", IsLoopback: true, BytesSent: 100, BytesRecv: 100},
+		{Name: "eth0", BytesSent: 1000, BytesRecv: 2000},
+	}}, start)
+
+	second := c.collectAt(NetworkSnapshot{Interfaces: []NetworkInterfaceSnapshot{
+		{Name: "lo", IsLoopback: true, BytesSent: 150, BytesRecv: 150},
+		{Name: "eth0", BytesSent: 1500, BytesRecv: 2400},
+	}}, start.Add(5*time.Second))
+
+	if len(second.Interfaces) != 2 {
+		t.Fatalf("got %d interfaces, want 2", len(second.Interfaces))
+	}
+
+	var sawNonLoopback bool
+	for _, iface := range second.Interfaces {
+		if iface.Name == "eth0" {
+			sawNonLoopback = true
+			if iface.IsLoopback {
+				t.Error("eth0 should not be marked IsLoopback")
+			}
+			if iface.BytesSentPerSec != 100 {
+				t.Errorf("eth0 BytesSentPerSec = %v, want 100", iface.BytesSentPerSec)
+			}
+			if iface.BytesRecvPerSec != 80 {
+				t.Errorf("eth0 BytesRecvPerSec = %v, want 80", iface.BytesRecvPerSec)
+			}
+		}
+	}
+	if !sawNonLoopback {
+		t.Error("expected at least one non-loopback interface in the breakdown")
+	}
+}
+
+func TestNetworkRateCollectorSkipsInterfacesWithNoPriorSample(t *testing.T) {
+	c := NewNetworkRateCollector()
+	start := time.Unix(4000, 0)
+
+	c.collectAt(NetworkSnapshot{Interfaces: []NetworkInterfaceSnapshot{{Name: "eth0", BytesSent: 1000}}}, start)
+	second := c.collectAt(NetworkSnapshot{Interfaces: []NetworkInterfaceSnapshot{
+		{Name: "eth0", BytesSent: 1500},
+		{Name: "eth1", BytesSent: 500},
+	}}, start.Add(5*time.Second))
+
+	if len(second.Interfaces) != 1 || second.Interfaces[0].Name != "eth0" {
+		t.Errorf("Interfaces = %+v, want only eth0 (eth1 has no prior sample)", second.Interfaces)
+	}
+}
+
+func TestCollectNetworkReturnsNonLoopbackInterface(t *testing.T) {
+	snap, err := collectNetwork(nil)
+	if err != nil {
+		t.Fatalf("collectNetwork: %v", err)
+	}
+
+	var sawNonLoopback bool
+	for _, iface := range snap.Interfaces {
+		if !iface.IsLoopback {
+			sawNonLoopback = true
+			break
+		}
+	}
+	if !sawNonLoopback {
+		t.Errorf("Interfaces = %+v, want at least one non-loopback interface", snap.Interfaces)
+	}
+}
+
+func TestSetInterfaceAllowlistFiltersCollectNetworkBreakdown(t *testing.T) {
+	c := NewNetworkRateCollector()
+	c.SetInterfaceAllowlist([]string{"eth0"})
+
+	snap, err := collectNetwork(c.allowlist)
+	if err != nil {
+		t.Fatalf("collectNetwork: %v", err)
+	}
+	for _, iface := range snap.Interfaces {
+		if iface.Name != "eth0" {
+			t.Errorf("Interfaces contains disallowed interface %q", iface.Name)
+		}
+	}
+}
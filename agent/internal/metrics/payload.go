@@ -0,0 +1,52 @@
+// Package metrics collects host and process metrics for periodic delivery
+// to the Console.
+package metrics
+
+import "time"
+
+// MetricsPayload is the wire representation of a single metrics sample.
+type MetricsPayload struct {
+	Timestamp        time.Time `json:"timestamp"`
+	CPUPercent       float64   `json:"cpu_percent"`
+	MemoryUsedBytes  uint64    `json:"memory_used_bytes"`
+	MemoryTotalBytes uint64    `json:"memory_total_bytes"`
+	DiskUsedBytes    uint64    `json:"disk_used_bytes"`
+	DiskTotalBytes   uint64    `json:"disk_total_bytes"`
+
+	NetworkRate *NetworkRateMetrics `json:"network_rate,omitempty"`
+
+	// IOStats holds per-device disk I/O latency, queue depth, and
+	// utilization. Populated only when disk I/O stats collection is
+	// enabled (SINDRI_AGENT_DISK_IO_STATS).
+	IOStats []DiskIOStats `json:"io_stats,omitempty"`
+
+	// TopProcesses holds the most CPU-hungry processes on the host, sorted
+	// descending by CPUPercent. Populated only when top-process reporting
+	// is enabled (SINDRI_AGENT_TOP_PROCESSES), since enumerating every
+	// process on the host adds real overhead on systems with thousands of
+	// them.
+	TopProcesses []TopProcess `json:"top_processes,omitempty"`
+
+	// GPU holds per-GPU utilization, memory, and temperature. Always nil
+	// on a build without the nvml build tag, and on an nvml build with no
+	// NVIDIA GPU present.
+	GPU []GPUMetrics `json:"gpu,omitempty"`
+
+	// Cgroup holds the cgroup v2 CPU quota and memory limit this process
+	// is confined to, which on containerized platforms (Fly.io,
+	// Kubernetes, Docker) can be far smaller than the host's total
+	// resources reported above. Nil on a host without cgroup v2.
+	Cgroup *CgroupMetrics `json:"cgroup,omitempty"`
+
+	// CustomMetrics holds values merged in from any MetricsPlugins
+	// registered via Collector.RegisterPlugin. Nil if none are registered
+	// or none returned any metrics this cycle.
+	CustomMetrics map[string]float64 `json:"custom_metrics,omitempty"`
+
+	// Diff, when non-nil, holds only the fields that changed since the
+	// last full payload sent (see DiffTracker). The payload's other fields
+	// are still populated from the same sample, so unchanged data is sent
+	// again on every tick. The Console merges Diff onto its last full payload
+	// rather than trusting this payload's other fields directly.
+	Diff *MetricsDiff `json:"diff,omitempty"`
+}
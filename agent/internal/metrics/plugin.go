@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+	"time"
+)
+
+// pluginCollectTimeout bounds how long a single MetricsPlugin is allowed to
+// run per Collect call, so a slow or hung cloud API doesn't stall the
+// entire metrics push.
+const pluginCollectTimeout = 2 * time.Second
+
+// MetricsPlugin collects cloud-provider-specific metrics not covered by
+// the built-in collectors (e.g. AWS CloudWatch agent metrics, GCP
+// Ops Agent metrics). Implement it and register an instance with
+// Collector.RegisterPlugin to have its values merged into every
+// MetricsPayload.CustomMetrics.
+//
+// A third-party plugin should:
+//   - give Name() a short, stable, lowercase identifier — it is not
+//     currently used to namespace metric keys, so pick keys in Collect
+//     that won't collide with another registered plugin's;
+//   - honor ctx's deadline (see pluginCollectTimeout) rather than blocking
+//     indefinitely on a slow network call;
+//   - return a partial map plus an error is fine — Collector.Collect skips
+//     a plugin's contribution entirely on error rather than failing the
+//     whole payload, so partial results from a successful call are the
+//     only way to report some-but-not-all metrics for that cycle.
+type MetricsPlugin interface {
+	Name() string
+	Collect(ctx context.Context) (map[string]float64, error)
+}
+
+// NullPlugin is a MetricsPlugin that collects nothing, useful as a test
+// double or as a documented no-op starting point for a new plugin.
+type NullPlugin struct {
+	// PluginName is returned by Name. Defaults to "null" if empty.
+	PluginName string
+}
+
+// Name returns p.PluginName, or "null" if unset.
+func (p NullPlugin) Name() string {
+	if p.PluginName == "" {
+		return "null"
+	}
+	return p.PluginName
+}
+
+// Collect returns an empty metric set and no error.
+func (p NullPlugin) Collect(ctx context.Context) (map[string]float64, error) {
+	return nil, nil
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"time"
+)
+
+// Metric name constants for use as ThresholdConfig.Metrics keys.
+const (
+	ThresholdMetricCPUPercent    = "cpu_percent"
+	ThresholdMetricMemoryPercent = "memory_percent"
+	ThresholdMetricDiskPercent   = "disk_percent"
+)
+
+// ThresholdConfig configures WatchThresholds: which metrics to watch (see
+// the ThresholdMetric* constants), the percentage value each breaches
+// above, and how often to sample.
+type ThresholdConfig struct {
+	Interval time.Duration
+	Metrics  map[string]float64
+}
+
+// ThresholdBreach describes a metric crossing its configured threshold,
+// in either direction.
+type ThresholdBreach struct {
+	// Metric is the ThresholdConfig.Metrics key that crossed its
+	// threshold.
+	Metric    string
+	Value     float64
+	Threshold float64
+
+	// Rising is true when Value crossed above Threshold, false when it
+	// fell back below it.
+	Rising bool
+}
+
+// Sampler is the subset of Collector's behavior WatchThresholds depends
+// on, letting tests inject a mock instead of collecting real host
+// metrics.
+type Sampler interface {
+	Collect() (*MetricsPayload, error)
+}
+
+var _ Sampler = (*Collector)(nil)
+
+// WatchThresholds runs until ctx is canceled, calling source.Collect at
+// cfg.Interval and invoking fn — asynchronously, in its own goroutine —
+// each time a configured metric crosses its threshold: once on the
+// rising crossing, and once more on the falling crossing back below it.
+// A Collect error is skipped rather than stopping the watch.
+func WatchThresholds(ctx context.Context, source Sampler, cfg ThresholdConfig, fn func(ThresholdBreach)) {
+	breached := make(map[string]bool, len(cfg.Metrics))
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			payload, err := source.Collect()
+			if err != nil {
+				continue
+			}
+			for metric, threshold := range cfg.Metrics {
+				value, ok := metricValue(payload, metric)
+				if !ok {
+					continue
+				}
+				above := value >= threshold
+				if above == breached[metric] {
+					continue
+				}
+				breached[metric] = above
+				go fn(ThresholdBreach{Metric: metric, Value: value, Threshold: threshold, Rising: above})
+			}
+		}
+	}
+}
+
+// metricValue extracts the named metric's current value from payload, or
+// returns (0, false) if metric is unrecognized or its denominator is
+// zero (e.g. disk stats unavailable).
+func metricValue(payload *MetricsPayload, metric string) (float64, bool) {
+	switch metric {
+	case ThresholdMetricCPUPercent:
+		return payload.CPUPercent, true
+	case ThresholdMetricMemoryPercent:
+		if payload.MemoryTotalBytes == 0 {
+			return 0, false
+		}
+		return float64(payload.MemoryUsedBytes) / float64(payload.MemoryTotalBytes) * 100, true
+	case ThresholdMetricDiskPercent:
+		if payload.DiskTotalBytes == 0 {
+			return 0, false
+		}
+		return float64(payload.DiskUsedBytes) / float64(payload.DiskTotalBytes) * 100, true
+	default:
+		return 0, false
+	}
+}
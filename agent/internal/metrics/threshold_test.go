@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSampler returns each of values in order on successive Collect
+// calls, then repeats the last value.
+type fakeSampler struct {
+	mu     sync.Mutex
+	values []float64
+	i      int
+}
+
+func (f *fakeSampler) Collect() (*MetricsPayload, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v := f.values[f.i]
+	if f.i < len(f.values)-1 {
+		f.i++
+	}
+	return &MetricsPayload{CPUPercent: v}, nil
+}
+
+func TestWatchThresholdsFiresBreachThenClearInOrder(t *testing.T) {
+	sampler := &fakeSampler{values: []float64{40, 60, 40}}
+	cfg := ThresholdConfig{
+		Interval: 10 * time.Millisecond,
+		Metrics:  map[string]float64{ThresholdMetricCPUPercent: 50},
+	}
+
+	var mu sync.Mutex
+	var breaches []ThresholdBreach
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		WatchThresholds(ctx, sampler, cfg, func(b ThresholdBreach) {
+			mu.Lock()
+			breaches = append(breaches, b)
+			mu.Unlock()
+		})
+		close(done)
+	}()
+	<-done
+	time.Sleep(20 * time.Millisecond) // let async fn callbacks finish
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(breaches) < 2 {
+		t.Fatalf("got %d breach callbacks, want at least 2 (rising then falling)", len(breaches))
+	}
+	if !breaches[0].Rising {
+		t.Errorf("breaches[0].Rising = false, want true (crossing above threshold)")
+	}
+	if breaches[0].Metric != ThresholdMetricCPUPercent || breaches[0].Value != 60 || breaches[0].Threshold != 50 {
+		t.Errorf("breaches[0] = %+v, want Metric=%s Value=60 Threshold=50", breaches[0], ThresholdMetricCPUPercent)
+	}
+	if breaches[1].Rising {
+		t.Errorf("breaches[1].Rising = true, want false (crossing back below threshold)")
+	}
+}
+
+func TestWatchThresholdsSkipsUnrecognizedMetric(t *testing.T) {
+	sampler := &fakeSampler{values: []float64{100}}
+	cfg := ThresholdConfig{
+		Interval: 10 * time.Millisecond,
+		Metrics:  map[string]float64{"bogus_metric": 1},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 40*time.Millisecond)
+	defer cancel()
+
+	called := false
+	WatchThresholds(ctx, sampler, cfg, func(ThresholdBreach) { called = true })
+	if called {
+		t.Error("fn called for an unrecognized metric, want it skipped")
+	}
+}
@@ -0,0 +1,208 @@
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicksPerSecond is the kernel's USER_HZ value used to convert the
+// jiffie counters in /proc/[pid]/stat into seconds. It is fixed at 100 on
+// every Linux platform this agent targets.
+const clockTicksPerSecond = 100
+
+// TopProcess reports a single process's resource usage as of the most
+// recent Collect call, computed from two successive /proc/[pid]/stat
+// samples.
+type TopProcess struct {
+	PID         int32   `json:"pid"`
+	Name        string  `json:"name"`
+	CPUPercent  float64 `json:"cpu_percent"`
+	MemRSSBytes uint64  `json:"mem_rss_bytes"`
+	Status      string  `json:"status"`
+}
+
+// processTimes holds a single process's cumulative CPU jiffie counter at a
+// point in time.
+type processTimes struct {
+	ticks uint64
+}
+
+// processStatusToState maps /proc/[pid]/stat's single-character process
+// state field to a human-readable status, per proc(5).
+var processStatusToState = map[byte]string{
+	'R': "running",
+	'S': "sleeping",
+	'D': "disk-sleep",
+	'Z': "zombie",
+	'T': "stopped",
+	't': "tracing-stop",
+	'X': "dead",
+	'I': "idle",
+}
+
+// TopProcessCollector reports the N most CPU-hungry processes on the host,
+// computed from successive /proc/[pid]/stat samples, mirroring
+// DiskIOCollector's before/after delta approach since the kernel only
+// exposes cumulative counters.
+type TopProcessCollector struct {
+	n        int
+	prev     map[int32]processTimes
+	prevTime time.Time
+}
+
+// NewTopProcessCollector returns a collector that reports the top n
+// processes by CPU usage. Its first Collect call reports no processes
+// (there is nothing to diff against yet).
+func NewTopProcessCollector(n int) *TopProcessCollector {
+	return &TopProcessCollector{n: n}
+}
+
+// Collect reads the current per-process CPU counters and returns the top N
+// TopProcess entries by CPU percentage since the previous call.
+func (c *TopProcessCollector) Collect() ([]TopProcess, error) {
+	snap, err := readProcesses()
+	if err != nil {
+		return nil, err
+	}
+	return c.collectAt(snap, time.Now()), nil
+}
+
+// procSnapshot is a single process's identity and cumulative counters as of
+// one /proc scan.
+type procSnapshot struct {
+	pid    int32
+	name   string
+	status string
+	ticks  uint64
+	rss    uint64
+}
+
+// collectAt is a test seam: it computes TopProcess entries as if snap were
+// collected at the given time, without touching the real filesystem.
+func (c *TopProcessCollector) collectAt(snap []procSnapshot, now time.Time) []TopProcess {
+	var processes []TopProcess
+	if c.prev != nil {
+		elapsedSeconds := now.Sub(c.prevTime).Seconds()
+		for _, cur := range snap {
+			prev, ok := c.prev[cur.pid]
+			if !ok || elapsedSeconds <= 0 {
+				continue
+			}
+			deltaTicks := diskCounterDelta(prev.ticks, cur.ticks)
+			cpuPercent := float64(deltaTicks) / clockTicksPerSecond / elapsedSeconds * 100
+			processes = append(processes, TopProcess{
+				PID:         cur.pid,
+				Name:        cur.name,
+				CPUPercent:  cpuPercent,
+				MemRSSBytes: cur.rss,
+				Status:      cur.status,
+			})
+		}
+		sort.Slice(processes, func(i, j int) bool {
+			return processes[i].CPUPercent > processes[j].CPUPercent
+		})
+		if len(processes) > c.n {
+			processes = processes[:c.n]
+		}
+	}
+
+	prev := make(map[int32]processTimes, len(snap))
+	for _, s := range snap {
+		prev[s.pid] = processTimes{ticks: s.ticks}
+	}
+	c.prev = prev
+	c.prevTime = now
+	return processes
+}
+
+// readProcesses scans /proc for numeric PID directories and parses each
+// process's /proc/[pid]/stat and /proc/[pid]/status. A process that exits
+// mid-scan is skipped rather than failing the whole collection.
+func readProcesses() ([]procSnapshot, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, fmt.Errorf("metrics: read /proc: %w", err)
+	}
+
+	var snapshots []procSnapshot
+	for _, entry := range entries {
+		pid, err := strconv.ParseInt(entry.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		snap, ok, err := readProcessStat(int32(pid))
+		if err != nil || !ok {
+			continue
+		}
+		snap.rss = readProcessRSS(int32(pid))
+		snapshots = append(snapshots, snap)
+	}
+	return snapshots, nil
+}
+
+// readProcessStat parses /proc/[pid]/stat for the process name, state, and
+// cumulative CPU jiffies (utime + stime, fields 14 and 15). The comm field
+// (fields[1]) is parenthesized and may itself contain spaces or
+// parentheses, so it's located by the last ")" rather than a fixed field
+// index.
+func readProcessStat(pid int32) (procSnapshot, bool, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return procSnapshot{}, false, nil
+	}
+
+	line := string(data)
+	open := strings.IndexByte(line, '(')
+	close := strings.LastIndexByte(line, ')')
+	if open < 0 || close < 0 || close < open {
+		return procSnapshot{}, false, fmt.Errorf("metrics: malformed /proc/%d/stat", pid)
+	}
+	name := line[open+1 : close]
+	fields := strings.Fields(line[close+1:])
+	if len(fields) < 14 {
+		return procSnapshot{}, false, fmt.Errorf("metrics: /proc/%d/stat has too few fields", pid)
+	}
+
+	state := processStatusToState[fields[0][0]]
+	if state == "" {
+		state = "unknown"
+	}
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+
+	return procSnapshot{
+		pid:    pid,
+		name:   name,
+		status: state,
+		ticks:  utime + stime,
+	}, true, nil
+}
+
+// readProcessRSS reads the resident set size (in bytes) from
+// /proc/[pid]/status' VmRSS line, reported there in KiB. It returns 0 if
+// the process has since exited or the field is missing.
+func readProcessRSS(pid int32) uint64 {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "VmRSS:") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return 0
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0
+		}
+		return kb * 1024
+	}
+	return 0
+}
@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTopProcessCollectorFirstCallReportsNoProcesses(t *testing.T) {
+	c := NewTopProcessCollector(5)
+	snap := []procSnapshot{{pid: 1, name: "init", status: "sleeping", ticks: 100}}
+	processes := c.collectAt(snap, time.Now())
+	if len(processes) != 0 {
+		t.Errorf("expected no processes on the first call, got %+v", processes)
+	}
+}
+
+func TestTopProcessCollectorComputesCPUPercentAndSortsDescending(t *testing.T) {
+	before := []procSnapshot{
+		{pid: 1, name: "quiet", status: "sleeping", ticks: 100, rss: 1024},
+		{pid: 2, name: "busy", status: "running", ticks: 100, rss: 2048},
+	}
+	after := []procSnapshot{
+		{pid: 1, name: "quiet", status: "sleeping", ticks: 105, rss: 1024},
+		{pid: 2, name: "busy", status: "running", ticks: 300, rss: 4096},
+	}
+
+	c := NewTopProcessCollector(5)
+	start := time.Unix(0, 0)
+	c.collectAt(before, start)
+	processes := c.collectAt(after, start.Add(time.Second))
+
+	if len(processes) != 2 {
+		t.Fatalf("expected 2 processes, got %d: %+v", len(processes), processes)
+	}
+	if processes[0].PID != 2 || processes[0].Name != "busy" {
+		t.Errorf("expected busy (pid 2) first, got %+v", processes[0])
+	}
+	// deltaTicks=200, clockTicksPerSecond=100, elapsedSeconds=1 -> 200%.
+	if processes[0].CPUPercent != 200 {
+		t.Errorf("CPUPercent = %v, want 200", processes[0].CPUPercent)
+	}
+	if processes[0].MemRSSBytes != 4096 {
+		t.Errorf("MemRSSBytes = %v, want 4096", processes[0].MemRSSBytes)
+	}
+	if processes[1].PID != 1 {
+		t.Errorf("expected quiet (pid 1) second, got %+v", processes[1])
+	}
+}
+
+func TestTopProcessCollectorTruncatesToN(t *testing.T) {
+	before := []procSnapshot{
+		{pid: 1, name: "a", status: "running", ticks: 0},
+		{pid: 2, name: "b", status: "running", ticks: 0},
+		{pid: 3, name: "c", status: "running", ticks: 0},
+	}
+	after := []procSnapshot{
+		{pid: 1, name: "a", status: "running", ticks: 100},
+		{pid: 2, name: "b", status: "running", ticks: 200},
+		{pid: 3, name: "c", status: "running", ticks: 300},
+	}
+
+	c := NewTopProcessCollector(2)
+	start := time.Unix(0, 0)
+	c.collectAt(before, start)
+	processes := c.collectAt(after, start.Add(time.Second))
+
+	if len(processes) != 2 {
+		t.Fatalf("expected 2 processes (truncated to n), got %d: %+v", len(processes), processes)
+	}
+	if processes[0].PID != 3 || processes[1].PID != 2 {
+		t.Errorf("expected pids [3, 2], got [%d, %d]", processes[0].PID, processes[1].PID)
+	}
+}
+
+func TestTopProcessCollectorSkipsProcessDisappearedBetweenSamples(t *testing.T) {
+	before := []procSnapshot{
+		{pid: 1, name: "a", status: "running", ticks: 0},
+		{pid: 2, name: "gone", status: "running", ticks: 0},
+	}
+	// pid 2 exited between samples.
+	after := []procSnapshot{
+		{pid: 1, name: "a", status: "running", ticks: 100},
+	}
+
+	c := NewTopProcessCollector(5)
+	start := time.Unix(0, 0)
+	c.collectAt(before, start)
+	processes := c.collectAt(after, start.Add(time.Second))
+
+	if len(processes) != 1 || processes[0].PID != 1 {
+		t.Errorf("expected only pid 1, got %+v", processes)
+	}
+}
+
+func TestReadProcessStatParsesRealInitProcess(t *testing.T) {
+	snap, ok, err := readProcessStat(int32(1))
+	if err != nil {
+		t.Fatalf("readProcessStat: %v", err)
+	}
+	if !ok {
+		t.Skip("pid 1 not readable in this sandbox")
+	}
+	if snap.name == "" {
+		t.Error("expected a non-empty process name")
+	}
+	if snap.status == "" {
+		t.Error("expected a non-empty status")
+	}
+}
+
+func TestReadProcessStatReturnsFalseForNonexistentPID(t *testing.T) {
+	_, ok, err := readProcessStat(int32(1 << 30))
+	if err != nil {
+		t.Fatalf("readProcessStat: %v", err)
+	}
+	if ok {
+		t.Error("expected ok=false for a nonexistent pid")
+	}
+}
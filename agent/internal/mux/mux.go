@@ -0,0 +1,88 @@
+// Package mux lets several logical features (heartbeat, metrics, terminal)
+// share a single websocket.Client connection instead of each opening its
+// own, for environments that limit outbound WebSocket connections per host.
+// Envelopes are routed to a registered Handler by their ChannelID field.
+package mux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+	"github.com/pacphi/sindri/agent/internal/websocket"
+)
+
+// ErrChannelNotRegistered is returned by Send when no Handler has been
+// registered for the given channel ID.
+var ErrChannelNotRegistered = errors.New("mux: channel not registered")
+
+// Handler processes envelopes routed to a single channel.
+type Handler interface {
+	HandleEnvelope(env *protocol.Envelope) error
+}
+
+// Client wraps a websocket.Client, multiplexing multiple channels' envelopes
+// over it. Each feature registers a Handler under its own channel ID; Run
+// dispatches inbound envelopes to the matching Handler, and Send tags
+// outbound envelopes with their channel ID before delegating to the
+// underlying websocket.Client.
+type Client struct {
+	ws *websocket.Client
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+// NewClient returns a Client that multiplexes channels over ws.
+func NewClient(ws *websocket.Client) *Client {
+	return &Client{
+		ws:       ws,
+		handlers: make(map[string]Handler),
+	}
+}
+
+// RegisterHandler registers h to receive envelopes for channelID, replacing
+// any Handler previously registered for that channel.
+func (c *Client) RegisterHandler(channelID string, h Handler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[channelID] = h
+}
+
+// Send tags env with channelID and delegates to the underlying
+// websocket.Client's SendWithRetry.
+func (c *Client) Send(channelID string, env *protocol.Envelope, opts websocket.SendOptions) error {
+	env.ChannelID = channelID
+	return c.ws.SendWithRetry(env, opts)
+}
+
+// Run repeatedly calls the underlying websocket.Client's Receive and
+// dispatches each envelope to the Handler registered for its ChannelID. It
+// returns when ctx is done or Receive returns an error. An envelope whose
+// ChannelID has no registered Handler is dropped.
+func (c *Client) Run(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		env, err := c.ws.Receive()
+		if err != nil {
+			return fmt.Errorf("mux: receive: %w", err)
+		}
+
+		c.dispatch(env)
+	}
+}
+
+func (c *Client) dispatch(env *protocol.Envelope) {
+	c.mu.RLock()
+	h, ok := c.handlers[env.ChannelID]
+	c.mu.RUnlock()
+	if !ok {
+		return
+	}
+	_ = h.HandleEnvelope(env)
+}
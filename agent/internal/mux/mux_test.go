@@ -0,0 +1,58 @@
+package mux
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+	"github.com/pacphi/sindri/agent/internal/websocket"
+)
+
+type recordingHandler struct {
+	mu   sync.Mutex
+	envs []*protocol.Envelope
+}
+
+func (h *recordingHandler) HandleEnvelope(env *protocol.Envelope) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.envs = append(h.envs, env)
+	return nil
+}
+
+func (h *recordingHandler) received() []*protocol.Envelope {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.envs
+}
+
+func TestDispatchRoutesEnvelopeToRegisteredChannel(t *testing.T) {
+	c := NewClient(websocket.NewClient())
+	heartbeat := &recordingHandler{}
+	metrics := &recordingHandler{}
+	c.RegisterHandler("heartbeat", heartbeat)
+	c.RegisterHandler("metrics", metrics)
+
+	c.dispatch(&protocol.Envelope{ChannelID: "heartbeat", Type: "hb"})
+	c.dispatch(&protocol.Envelope{ChannelID: "metrics", Type: "m1"})
+	c.dispatch(&protocol.Envelope{ChannelID: "metrics", Type: "m2"})
+
+	if got := heartbeat.received(); len(got) != 1 || got[0].Type != "hb" {
+		t.Errorf("heartbeat handler received %v, want one envelope of type hb", got)
+	}
+	if got := metrics.received(); len(got) != 2 || got[0].Type != "m1" || got[1].Type != "m2" {
+		t.Errorf("metrics handler received %v, want [m1 m2]", got)
+	}
+}
+
+func TestDispatchDropsEnvelopeForUnregisteredChannel(t *testing.T) {
+	c := NewClient(websocket.NewClient())
+	registered := &recordingHandler{}
+	c.RegisterHandler("registered", registered)
+
+	c.dispatch(&protocol.Envelope{ChannelID: "unknown", Type: "x"})
+
+	if got := registered.received(); len(got) != 0 {
+		t.Errorf("registered handler received %v, want none", got)
+	}
+}
@@ -0,0 +1,151 @@
+package netdiag
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+// routeMaxHops bounds how many hops routeTest traces before giving up.
+const routeMaxHops = 5
+
+// routeHopTimeout bounds how long routeTest waits for a single hop's
+// reply before moving to the next TTL.
+const routeHopTimeout = 2 * time.Second
+
+// routeTest traces the first routeMaxHops hops to diagHost by sending an
+// ICMP echo request with an increasing TTL and observing which hop replies
+// (either a TTL-exceeded from an intermediate router, or an echo reply
+// from diagHost itself). It requires a raw ICMP socket, which needs root
+// on most systems; if opening the socket fails, that's reported as the
+// test's error rather than treated as a crash.
+func routeTest(ctx context.Context) protocol.DiagResult {
+	return timed(func() error {
+		conn, err := icmp.ListenPacket("ip4:icmp", "0.0.0.0")
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrRequiresRoot, err)
+		}
+		defer conn.Close()
+
+		dst, err := net.ResolveIPAddr("ip4", diagHost)
+		if err != nil {
+			return fmt.Errorf("resolve %s: %w", diagHost, err)
+		}
+
+		p := conn.IPv4PacketConn()
+		id := os.Getpid() & 0xffff
+
+		for ttl := 1; ttl <= routeMaxHops; ttl++ {
+			if err := p.SetTTL(ttl); err != nil {
+				return fmt.Errorf("set ttl %d: %w", ttl, err)
+			}
+
+			msg := icmp.Message{
+				Type: ipv4.ICMPTypeEcho,
+				Code: 0,
+				Body: &icmp.Echo{ID: id, Seq: ttl, Data: []byte("sindri-netdiag")},
+			}
+			wb, err := msg.Marshal(nil)
+			if err != nil {
+				return fmt.Errorf("marshal echo request: %w", err)
+			}
+			if _, err := conn.WriteTo(wb, dst); err != nil {
+				return fmt.Errorf("write echo request (ttl=%d): %w", ttl, err)
+			}
+
+			if err := conn.SetReadDeadline(time.Now().Add(routeHopTimeout)); err != nil {
+				return fmt.Errorf("set read deadline: %w", err)
+			}
+			rb := make([]byte, 1500)
+			n, peer, err := conn.ReadFrom(rb)
+			if err != nil {
+				// No reply from this hop within the deadline; move on to
+				// the next one rather than failing the whole trace.
+				continue
+			}
+
+			reply, err := icmp.ParseMessage(1 /* ipv4.ICMPTypeEchoReply.Protocol() */, rb[:n])
+			if err != nil {
+				continue
+			}
+			if reply.Type == ipv4.ICMPTypeEchoReply && peer.String() == dst.String() {
+				return nil
+			}
+			if reply.Type == ipv4.ICMPTypeTimeExceeded {
+				continue
+			}
+		}
+		return fmt.Errorf("no reply from %s within %d hops", diagHost, routeMaxHops)
+	})
+}
+
+// mtuProbeSizes are the payload sizes (bytes) mtuTest tries, from largest
+// to smallest, stopping at the first one that gets through unfragmented.
+// 1472 is the largest ICMP echo payload that fits an unfragmented Ethernet
+// frame (1500 MTU minus the 20-byte IP and 8-byte ICMP headers); 512 and
+// 68 cover common tunnel/VPN and legacy minimum-MTU paths.
+var mtuProbeSizes = []int{1472, 1400, 1200, 512, 68}
+
+// mtuTest finds the largest of mtuProbeSizes that gets an echo reply from
+// diagHost, the way path MTU discovery does: a probe too large for some
+// hop along the path either goes unanswered or triggers an ICMP
+// "fragmentation needed" error, and mtuTest moves on to the next smaller
+// size. It requires the same raw ICMP socket as routeTest.
+func mtuTest(ctx context.Context) protocol.DiagResult {
+	return timed(func() error {
+		conn, err := icmp.ListenPacket("ip4:icmp", "0.0.0.0")
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrRequiresRoot, err)
+		}
+		defer conn.Close()
+
+		dst, err := net.ResolveIPAddr("ip4", diagHost)
+		if err != nil {
+			return fmt.Errorf("resolve %s: %w", diagHost, err)
+		}
+
+		p := conn.IPv4PacketConn()
+		if err := p.SetTTL(64); err != nil {
+			return fmt.Errorf("set ttl: %w", err)
+		}
+
+		id := os.Getpid() & 0xffff
+		for i, size := range mtuProbeSizes {
+			msg := icmp.Message{
+				Type: ipv4.ICMPTypeEcho,
+				Code: 0,
+				Body: &icmp.Echo{ID: id, Seq: i, Data: make([]byte, size)},
+			}
+			wb, err := msg.Marshal(nil)
+			if err != nil {
+				return fmt.Errorf("marshal echo request (size=%d): %w", size, err)
+			}
+			if _, err := conn.WriteTo(wb, dst); err != nil {
+				return fmt.Errorf("write echo request (size=%d): %w", size, err)
+			}
+
+			if err := conn.SetReadDeadline(time.Now().Add(routeHopTimeout)); err != nil {
+				return fmt.Errorf("set read deadline: %w", err)
+			}
+			rb := make([]byte, 1500)
+			n, peer, err := conn.ReadFrom(rb)
+			if err != nil {
+				continue
+			}
+			reply, err := icmp.ParseMessage(1, rb[:n])
+			if err != nil {
+				continue
+			}
+			if reply.Type == ipv4.ICMPTypeEchoReply && peer.String() == dst.String() {
+				return nil
+			}
+		}
+		return fmt.Errorf("no probe size reached %s unfragmented", diagHost)
+	})
+}
@@ -0,0 +1,124 @@
+// Package netdiag runs on-demand network diagnostics on the agent's
+// behalf (MsgNetworkDiagRequest), so an operator can tell whether a
+// connectivity problem is on the instance's side of the network without
+// shelling in.
+package netdiag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// diagHost is the hostname the dns, tcp_connect, http_get, route, and mtu
+// tests probe, since MsgNetworkDiagRequest doesn't carry a target of its
+// own.
+const diagHost = "sindri.io"
+
+// testTimeout bounds how long a single test is allowed to take.
+const testTimeout = 5 * time.Second
+
+// Test runs a single named network diagnostic and reports its outcome.
+type Test interface {
+	Run(ctx context.Context) protocol.DiagResult
+}
+
+// TestFunc adapts a plain function to a Test.
+type TestFunc func(ctx context.Context) protocol.DiagResult
+
+// Run calls f.
+func (f TestFunc) Run(ctx context.Context) protocol.DiagResult {
+	return f(ctx)
+}
+
+// DefaultTests returns the built-in Test implementations, keyed the same
+// way MsgNetworkDiagRequest.Tests names them.
+func DefaultTests() map[string]Test {
+	return map[string]Test{
+		"dns":         TestFunc(dnsTest),
+		"tcp_connect": TestFunc(tcpConnectTest),
+		"http_get":    TestFunc(httpGetTest),
+		"route":       TestFunc(routeTest),
+		"mtu":         TestFunc(mtuTest),
+	}
+}
+
+// Handle runs each test named in req.Tests (looked up in tests) and
+// aggregates their results. A name not present in tests is reported as a
+// failed DiagResult rather than skipped, so the Console can tell the
+// difference between "test not supported by this agent build" and "test
+// passed silently".
+func Handle(ctx context.Context, req protocol.MsgNetworkDiagRequest, tests map[string]Test) protocol.MsgNetworkDiagResponse {
+	results := make(map[string]protocol.DiagResult, len(req.Tests))
+	for _, name := range req.Tests {
+		test, ok := tests[name]
+		if !ok {
+			results[name] = protocol.DiagResult{Success: false, Error: fmt.Sprintf("netdiag: unsupported test %q", name)}
+			continue
+		}
+		results[name] = test.Run(ctx)
+	}
+	return protocol.MsgNetworkDiagResponse{
+		RequestID: req.RequestID,
+		Results:   results,
+	}
+}
+
+// timed runs fn, wrapping its outcome (and elapsed time) in a DiagResult.
+func timed(fn func() error) protocol.DiagResult {
+	start := time.Now()
+	err := fn()
+	latency := time.Since(start).String()
+	if err != nil {
+		return protocol.DiagResult{Success: false, Latency: latency, Error: err.Error()}
+	}
+	return protocol.DiagResult{Success: true, Latency: latency}
+}
+
+func dnsTest(ctx context.Context) protocol.DiagResult {
+	ctx, cancel := context.WithTimeout(ctx, testTimeout)
+	defer cancel()
+	return timed(func() error {
+		_, err := net.DefaultResolver.LookupHost(ctx, diagHost)
+		return err
+	})
+}
+
+func tcpConnectTest(ctx context.Context) protocol.DiagResult {
+	ctx, cancel := context.WithTimeout(ctx, testTimeout)
+	defer cancel()
+	return timed(func() error {
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", net.JoinHostPort(diagHost, "443"))
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
+}
+
+func httpGetTest(ctx context.Context) protocol.DiagResult {
+	ctx, cancel := context.WithTimeout(ctx, testTimeout)
+	defer cancel()
+	return timed(func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+diagHost, nil)
+		if err != nil {
+			return err
+		}
+		client := &http.Client{Timeout: testTimeout}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	})
+}
+
+// ErrRequiresRoot is returned (wrapped) by the route and mtu tests when
+// they can't open the raw ICMP socket both require, which on most systems
+// needs root (or CAP_NET_RAW).
+var ErrRequiresRoot = errors.New("netdiag: requires root to open a raw ICMP socket")
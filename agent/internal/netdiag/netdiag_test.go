@@ -0,0 +1,77 @@
+package netdiag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestHandleAggregatesResultsFromRequestedTests(t *testing.T) {
+	tests := map[string]Test{
+		"dns":         TestFunc(func(ctx context.Context) protocol.DiagResult { return protocol.DiagResult{Success: true, Latency: "1ms"} }),
+		"tcp_connect": TestFunc(func(ctx context.Context) protocol.DiagResult { return protocol.DiagResult{Success: false, Error: "connection refused"} }),
+	}
+
+	resp := Handle(context.Background(), protocol.MsgNetworkDiagRequest{
+		RequestID: "req-1",
+		Tests:     []string{"dns", "tcp_connect"},
+	}, tests)
+
+	if resp.RequestID != "req-1" {
+		t.Errorf("RequestID = %q, want %q", resp.RequestID, "req-1")
+	}
+	if len(resp.Results) != 2 {
+		t.Fatalf("len(Results) = %d, want 2", len(resp.Results))
+	}
+	if !resp.Results["dns"].Success {
+		t.Errorf("dns result = %+v, want Success=true", resp.Results["dns"])
+	}
+	if resp.Results["tcp_connect"].Success || resp.Results["tcp_connect"].Error != "connection refused" {
+		t.Errorf("tcp_connect result = %+v, want Success=false Error=connection refused", resp.Results["tcp_connect"])
+	}
+}
+
+func TestHandleReportsUnsupportedTestAsFailure(t *testing.T) {
+	resp := Handle(context.Background(), protocol.MsgNetworkDiagRequest{
+		RequestID: "req-2",
+		Tests:     []string{"quantum_ping"},
+	}, map[string]Test{})
+
+	result, ok := resp.Results["quantum_ping"]
+	if !ok {
+		t.Fatal("expected a result for the unsupported test name")
+	}
+	if result.Success {
+		t.Errorf("result.Success = true, want false for an unsupported test")
+	}
+	if result.Error == "" {
+		t.Error("result.Error is empty, want an explanation of the unsupported test")
+	}
+}
+
+func TestHandleOnlyRunsRequestedTests(t *testing.T) {
+	ran := map[string]bool{}
+	tests := map[string]Test{
+		"dns":      TestFunc(func(ctx context.Context) protocol.DiagResult { ran["dns"] = true; return protocol.DiagResult{Success: true} }),
+		"http_get": TestFunc(func(ctx context.Context) protocol.DiagResult { ran["http_get"] = true; return protocol.DiagResult{Success: true} }),
+	}
+
+	Handle(context.Background(), protocol.MsgNetworkDiagRequest{Tests: []string{"dns"}}, tests)
+
+	if !ran["dns"] {
+		t.Error("expected the dns test to run")
+	}
+	if ran["http_get"] {
+		t.Error("http_get test ran but was not requested")
+	}
+}
+
+func TestDefaultTestsIncludesAllKnownNames(t *testing.T) {
+	tests := DefaultTests()
+	for _, name := range []string{"dns", "tcp_connect", "http_get", "route", "mtu"} {
+		if _, ok := tests[name]; !ok {
+			t.Errorf("DefaultTests() is missing %q", name)
+		}
+	}
+}
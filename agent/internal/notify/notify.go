@@ -0,0 +1,63 @@
+// Package notify sends best-effort webhook notifications for connection
+// lifecycle events.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Event describes a connection lifecycle event posted to the reconnect
+// notify webhook.
+type Event struct {
+	Type      string    `json:"type"`
+	Reason    string    `json:"reason,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Notifier posts Events to a configured webhook URL. A zero-value Notifier
+// with an empty URL is a no-op.
+type Notifier struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// NewNotifier returns a Notifier posting to url. An empty url disables
+// notifications.
+func NewNotifier(url string) *Notifier {
+	return &Notifier{URL: url, HTTPClient: http.DefaultClient}
+}
+
+// Notify posts event to the webhook URL, if one is configured. Delivery is
+// best-effort: failures are returned to the caller to log, not retried,
+// since a missed connection-event notification is not worth blocking the
+// reconnect path over.
+func (n *Notifier) Notify(event Event) error {
+	if n == nil || n.URL == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("notify: marshal event: %w", err)
+	}
+
+	client := n.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Post(n.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notify: post event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNotifyPostsEvent(t *testing.T) {
+	received := make(chan Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("decode webhook body: %v", err)
+		}
+		received <- event
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := NewNotifier(server.URL)
+	if err := n.Notify(Event{Type: "reconnected", Reason: "network restored"}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	select {
+	case event := <-received:
+		if event.Type != "reconnected" {
+			t.Errorf("got type %q, want reconnected", event.Type)
+		}
+	default:
+		t.Fatal("webhook was not called")
+	}
+}
+
+func TestNotifyNoopWithoutURL(t *testing.T) {
+	n := NewNotifier("")
+	if err := n.Notify(Event{Type: "reconnected"}); err != nil {
+		t.Fatalf("Notify with no URL should be a no-op, got: %v", err)
+	}
+}
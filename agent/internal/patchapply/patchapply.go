@@ -0,0 +1,175 @@
+// Package patchapply applies unified diff patches (the output of `diff -u`)
+// to files under a configured filesystem.Root, guarding against patching
+// stale content and writing the result atomically.
+package patchapply
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sourcegraph/go-diff/diff"
+
+	"github.com/pacphi/sindri/agent/internal/filesystem"
+)
+
+// ErrSourceHashMismatch is returned by Apply when a patch's expected
+// source hash (from its "---" header, see Apply) does not match the
+// current content of the file being patched, meaning the patch was
+// generated against content that has since changed.
+var ErrSourceHashMismatch = errors.New("patchapply: source file hash does not match patch")
+
+// Apply applies unifiedDiff to the file at path (resolved against root),
+// atomically (write to temp, rename). unifiedDiff must be the output of
+// `diff -u`, with its "---" header line extended with a "sha256:<hex>"
+// field carrying the expected sha256 of the file's current content, e.g.:
+//
+//	--- path/to/file.txt	sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824
+//	+++ path/to/file.txt
+//	@@ -1,3 +1,3 @@
+//	...
+//
+// If the hash is present and does not match, Apply returns
+// ErrSourceHashMismatch without modifying the file. It returns the total
+// number of added and removed lines (each "+"/"-" hunk line counts once)
+// on success.
+func Apply(root *filesystem.Root, path string, unifiedDiff string) (linesChanged int, err error) {
+	resolved, err := root.Resolve(path)
+	if err != nil {
+		return 0, err
+	}
+
+	original, err := os.ReadFile(resolved)
+	if err != nil {
+		return 0, fmt.Errorf("patchapply: read %q: %w", path, err)
+	}
+
+	wantHash, hunkText, err := splitHeader(unifiedDiff)
+	if err != nil {
+		return 0, err
+	}
+	if wantHash != "" && wantHash != hashOf(original) {
+		return 0, ErrSourceHashMismatch
+	}
+
+	hunks, err := diff.ParseHunks([]byte(hunkText))
+	if err != nil {
+		return 0, fmt.Errorf("patchapply: parse hunks: %w", err)
+	}
+
+	patched, changed, err := applyHunks(original, hunks)
+	if err != nil {
+		return 0, fmt.Errorf("patchapply: apply hunks to %q: %w", path, err)
+	}
+
+	mode := os.FileMode(0o644)
+	if info, statErr := os.Stat(resolved); statErr == nil {
+		mode = info.Mode()
+	}
+	if err := filesystem.WriteFileAtomic(root, path, patched, mode); err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
+
+// hashOf returns the lowercase hex-encoded sha256 of data.
+func hashOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// splitHeader extracts the expected source hash (if any) from unifiedDiff's
+// "---" header line and returns the remaining hunk-only text (everything
+// after the "---"/"+++" header lines), suitable for diff.ParseHunks.
+func splitHeader(unifiedDiff string) (wantHash, hunkText string, err error) {
+	origLine, rest, ok := strings.Cut(unifiedDiff, "\n")
+	if !ok || !strings.HasPrefix(origLine, "--- ") {
+		return "", "", fmt.Errorf("patchapply: diff missing \"---\" header")
+	}
+	newLine, hunkText, ok := strings.Cut(rest, "\n")
+	if !ok || !strings.HasPrefix(newLine, "+++ ") {
+		return "", "", fmt.Errorf("patchapply: diff missing \"+++\" header")
+	}
+
+	for _, field := range strings.Fields(strings.TrimPrefix(origLine, "--- ")) {
+		if hash, ok := strings.CutPrefix(field, "sha256:"); ok {
+			wantHash = hash
+		}
+	}
+	return wantHash, hunkText, nil
+}
+
+// splitLines splits data into lines, each retaining its trailing "\n" (the
+// final line does not have one if data doesn't end in "\n").
+func splitLines(data []byte) [][]byte {
+	var lines [][]byte
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			lines = append(lines, data)
+			break
+		}
+		lines = append(lines, data[:i+1])
+		data = data[i+1:]
+	}
+	return lines
+}
+
+// applyHunks applies hunks (in ascending order of OrigStartLine) to
+// original, returning the patched content and the total number of "+"
+// and "-" lines applied (a changed line counts twice: once removed, once
+// added).
+func applyHunks(original []byte, hunks []*diff.Hunk) ([]byte, int, error) {
+	origLines := splitLines(original)
+	var out bytes.Buffer
+	origIdx := 0
+	changed := 0
+
+	for _, h := range hunks {
+		start := int(h.OrigStartLine) - 1
+		if start < 0 {
+			start = 0
+		}
+		if start > len(origLines) || start < origIdx {
+			return nil, 0, fmt.Errorf("hunk starting at line %d is out of range or out of order", h.OrigStartLine)
+		}
+		for ; origIdx < start; origIdx++ {
+			out.Write(origLines[origIdx])
+		}
+
+		for _, line := range splitLines(h.Body) {
+			text := bytes.TrimSuffix(line, []byte("\n"))
+			if len(text) == 0 {
+				continue
+			}
+			switch text[0] {
+			case ' ':
+				if origIdx >= len(origLines) {
+					return nil, 0, fmt.Errorf("context line beyond end of file at line %d", origIdx+1)
+				}
+				out.Write(origLines[origIdx])
+				origIdx++
+			case '-':
+				if origIdx >= len(origLines) {
+					return nil, 0, fmt.Errorf("removal line beyond end of file at line %d", origIdx+1)
+				}
+				origIdx++
+				changed++
+			case '+':
+				out.Write(text[1:])
+				out.WriteByte('\n')
+				changed++
+			default:
+				return nil, 0, fmt.Errorf("unrecognized hunk line prefix %q", text[0])
+			}
+		}
+	}
+	for ; origIdx < len(origLines); origIdx++ {
+		out.Write(origLines[origIdx])
+	}
+	return out.Bytes(), changed, nil
+}
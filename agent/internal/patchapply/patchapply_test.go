@@ -0,0 +1,77 @@
+package patchapply
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/filesystem"
+)
+
+const original = "line1\nline2\nline3\n"
+
+func writeOriginal(t *testing.T) (*filesystem.Root, string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte(original), 0o644); err != nil {
+		t.Fatalf("write original: %v", err)
+	}
+	root, err := filesystem.NewRoot(dir)
+	if err != nil {
+		t.Fatalf("NewRoot: %v", err)
+	}
+	return root, dir
+}
+
+func sourceHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func diffWithHash(hash string) string {
+	return fmt.Sprintf(
+		"--- file.txt\tsha256:%s\n+++ file.txt\n@@ -1,3 +1,3 @@\n line1\n-line2\n+CHANGED\n line3\n",
+		hash,
+	)
+}
+
+func TestApplyPatchesFileMatchingSourceHash(t *testing.T) {
+	root, dir := writeOriginal(t)
+
+	changed, err := Apply(root, "file.txt", diffWithHash(sourceHash(original)))
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if changed != 2 {
+		t.Errorf("linesChanged = %d, want 2", changed)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+	if err != nil {
+		t.Fatalf("read patched file: %v", err)
+	}
+	if want := "line1\nCHANGED\nline3\n"; string(got) != want {
+		t.Errorf("patched content = %q, want %q", got, want)
+	}
+}
+
+func TestApplyRejectsWrongSourceHashWithoutModifyingFile(t *testing.T) {
+	root, dir := writeOriginal(t)
+
+	_, err := Apply(root, "file.txt", diffWithHash(sourceHash("stale content\n")))
+	if !errors.Is(err, ErrSourceHashMismatch) {
+		t.Fatalf("Apply with wrong hash = %v, want ErrSourceHashMismatch", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if string(got) != original {
+		t.Errorf("file was modified despite hash mismatch: got %q, want %q", got, original)
+	}
+}
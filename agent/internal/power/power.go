@@ -0,0 +1,122 @@
+// Package power detects whether the host is running on battery or AC
+// power, so the agent can throttle its own metrics collection to save
+// power on laptops and other battery-backed devices.
+package power
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// pollInterval is how often Start's background goroutine re-checks power
+// state.
+const pollInterval = 30 * time.Second
+
+// BatteryIntervalMultiplier scales MetricsInterval while on battery power
+// (SINDRI_AGENT_DISABLE_METRICS_ON_BATTERY).
+const BatteryIntervalMultiplier = 5
+
+// sysClassPowerSupply is the default directory Monitor scans for AC/
+// battery status; overridden in tests via NewMonitorAt.
+const sysClassPowerSupply = "/sys/class/power_supply"
+
+// Monitor tracks whether the host is currently running on battery power.
+// The zero value reports on-battery as false until Refresh or Start is
+// called.
+type Monitor struct {
+	dir       string
+	onBattery atomic.Bool
+	done      chan struct{}
+}
+
+// NewMonitor returns a Monitor reading power supply state from the
+// standard /sys/class/power_supply location, with an initial Refresh
+// already applied.
+func NewMonitor() *Monitor {
+	return NewMonitorAt(sysClassPowerSupply)
+}
+
+// NewMonitorAt returns a Monitor reading power supply state from dir
+// (structured like /sys/class/power_supply), letting tests point it at a
+// fake directory tree. An initial Refresh is applied before returning.
+func NewMonitorAt(dir string) *Monitor {
+	m := &Monitor{dir: dir}
+	m.Refresh()
+	return m
+}
+
+// OnBattery reports whether the host was on battery power as of the most
+// recent Refresh.
+func (m *Monitor) OnBattery() bool {
+	return m.onBattery.Load()
+}
+
+// AdjustInterval returns base scaled by BatteryIntervalMultiplier while on
+// battery power, or base unchanged while on AC.
+func (m *Monitor) AdjustInterval(base time.Duration) time.Duration {
+	if m.OnBattery() {
+		return base * BatteryIntervalMultiplier
+	}
+	return base
+}
+
+// Refresh re-reads the power supply directory and updates OnBattery.
+func (m *Monitor) Refresh() {
+	m.onBattery.Store(detectOnBattery(m.dir))
+}
+
+// Start begins calling Refresh every pollInterval in a background
+// goroutine, until Stop is called. Calling Start more than once without an
+// intervening Stop leaks the earlier goroutine.
+func (m *Monitor) Start() {
+	m.done = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.Refresh()
+			case <-m.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the background polling goroutine started by Start.
+func (m *Monitor) Stop() {
+	if m.done != nil {
+		close(m.done)
+	}
+}
+
+// detectOnBattery scans dir (typically /sys/class/power_supply) for a
+// power supply of type "Mains" reporting online. It returns true only if
+// at least one Mains supply was found and none report online — a host
+// with no power supplies at all (most servers) is treated as running on
+// AC, since there is no battery to drain.
+func detectOnBattery(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+
+	sawMains := false
+	for _, entry := range entries {
+		typ, err := os.ReadFile(filepath.Join(dir, entry.Name(), "type"))
+		if err != nil || strings.TrimSpace(string(typ)) != "Mains" {
+			continue
+		}
+		sawMains = true
+
+		online, err := os.ReadFile(filepath.Join(dir, entry.Name(), "online"))
+		if err == nil && strings.TrimSpace(string(online)) == "1" {
+			return false
+		}
+	}
+	return sawMains
+}
@@ -0,0 +1,93 @@
+package power
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeSupply(t *testing.T, dir, name, typ, online string) {
+	t.Helper()
+	supplyDir := filepath.Join(dir, name)
+	if err := os.MkdirAll(supplyDir, 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(supplyDir, "type"), []byte(typ+"\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile type: %v", err)
+	}
+	if online != "" {
+		if err := os.WriteFile(filepath.Join(supplyDir, "online"), []byte(online+"\n"), 0o644); err != nil {
+			t.Fatalf("WriteFile online: %v", err)
+		}
+	}
+}
+
+func TestOnBatteryFalseWhenACOnline(t *testing.T) {
+	dir := t.TempDir()
+	writeSupply(t, dir, "AC", "Mains", "1")
+	writeSupply(t, dir, "BAT0", "Battery", "")
+
+	m := NewMonitorAt(dir)
+	if m.OnBattery() {
+		t.Error("expected OnBattery() = false with AC online")
+	}
+}
+
+func TestOnBatteryTrueWhenACOffline(t *testing.T) {
+	dir := t.TempDir()
+	writeSupply(t, dir, "AC", "Mains", "0")
+	writeSupply(t, dir, "BAT0", "Battery", "")
+
+	m := NewMonitorAt(dir)
+	if !m.OnBattery() {
+		t.Error("expected OnBattery() = true with AC offline")
+	}
+}
+
+func TestOnBatteryFalseWithNoPowerSupplies(t *testing.T) {
+	dir := t.TempDir()
+
+	m := NewMonitorAt(dir)
+	if m.OnBattery() {
+		t.Error("expected OnBattery() = false on a host with no power supplies")
+	}
+}
+
+func TestAdjustIntervalScalesOnBattery(t *testing.T) {
+	dir := t.TempDir()
+	writeSupply(t, dir, "AC", "Mains", "0")
+
+	m := NewMonitorAt(dir)
+	base := 60 * time.Second
+	if got := m.AdjustInterval(base); got != base*BatteryIntervalMultiplier {
+		t.Errorf("AdjustInterval() = %v, want %v", got, base*BatteryIntervalMultiplier)
+	}
+}
+
+func TestAdjustIntervalUnchangedOnAC(t *testing.T) {
+	dir := t.TempDir()
+	writeSupply(t, dir, "AC", "Mains", "1")
+
+	m := NewMonitorAt(dir)
+	base := 60 * time.Second
+	if got := m.AdjustInterval(base); got != base {
+		t.Errorf("AdjustInterval() = %v, want %v", got, base)
+	}
+}
+
+func TestRefreshPicksUpChangedState(t *testing.T) {
+	dir := t.TempDir()
+	writeSupply(t, dir, "AC", "Mains", "1")
+
+	m := NewMonitorAt(dir)
+	if m.OnBattery() {
+		t.Fatal("expected OnBattery() = false initially")
+	}
+
+	writeSupply(t, dir, "AC", "Mains", "0")
+	m.Refresh()
+	if !m.OnBattery() {
+		t.Error("expected OnBattery() = true after Refresh observes AC offline")
+	}
+}
@@ -0,0 +1,109 @@
+// Package promexport exposes the agent's most recent MetricsPayload as a
+// Prometheus text-exposition endpoint, for ops teams running a Prometheus
+// scraper that would rather pull metrics directly than route through the
+// Console WebSocket.
+package promexport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/metrics"
+)
+
+// shutdownTimeout bounds how long Exporter.Start waits for in-flight
+// requests to finish once its context is canceled.
+const shutdownTimeout = 5 * time.Second
+
+// Exporter serves the most recently collected MetricsPayload at /metrics
+// in the Prometheus text exposition format. It holds no history — each
+// UpdateSnapshot call replaces the previous one — since Prometheus scrapes
+// on its own schedule and only ever wants the current value of each gauge.
+type Exporter struct {
+	addr string
+
+	mu       sync.RWMutex
+	snapshot *metrics.MetricsPayload
+
+	server *http.Server
+}
+
+// NewExporter returns an Exporter that will listen on addr (e.g. ":9090")
+// once Start is called.
+func NewExporter(addr string) *Exporter {
+	return &Exporter{addr: addr}
+}
+
+// UpdateSnapshot replaces the payload served at /metrics. Safe for
+// concurrent use with Start's request handling.
+func (e *Exporter) UpdateSnapshot(payload *metrics.MetricsPayload) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.snapshot = payload
+}
+
+// Start runs the HTTP server until ctx is canceled, then shuts it down
+// gracefully (waiting up to shutdownTimeout for in-flight requests). It
+// blocks until the server has fully stopped.
+func (e *Exporter) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", e.handleMetrics)
+	e.server = &http.Server{Addr: e.addr, Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := e.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- fmt.Errorf("promexport: listen and serve: %w", err)
+			return
+		}
+		errCh <- nil
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := e.server.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("promexport: shutdown: %w", err)
+		}
+		return <-errCh
+	}
+}
+
+// handleMetrics writes the current snapshot in the Prometheus text
+// exposition format. An empty response (no gauges) is valid Prometheus
+// output for a scraper that arrives before the first metrics tick.
+func (e *Exporter) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	snapshot := e.snapshot
+	e.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if snapshot == nil {
+		return
+	}
+	writeSnapshot(w, snapshot)
+}
+
+// writeSnapshot writes snapshot's fields as Prometheus gauges, each
+// preceded by the HELP and TYPE comment lines the exposition format
+// expects.
+func writeSnapshot(w io.Writer, snapshot *metrics.MetricsPayload) {
+	writeGauge(w, "cpu_usage_percent", "Percentage of CPU time in use.", snapshot.CPUPercent)
+	writeGauge(w, "memory_used_bytes", "Bytes of physical memory in use.", float64(snapshot.MemoryUsedBytes))
+	writeGauge(w, "memory_total_bytes", "Total bytes of physical memory.", float64(snapshot.MemoryTotalBytes))
+	writeGauge(w, "disk_used_bytes", "Bytes of disk space in use.", float64(snapshot.DiskUsedBytes))
+	writeGauge(w, "disk_total_bytes", "Total bytes of disk space.", float64(snapshot.DiskTotalBytes))
+}
+
+func writeGauge(w io.Writer, name, help string, value float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(w, "%s %v\n", name, value)
+}
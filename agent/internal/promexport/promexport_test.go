@@ -0,0 +1,65 @@
+package promexport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/metrics"
+)
+
+func TestGetMetricsOverRealHTTPServerIncludesCPUUsagePercent(t *testing.T) {
+	e := NewExporter(":0")
+	e.UpdateSnapshot(&metrics.MetricsPayload{CPUPercent: 17.25})
+
+	server := httptest.NewServer(http.HandlerFunc(e.handleMetrics))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	if !strings.Contains(string(body), "cpu_usage_percent 17.25") {
+		t.Errorf("expected cpu_usage_percent line in body, got:\n%s", body)
+	}
+}
+
+func TestHandleMetricsIncludesCPUUsagePercent(t *testing.T) {
+	e := NewExporter(":0")
+	e.UpdateSnapshot(&metrics.MetricsPayload{
+		CPUPercent:      42.5,
+		MemoryUsedBytes: 1024,
+	})
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	e.handleMetrics(rec, req)
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "cpu_usage_percent 42.5") {
+		t.Errorf("expected cpu_usage_percent line in body, got:\n%s", body)
+	}
+	if !strings.Contains(body, "memory_used_bytes 1024") {
+		t.Errorf("expected memory_used_bytes line in body, got:\n%s", body)
+	}
+}
+
+func TestHandleMetricsReturnsEmptyBodyBeforeFirstSnapshot(t *testing.T) {
+	e := NewExporter(":0")
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	e.handleMetrics(rec, req)
+
+	if rec.Body.Len() != 0 {
+		t.Errorf("expected an empty body before the first UpdateSnapshot, got %q", rec.Body.String())
+	}
+}
@@ -0,0 +1,110 @@
+// Package protocol defines the message envelope and payload types exchanged
+// between the agent and the Console over the WebSocket transport.
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+)
+
+// MaxEnvelopeSize bounds the marshaled size of a single Envelope, guarding
+// against a malformed or malicious payload exhausting memory or blowing
+// past the WebSocket frame size limit.
+const MaxEnvelopeSize = 16 * 1024 * 1024
+
+// MessageType identifies the kind of payload carried by an Envelope.
+type MessageType string
+
+// Envelope wraps a typed payload for transport. Payload is decoded based on
+// Type by the caller.
+type Envelope struct {
+	Type    MessageType     `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+
+	// MessageID uniquely identifies this envelope, letting the receiver
+	// deduplicate a message that was retransmitted after a missing ACK.
+	// Empty for envelopes that don't need delivery deduplication.
+	MessageID string `json:"message_id,omitempty"`
+
+	// Signature is the hex-encoded HMAC-SHA256 of the envelope (with this
+	// field cleared), set by Sign and checked by Verify. Empty when
+	// SINDRI_AGENT_MESSAGE_SECRET is not configured.
+	Signature string `json:"signature,omitempty"`
+
+	// ChannelID identifies which mux.Handler an envelope belongs to when
+	// multiple logical Console sessions share one underlying
+	// websocket.Client (see internal/mux). Empty for envelopes sent
+	// outside a mux.Client.
+	ChannelID string `json:"channel_id,omitempty"`
+
+	// FragmentID, FragmentIndex, and FragmentTotal are set by
+	// websocket.Client's AutoFragment when a payload too large for a
+	// single WebSocket frame is split across multiple envelopes.
+	// FragmentTotal is zero for an envelope that was not fragmented.
+	FragmentID    string `json:"fragment_id,omitempty"`
+	FragmentIndex int    `json:"fragment_index,omitempty"`
+	FragmentTotal int    `json:"fragment_total,omitempty"`
+
+	// FragmentChunk carries this fragment's slice of the original
+	// Payload bytes when FragmentTotal is non-zero; Payload itself is
+	// left empty on a fragment envelope. The receiver concatenates
+	// FragmentChunk across all fragments sharing FragmentID, in
+	// FragmentIndex order, to reconstruct the original Payload.
+	FragmentChunk []byte `json:"fragment_chunk,omitempty"`
+
+	// SeqNum orders envelopes within whatever stream assigned it (see
+	// NewEnvelope and websocket.Client.SendWithRetry, which each
+	// maintain their own monotonic counter), letting the receiver detect
+	// a dropped or reordered message by spotting a gap.
+	SeqNum uint64 `json:"seq_num"`
+}
+
+// nextEnvelopeSeq backs NewEnvelope's SeqNum assignment. It is process-wide
+// rather than per-Envelope-construction-site, since NewEnvelope has no
+// notion of which connection (if any) an envelope will eventually be sent
+// on; websocket.Client.SendWithRetry assigns its own per-connection
+// sequence on top of whatever NewEnvelope set.
+var nextEnvelopeSeq atomic.Uint64
+
+// NewEnvelope marshals payload and wraps it in an Envelope of the given
+// type. It returns an error if the marshaled payload exceeds
+// MaxEnvelopeSize, so oversized messages are caught at construction time
+// rather than surfacing as an opaque write failure later.
+func NewEnvelope(t MessageType, payload interface{}) (*Envelope, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxEnvelopeSize {
+		return nil, fmt.Errorf("protocol: envelope payload of %d bytes exceeds max size %d", len(data), MaxEnvelopeSize)
+	}
+	return &Envelope{Type: t, Payload: data, SeqNum: nextEnvelopeSeq.Add(1)}, nil
+}
+
+// ValidateSize returns an error if e's marshaled size exceeds
+// MaxEnvelopeSize. Useful for envelopes decoded off the wire (e.g. via
+// Client.Receive) rather than constructed via NewEnvelope.
+func (e *Envelope) ValidateSize() error {
+	size, err := e.size()
+	if err != nil {
+		return fmt.Errorf("protocol: measure envelope size: %w", err)
+	}
+	if size > MaxEnvelopeSize {
+		return fmt.Errorf("protocol: envelope of %d bytes exceeds max size %d", size, MaxEnvelopeSize)
+	}
+	return nil
+}
+
+func (e *Envelope) size() (int, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// Decode unmarshals the Envelope's payload into out.
+func (e *Envelope) Decode(out interface{}) error {
+	return json.Unmarshal(e.Payload, out)
+}
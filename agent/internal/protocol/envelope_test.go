@@ -0,0 +1,38 @@
+package protocol
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewEnvelopeRejectsOversizedPayload(t *testing.T) {
+	huge := strings.Repeat("x", MaxEnvelopeSize+1)
+	_, err := NewEnvelope(MsgTypeTerminalOutput, MsgTerminalOutput{Data: []byte(huge)})
+	if err == nil {
+		t.Fatal("expected an oversized envelope payload to be rejected")
+	}
+}
+
+func TestNewEnvelopeAcceptsSmallPayload(t *testing.T) {
+	env, err := NewEnvelope(MsgTypeTerminalBell, MsgTerminalBell{SessionID: "sess-1"})
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+	if err := env.ValidateSize(); err != nil {
+		t.Fatalf("ValidateSize: %v", err)
+	}
+}
+
+func TestNewEnvelopeAssignsMonotonicallyIncreasingSeqNum(t *testing.T) {
+	first, err := NewEnvelope(MsgTypeTerminalBell, MsgTerminalBell{SessionID: "sess-1"})
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+	second, err := NewEnvelope(MsgTypeTerminalBell, MsgTerminalBell{SessionID: "sess-1"})
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+	if second.SeqNum <= first.SeqNum {
+		t.Errorf("second.SeqNum = %d, want greater than first.SeqNum = %d", second.SeqNum, first.SeqNum)
+	}
+}
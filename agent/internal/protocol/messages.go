@@ -0,0 +1,34 @@
+package protocol
+
+// Message type identifiers. New message types are appended here as the
+// protocol grows; existing values must never change once released.
+const (
+	MsgTypeAgentVersionInfo MessageType = "agent_version_info"
+	MsgTypeFileReadRequest  MessageType = "file_read_request"
+	MsgTypeFileReadResponse MessageType = "file_read_response"
+)
+
+// MsgAgentVersionInfo is pushed by the Console when a newer agent build is
+// available for download.
+type MsgAgentVersionInfo struct {
+	CurrentVersion   string `json:"current_version"`
+	AvailableVersion string `json:"available_version"`
+	DownloadURL      string `json:"download_url"`
+	Checksum         string `json:"checksum"`
+}
+
+// MsgFileReadRequest asks the agent to read a byte range from a remote
+// file. A Length of 0 means "read to end of file".
+type MsgFileReadRequest struct {
+	RequestID string `json:"request_id"`
+	Path      string `json:"path"`
+	Offset    int64  `json:"offset"`
+	Length    int64  `json:"length"`
+}
+
+// MsgFileReadResponse carries the bytes read for a MsgFileReadRequest.
+type MsgFileReadResponse struct {
+	RequestID string `json:"request_id"`
+	Data      []byte `json:"data"`
+	EOF       bool   `json:"eof"`
+}
@@ -0,0 +1,19 @@
+package protocol
+
+// Message types for generic delivery acknowledgment, distinct from
+// MsgHeartbeatACK (which additionally carries clock-skew data specific
+// to heartbeats).
+const (
+	MsgTypeAck  MessageType = "ack"
+	MsgTypeNack MessageType = "nack"
+)
+
+// AckPayload acknowledges (or, as a NACK, rejects) delivery of the
+// envelope identified by SeqNum and OrigType, letting a sender that cares
+// about delivery of a critical message (see heartbeat.Manager.WaitForACK)
+// confirm the Console actually received it instead of firing and
+// forgetting.
+type AckPayload struct {
+	SeqNum   uint64      `json:"seq_num"`
+	OrigType MessageType `json:"orig_type"`
+}
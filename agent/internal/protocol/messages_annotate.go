@@ -0,0 +1,13 @@
+package protocol
+
+// MsgTypeAnnotateInstance requests that temporary, unpersisted labels be
+// applied to (or removed from) this instance.
+const MsgTypeAnnotateInstance MessageType = "annotate_instance"
+
+// MsgAnnotateInstance carries Console-driven instance annotations, applied
+// via annotations.Store.Apply. Unlike MsgTagUpdate, annotations are never
+// persisted to the registration cache and expire automatically.
+type MsgAnnotateInstance struct {
+	Annotations map[string]string `json:"annotations"`
+	Remove      []string          `json:"remove,omitempty"`
+}
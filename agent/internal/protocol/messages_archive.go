@@ -0,0 +1,35 @@
+package protocol
+
+// Message types for on-demand remote tarball/zip creation.
+const (
+	MsgTypeArchiveCreateRequest MessageType = "archive_create_request"
+	MsgTypeArchiveProgress      MessageType = "archive_progress"
+	MsgTypeArchiveComplete      MessageType = "archive_complete"
+)
+
+// MsgArchiveCreateRequest asks the agent to archive Paths (each resolved
+// against FS_ROOT) into a single file written to Destination. Format is
+// "tar.gz" or "zip".
+type MsgArchiveCreateRequest struct {
+	RequestID   string   `json:"request_id"`
+	Paths       []string `json:"paths"`
+	Format      string   `json:"format"`
+	Destination string   `json:"destination"`
+}
+
+// MsgArchiveProgress reports incremental progress of an in-flight archive
+// creation.
+type MsgArchiveProgress struct {
+	RequestID      string `json:"request_id"`
+	FilesProcessed int    `json:"files_processed"`
+	FilesTotal     int    `json:"files_total"`
+	BytesProcessed int64  `json:"bytes_processed"`
+}
+
+// MsgArchiveComplete reports the result of a finished archive creation.
+type MsgArchiveComplete struct {
+	RequestID   string `json:"request_id"`
+	Destination string `json:"destination"`
+	SHA256      string `json:"sha256"`
+	SizeBytes   int64  `json:"size_bytes"`
+}
@@ -0,0 +1,22 @@
+package protocol
+
+// Message types for remote shell tab-completion.
+const (
+	MsgTypeCompletionsRequest  MessageType = "completions_request"
+	MsgTypeCompletionsResponse MessageType = "completions_response"
+)
+
+// MsgCompletionsRequest asks the agent for tab-completion suggestions for a
+// partially-typed command in a session.
+type MsgCompletionsRequest struct {
+	RequestID string `json:"request_id"`
+	SessionID string `json:"session_id"`
+	Partial   string `json:"partial"`
+}
+
+// MsgCompletionsResponse returns the completion suggestions for a prior
+// MsgCompletionsRequest.
+type MsgCompletionsResponse struct {
+	RequestID   string   `json:"request_id"`
+	Completions []string `json:"completions"`
+}
@@ -0,0 +1,13 @@
+package protocol
+
+// Message type for pushing runtime configuration overrides to the agent.
+const MsgTypeConfigPush MessageType = "config_push"
+
+// MsgConfigPush applies a flat set of runtime configuration overrides, sent
+// by the Console either directly or as part of a registration response
+// (see registration.RegistrationResponse.ConfigOverrides), without
+// requiring the agent to restart. Unknown keys are ignored so older agents
+// can safely receive overrides introduced for newer ones.
+type MsgConfigPush struct {
+	Overrides map[string]string `json:"overrides,omitempty"`
+}
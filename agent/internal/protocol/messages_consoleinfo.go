@@ -0,0 +1,24 @@
+package protocol
+
+import "time"
+
+// MsgTypeConsoleInfo is sent Console -> Agent, typically right after the
+// agent's MsgStateSync, so the agent learns what the Console it's talking
+// to supports before relying on any optional feature.
+const MsgTypeConsoleInfo MessageType = "console_info"
+
+// MsgConsoleInfo reports the Console's version and the optional feature
+// set it supports, so the agent can gate features the Console it's
+// connected to doesn't understand yet (see consoleinfo.Store).
+type MsgConsoleInfo struct {
+	ConsoleVersion      string    `json:"console_version"`
+	SupportedFeatures   []string  `json:"supported_features,omitempty"`
+	MaxTerminalSessions int       `json:"max_terminal_sessions,omitempty"`
+	ServerTime          time.Time `json:"server_time"`
+
+	// WatchdogPingIntervalMS is how often the Console sends
+	// MsgWatchdogPing, in milliseconds. The agent treats the Console as
+	// unresponsive if it goes 2x this long without one (see
+	// watchdog.Monitor). Zero disables the watchdog.
+	WatchdogPingIntervalMS int64 `json:"watchdog_ping_interval_ms,omitempty"`
+}
@@ -0,0 +1,25 @@
+package protocol
+
+// Message types for on-demand Go pprof profile collection, guarded by the
+// agent's SINDRI_AGENT_DEBUG_PROFILE_ENABLED setting.
+const (
+	MsgTypeDebugProfileRequest  MessageType = "debug_profile_request"
+	MsgTypeDebugProfileResponse MessageType = "debug_profile_response"
+)
+
+// MsgDebugProfileRequest asks the agent to collect a Go pprof profile.
+// Profile is one of "heap", "goroutine", or "cpu". DurationMs is only used
+// for "cpu" profiles, which sample for that long before returning.
+type MsgDebugProfileRequest struct {
+	RequestID  string `json:"request_id"`
+	Profile    string `json:"profile"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+}
+
+// MsgDebugProfileResponse carries the collected profile back to the
+// Console. Data is the raw pprof binary output, base64-encoded, and is
+// directly usable with `go tool pprof` once decoded.
+type MsgDebugProfileResponse struct {
+	RequestID string `json:"request_id"`
+	Data      string `json:"data"`
+}
@@ -0,0 +1,43 @@
+package protocol
+
+import "runtime"
+
+// MsgTypeDiagnostics identifies a MsgDiagnostics envelope.
+const MsgTypeDiagnostics MessageType = "diagnostics"
+
+// MsgDiagnostics reports the agent's current Go runtime heap stats
+// (runtime.MemStats), letting the Console spot heap growth or
+// fragmentation on a long-running instance without SSH access.
+type MsgDiagnostics struct {
+	// HeapAllocBytes is currently allocated and still in use
+	// (runtime.MemStats.HeapAlloc).
+	HeapAllocBytes uint64 `json:"heap_alloc_bytes"`
+
+	// HeapSysBytes is obtained from the OS for the heap
+	// (runtime.MemStats.HeapSys).
+	HeapSysBytes uint64 `json:"heap_sys_bytes"`
+
+	// HeapIdleBytes is unused and available for reuse or return to the OS
+	// (runtime.MemStats.HeapIdle).
+	HeapIdleBytes uint64 `json:"heap_idle_bytes"`
+
+	// NumGC is the number of completed GC cycles (runtime.MemStats.NumGC).
+	NumGC uint32 `json:"num_gc"`
+
+	// NumGoroutine is the current goroutine count (runtime.NumGoroutine).
+	NumGoroutine int `json:"num_goroutine"`
+}
+
+// NewDiagnostics builds a MsgDiagnostics from the current process's
+// runtime.MemStats.
+func NewDiagnostics() MsgDiagnostics {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return MsgDiagnostics{
+		HeapAllocBytes: stats.HeapAlloc,
+		HeapSysBytes:   stats.HeapSys,
+		HeapIdleBytes:  stats.HeapIdle,
+		NumGC:          stats.NumGC,
+		NumGoroutine:   runtime.NumGoroutine(),
+	}
+}
@@ -0,0 +1,41 @@
+package protocol
+
+// Message types for syncing a local directory tree to the agent.
+const (
+	MsgTypeDirSyncManifest MessageType = "dir_sync_manifest"
+	MsgTypeDirSyncRequest  MessageType = "dir_sync_request"
+	MsgTypeDirSyncComplete MessageType = "dir_sync_complete"
+)
+
+// FileSyncEntry describes one file in a directory sync manifest.
+type FileSyncEntry struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+	Mode   uint32 `json:"mode"`
+}
+
+// MsgDirSyncManifest asks the agent to sync a local directory tree to
+// DestPath. The agent computes its own manifest for DestPath, diffs it
+// against Files, and responds with MsgDirSyncRequest naming the files it
+// still needs.
+type MsgDirSyncManifest struct {
+	SyncID   string          `json:"sync_id"`
+	DestPath string          `json:"dest_path"`
+	Files    []FileSyncEntry `json:"files"`
+}
+
+// MsgDirSyncRequest lists the files, by path relative to DestPath, that
+// are missing or out of date on the agent and must be sent via
+// MsgFileWriteRequest (with SyncID set) before the sync can complete.
+type MsgDirSyncRequest struct {
+	SyncID    string   `json:"sync_id"`
+	NeedFiles []string `json:"need_files"`
+}
+
+// MsgDirSyncComplete acknowledges that every file requested by a
+// MsgDirSyncRequest for SyncID has been written.
+type MsgDirSyncComplete struct {
+	SyncID       string `json:"sync_id"`
+	FilesUpdated int    `json:"files_updated"`
+}
@@ -0,0 +1,26 @@
+package protocol
+
+// Message types for on-demand DNS resolution from the agent's perspective,
+// which can differ from the developer's machine due to split-horizon DNS
+// or a fleet-specific resolver.
+const (
+	MsgTypeDNSLookupRequest  MessageType = "dns_lookup_request"
+	MsgTypeDNSLookupResponse MessageType = "dns_lookup_response"
+)
+
+// MsgDNSLookupRequest asks the agent to resolve Hostname as RecordType (one
+// of A, AAAA, MX, TXT, CNAME, NS).
+type MsgDNSLookupRequest struct {
+	RequestID  string `json:"request_id"`
+	Hostname   string `json:"hostname"`
+	RecordType string `json:"record_type"`
+}
+
+// MsgDNSLookupResponse carries the resolved records for a
+// MsgDNSLookupRequest.
+type MsgDNSLookupResponse struct {
+	RequestID  string   `json:"request_id"`
+	Records    []string `json:"records"`
+	ResolvedBy string   `json:"resolved_by"`
+	DurationMs int64    `json:"duration_ms"`
+}
@@ -0,0 +1,42 @@
+package protocol
+
+// Message types for remote environment variable access.
+const (
+	MsgTypeEnvGetRequest  MessageType = "env_get_request"
+	MsgTypeEnvGetResponse MessageType = "env_get_response"
+	MsgTypeEnvSetRequest  MessageType = "env_set_request"
+	MsgTypeEnvSetResponse MessageType = "env_set_response"
+)
+
+// MsgEnvGetRequest asks the agent for the value of an environment variable
+// in its own process environment.
+type MsgEnvGetRequest struct {
+	RequestID string `json:"request_id"`
+	Name      string `json:"name"`
+}
+
+// MsgEnvGetResponse carries the result of a MsgEnvGetRequest. Found is
+// false when the variable is unset, distinguishing that from an empty
+// value.
+type MsgEnvGetResponse struct {
+	RequestID string `json:"request_id"`
+	Value     string `json:"value"`
+	Found     bool   `json:"found"`
+}
+
+// MsgEnvSetRequest asks the agent to set (or, if Unset is true, remove) an
+// environment variable in its own process environment. This affects only
+// the agent process and any children it spawns afterward — it does not
+// mutate the environment of already-running sessions.
+type MsgEnvSetRequest struct {
+	RequestID string `json:"request_id"`
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Unset     bool   `json:"unset"`
+}
+
+// MsgEnvSetResponse acknowledges a MsgEnvSetRequest.
+type MsgEnvSetResponse struct {
+	RequestID string `json:"request_id"`
+	Error     string `json:"error,omitempty"`
+}
@@ -0,0 +1,23 @@
+package protocol
+
+// Message type for structured agent lifecycle events.
+const MsgTypeEvent MessageType = "event"
+
+// ReconnectReason classifies why the agent's connection to the Console was
+// (re)established, for observability dashboards on the Console side.
+type ReconnectReason string
+
+const (
+	ReconnectReasonInitial       ReconnectReason = "initial"
+	ReconnectReasonNetworkError  ReconnectReason = "network_error"
+	ReconnectReasonServerClosed  ReconnectReason = "server_closed"
+	ReconnectReasonPingTimeout   ReconnectReason = "ping_timeout"
+	ReconnectReasonManualRestart ReconnectReason = "manual_restart"
+)
+
+// MsgEvent reports a structured agent lifecycle event to the Console.
+type MsgEvent struct {
+	Kind   string          `json:"kind"`
+	Reason ReconnectReason `json:"reason,omitempty"`
+	Detail string          `json:"detail,omitempty"`
+}
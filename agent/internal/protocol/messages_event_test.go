@@ -0,0 +1,21 @@
+package protocol
+
+import "testing"
+
+func TestNewEnvelopeEncodesReconnectEvent(t *testing.T) {
+	env, err := NewEnvelope(MsgTypeEvent, MsgEvent{
+		Kind:   "reconnect",
+		Reason: ReconnectReasonPingTimeout,
+	})
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+
+	var decoded MsgEvent
+	if err := env.Decode(&decoded); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if decoded.Reason != ReconnectReasonPingTimeout {
+		t.Errorf("Reason = %q, want %q", decoded.Reason, ReconnectReasonPingTimeout)
+	}
+}
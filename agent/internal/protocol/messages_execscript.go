@@ -0,0 +1,28 @@
+package protocol
+
+// MsgTypeExecScriptRequest and MsgTypeCommandOutput are the message types
+// exchanged during multi-line script execution.
+const (
+	MsgTypeExecScriptRequest MessageType = "exec_script_request"
+	MsgTypeCommandOutput     MessageType = "command_output"
+)
+
+// MsgExecScript asks the agent to run a multi-line script through the
+// named interpreter (one of execscript's allowlisted interpreters), rather
+// than a single command line.
+type MsgExecScript struct {
+	ScriptID    string   `json:"script_id"`
+	Script      string   `json:"script"`
+	Interpreter string   `json:"interpreter"`
+	Env         []string `json:"env,omitempty"`
+	WorkDir     string   `json:"work_dir,omitempty"`
+	TimeoutMs   int64    `json:"timeout_ms"`
+}
+
+// MsgCommandOutput streams a chunk of a running command's output to the
+// Console. Stream is either "stdout" or "stderr".
+type MsgCommandOutput struct {
+	ScriptID string `json:"script_id"`
+	Stream   string `json:"stream"`
+	Data     []byte `json:"data"`
+}
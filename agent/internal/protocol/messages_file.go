@@ -0,0 +1,28 @@
+package protocol
+
+// Message types for remote file access.
+const (
+	MsgTypeFileWriteRequest  MessageType = "file_write_request"
+	MsgTypeFileWriteResponse MessageType = "file_write_response"
+)
+
+// MsgFileWriteRequest asks the agent to (over)write a remote file's full
+// contents.
+type MsgFileWriteRequest struct {
+	RequestID string `json:"request_id"`
+	Path      string `json:"path"`
+	Data      []byte `json:"data"`
+	Mode      uint32 `json:"mode"`
+
+	// SyncID, if set, identifies the MsgDirSyncManifest this write
+	// belongs to, so the agent can mark the file off against the
+	// directory sync tracked under that ID (see DirSyncTracker).
+	SyncID string `json:"sync_id,omitempty"`
+}
+
+// MsgFileWriteResponse acknowledges a MsgFileWriteRequest.
+type MsgFileWriteResponse struct {
+	RequestID    string `json:"request_id"`
+	BytesWritten int64  `json:"bytes_written"`
+	Error        string `json:"error,omitempty"`
+}
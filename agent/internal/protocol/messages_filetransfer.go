@@ -0,0 +1,57 @@
+package protocol
+
+// Message types for streaming file transfer between agent and Console, in
+// bounded chunks — unlike MsgFileReadRequest/MsgFileWriteRequest (see
+// messages.go, messages_file.go), which carry a whole file in a single
+// message and are unsuited to files too large to buffer in memory.
+const (
+	MsgTypeFileUploadRequest   MessageType = "file_upload_request"
+	MsgTypeFileUploadChunk     MessageType = "file_upload_chunk"
+	MsgTypeFileUploadComplete  MessageType = "file_upload_complete"
+	MsgTypeFileDownloadRequest MessageType = "file_download_request"
+	MsgTypeFileDownloadChunk   MessageType = "file_download_chunk"
+)
+
+// MsgFileUploadRequest announces an incoming upload of SizeBytes to Path,
+// letting the agent reject it up front (e.g. it exceeds
+// config.Config.MaxTransferBytes) before any MsgFileUploadChunk arrives.
+type MsgFileUploadRequest struct {
+	RequestID string `json:"request_id"`
+	Path      string `json:"path"`
+	SizeBytes int64  `json:"size_bytes"`
+	Mode      uint32 `json:"mode"`
+}
+
+// MsgFileUploadChunk carries one chunk of an in-progress upload. Offset is
+// the byte offset within the destination file that Data begins at.
+type MsgFileUploadChunk struct {
+	RequestID string `json:"request_id"`
+	Offset    int64  `json:"offset"`
+	Data      []byte `json:"data"`
+}
+
+// MsgFileUploadComplete reports the result of a finished upload.
+type MsgFileUploadComplete struct {
+	RequestID    string `json:"request_id"`
+	BytesWritten int64  `json:"bytes_written"`
+	Error        string `json:"error,omitempty"`
+}
+
+// MsgFileDownloadRequest asks the agent to stream Path back as a series of
+// MsgFileDownloadChunk messages, ChunkSize bytes at a time (0 uses
+// filetransfer's default).
+type MsgFileDownloadRequest struct {
+	RequestID string `json:"request_id"`
+	Path      string `json:"path"`
+	ChunkSize int    `json:"chunk_size,omitempty"`
+}
+
+// MsgFileDownloadChunk carries one chunk of a requested download. EOF is
+// true on the final chunk, which may also carry the last of the file's
+// data.
+type MsgFileDownloadChunk struct {
+	RequestID string `json:"request_id"`
+	Offset    int64  `json:"offset"`
+	Data      []byte `json:"data"`
+	EOF       bool   `json:"eof"`
+}
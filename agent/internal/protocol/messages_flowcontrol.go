@@ -0,0 +1,24 @@
+package protocol
+
+// Message types for per-session output flow control, letting the Console
+// bound how much unread terminal output the agent may have in flight.
+const (
+	MsgTypeFlowControl    MessageType = "flow_control"
+	MsgTypeFlowControlACK MessageType = "flow_control_ack"
+)
+
+// MsgFlowControl grants the named session additional send credit
+// (Console -> Agent). Credit is additive: repeated messages accumulate
+// rather than replace the agent's remaining window.
+type MsgFlowControl struct {
+	SessionID  string `json:"session_id"`
+	WindowSize int    `json:"window_size"`
+}
+
+// MsgFlowControlACK reports how many bytes of a session's granted credit
+// the agent has actually sent (Agent -> Console), so the Console can track
+// its own buffer occupancy.
+type MsgFlowControlACK struct {
+	SessionID string `json:"session_id"`
+	Consumed  int    `json:"consumed"`
+}
@@ -0,0 +1,17 @@
+package protocol
+
+import "time"
+
+// MsgTypeHeartbeatACK is the Console's per-heartbeat acknowledgment,
+// carrying its own clock so the agent can estimate clock skew.
+const MsgTypeHeartbeatACK MessageType = "heartbeat_ack"
+
+// MsgHeartbeatACK is sent by the Console in response to each heartbeat.
+// ClientTimestamp echoes the timestamp the agent sent (HeartbeatPayload.
+// Timestamp); ServerTimestamp is the Console's clock at the moment it
+// processed the heartbeat.
+type MsgHeartbeatACK struct {
+	InstanceID      string    `json:"instance_id"`
+	ClientTimestamp time.Time `json:"client_timestamp"`
+	ServerTimestamp time.Time `json:"server_timestamp"`
+}
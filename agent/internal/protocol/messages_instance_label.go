@@ -0,0 +1,13 @@
+package protocol
+
+// MsgTypeInstanceLabel requests that runtime instance labels be set or
+// removed, independent of the agent's own configuration.
+const MsgTypeInstanceLabel MessageType = "instance_label"
+
+// MsgInstanceLabel carries Console-driven instance label changes, applied
+// via labels.Store.Apply. Unlike MsgTagUpdate, labels are never persisted
+// to the registration cache and are lost across an agent restart.
+type MsgInstanceLabel struct {
+	Set    map[string]string `json:"set,omitempty"`
+	Delete []string          `json:"delete,omitempty"`
+}
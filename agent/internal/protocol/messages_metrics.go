@@ -0,0 +1,16 @@
+package protocol
+
+import "github.com/pacphi/sindri/agent/internal/metrics"
+
+// MsgTypeMetricsBatch identifies a MsgMetricsBatch envelope, sent instead
+// of individual metrics.MetricsPayload messages when
+// SINDRI_AGENT_METRICS_PUSH_BATCH_SIZE is greater than 1.
+const MsgTypeMetricsBatch MessageType = "metrics_batch"
+
+// MsgMetricsBatch bundles several metrics.MetricsPayload samples,
+// accumulated across consecutive metrics ticks, into a single message —
+// trading off latency for fewer, larger messages on poor network
+// connections (see metrics.BatchTracker).
+type MsgMetricsBatch struct {
+	Payloads []*metrics.MetricsPayload `json:"payloads"`
+}
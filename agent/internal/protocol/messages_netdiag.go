@@ -0,0 +1,28 @@
+package protocol
+
+// Message types for on-demand network diagnostics.
+const (
+	MsgTypeNetworkDiagRequest  MessageType = "network_diag_request"
+	MsgTypeNetworkDiagResponse MessageType = "network_diag_response"
+)
+
+// MsgNetworkDiagRequest asks the agent to run a subset of netdiag's known
+// tests ("dns", "tcp_connect", "http_get", "route", "mtu").
+type MsgNetworkDiagRequest struct {
+	RequestID string   `json:"request_id"`
+	Tests     []string `json:"tests"`
+}
+
+// DiagResult is the outcome of a single named network diagnostic test.
+type DiagResult struct {
+	Success bool   `json:"success"`
+	Latency string `json:"latency,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// MsgNetworkDiagResponse carries one DiagResult per test name requested in
+// the corresponding MsgNetworkDiagRequest.
+type MsgNetworkDiagResponse struct {
+	RequestID string                `json:"request_id"`
+	Results   map[string]DiagResult `json:"results"`
+}
@@ -0,0 +1,25 @@
+package protocol
+
+// Message types for applying a unified diff patch to a file under FS_ROOT,
+// e.g. to push a small edit without transferring the whole file.
+const (
+	MsgTypePatchApplyRequest  MessageType = "patch_apply_request"
+	MsgTypePatchApplyResponse MessageType = "patch_apply_response"
+)
+
+// MsgPatchApplyRequest asks the agent to apply Diff (the output of
+// `diff -u`, see patchapply.Apply) to Path, resolved against FS_ROOT.
+type MsgPatchApplyRequest struct {
+	RequestID string `json:"request_id"`
+	Path      string `json:"path"`
+	Diff      string `json:"diff"`
+}
+
+// MsgPatchApplyResponse reports the result of a MsgPatchApplyRequest.
+// Error is non-empty on failure, in which case LinesChanged is 0 and the
+// file was left untouched.
+type MsgPatchApplyResponse struct {
+	RequestID    string `json:"request_id"`
+	LinesChanged int    `json:"lines_changed"`
+	Error        string `json:"error,omitempty"`
+}
@@ -0,0 +1,32 @@
+package protocol
+
+// Message type for structured, non-fatal error reports sent by the agent.
+const MsgTypeReportError MessageType = "report_error"
+
+// Error codes reported via MsgReportError.Code, one per known failure the
+// agent can hit outside of a MsgXxxResponse's own inline error field.
+const (
+	ErrCodeMetricsCollectFailed  = "metrics_collect_failed"
+	ErrCodeHeartbeatSendFailed   = "heartbeat_send_failed"
+	ErrCodeTerminalForwardFailed = "terminal_forward_failed"
+)
+
+// MsgReportError tells the Console about a non-fatal error the agent
+// encountered, which would otherwise only be visible in the agent's local
+// logs (e.g. a failed metrics collection or heartbeat send). Identical
+// Component+Code errors are deduplicated within a short window (see
+// errorreport.Reporter) so a persistent failure doesn't flood the Console
+// with duplicate reports.
+type MsgReportError struct {
+	ErrorID   string `json:"error_id"`
+	Component string `json:"component"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+
+	// StackTrace is the reporting goroutine's stack at the time the error
+	// was observed (see runtime/debug.Stack), not necessarily the stack
+	// at the point the error originated.
+	StackTrace string `json:"stack_trace,omitempty"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
@@ -0,0 +1,16 @@
+package protocol
+
+// MsgTypeSecretInject requests that a secret be made available as an
+// environment variable to one or all PTY sessions.
+const MsgTypeSecretInject MessageType = "secret_inject"
+
+// MsgSecretInject stores a named secret for injection into a PTY session's
+// environment. SessionID may be a specific session ID or "*" to make the
+// secret visible to every session. The secret is dropped after TTLSeconds
+// elapses.
+type MsgSecretInject struct {
+	SessionID  string `json:"session_id"`
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	TTLSeconds int    `json:"ttl_seconds"`
+}
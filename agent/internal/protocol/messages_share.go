@@ -0,0 +1,36 @@
+package protocol
+
+import "time"
+
+// Message types exchanged while sharing a terminal session with another
+// viewer via a time-limited token (see internal/sharetoken).
+const (
+	MsgTypeTerminalShareRequest  MessageType = "terminal_share_request"
+	MsgTypeTerminalShareResponse MessageType = "terminal_share_response"
+	MsgTypeTerminalJoin          MessageType = "terminal_join"
+)
+
+// MsgTerminalShareRequest asks the agent to mint a token that lets another
+// session join SessionID's output stream. MultiUse allows the token to be
+// redeemed more than once before it expires; by default a token is
+// single-use.
+type MsgTerminalShareRequest struct {
+	SessionID  string `json:"session_id"`
+	TTLSeconds int64  `json:"ttl_seconds"`
+	MultiUse   bool   `json:"multi_use,omitempty"`
+}
+
+// MsgTerminalShareResponse returns the token minted for a
+// MsgTerminalShareRequest and when it expires.
+type MsgTerminalShareResponse struct {
+	SessionID string    `json:"session_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// MsgTerminalJoin redeems Token to attach NewSessionID as an observer of
+// the session the token was issued for.
+type MsgTerminalJoin struct {
+	Token        string `json:"token"`
+	NewSessionID string `json:"new_session_id"`
+}
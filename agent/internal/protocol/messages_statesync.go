@@ -0,0 +1,25 @@
+package protocol
+
+// Message type for periodic full state sync from the agent to the
+// Console, complementing the lightweight heartbeat with build provenance
+// and other slowly-changing fields not worth including on every heartbeat
+// tick.
+const MsgTypeStateSync MessageType = "state_sync"
+
+// StateSyncPayload reports the agent's build provenance and other
+// slowly-changing state.
+type StateSyncPayload struct {
+	AgentVersion string `json:"agent_version"`
+	Hostname     string `json:"hostname"`
+	Environment  string `json:"environment,omitempty"`
+
+	// GitCommit and BuildDate identify the exact build running on this
+	// instance, populated from linker flags at build time. Both are
+	// empty on a non-ldflags build.
+	GitCommit string `json:"git_commit,omitempty"`
+	BuildDate string `json:"build_date,omitempty"`
+
+	// Labels holds Console-driven instance labels applied via
+	// MsgInstanceLabel (see labels.Store). Nil if none are set.
+	Labels map[string]string `json:"labels,omitempty"`
+}
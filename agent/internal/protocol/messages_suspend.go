@@ -0,0 +1,18 @@
+package protocol
+
+// Message types for pausing and resuming agent activity, e.g. while an
+// instance is being snapshotted or migrated.
+const (
+	MsgTypeInstanceSuspend MessageType = "instance_suspend"
+	MsgTypeInstanceResume  MessageType = "instance_resume"
+)
+
+// MsgInstanceSuspend asks the agent to pause heartbeat, metrics, and
+// terminal I/O activity until a MsgInstanceResume arrives. Reason is
+// free-form, for inclusion in agent logs (e.g. "snapshot", "migration").
+type MsgInstanceSuspend struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// MsgInstanceResume restores activity paused by a prior MsgInstanceSuspend.
+type MsgInstanceResume struct{}
@@ -0,0 +1,30 @@
+package protocol
+
+import "time"
+
+// Message types for streaming syslog entries to the Console.
+const (
+	MsgTypeSyslogSubscribe MessageType = "syslog_subscribe"
+	MsgTypeSyslogEntry     MessageType = "syslog_entry"
+)
+
+// MsgSyslogSubscribe asks the agent to start streaming syslog entries
+// matching Facility and Severity (both optional; empty matches anything)
+// under SubscriptionID.
+type MsgSyslogSubscribe struct {
+	SubscriptionID string `json:"subscription_id"`
+	Facility       string `json:"facility,omitempty"`
+	Severity       string `json:"severity,omitempty"`
+}
+
+// MsgSyslogEntry carries a single syslog entry matching an active
+// subscription.
+type MsgSyslogEntry struct {
+	SubscriptionID string    `json:"subscription_id"`
+	Timestamp      time.Time `json:"timestamp"`
+	Facility       string    `json:"facility"`
+	Severity       string    `json:"severity"`
+	Host           string    `json:"host"`
+	Program        string    `json:"program"`
+	Message        string    `json:"message"`
+}
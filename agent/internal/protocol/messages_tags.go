@@ -0,0 +1,14 @@
+package protocol
+
+// Message type for incremental instance tag updates.
+const MsgTypeTagUpdate MessageType = "tag_update"
+
+// MsgTagUpdate incrementally applies instance tag changes, sent by either
+// side: the Console pushes it to update tags the agent should report on
+// itself, and the agent sends it to update the Console's instance
+// registry, without requiring a full re-registration for tags that change
+// frequently (e.g. "job": "active" toggled many times).
+type MsgTagUpdate struct {
+	Tags   map[string]string `json:"tags,omitempty"`
+	Remove []string          `json:"remove,omitempty"`
+}
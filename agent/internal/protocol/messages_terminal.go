@@ -0,0 +1,170 @@
+package protocol
+
+import "encoding/json"
+
+// Terminal output encodings for MsgTerminalOutput.Data.
+const (
+	// TerminalOutputEncodingBase64 is the default: Data is transported as
+	// a base64 string, as encoding/json does for any []byte field,
+	// tolerating arbitrary binary output.
+	TerminalOutputEncodingBase64 = "base64"
+
+	// TerminalOutputEncodingUTF8 transports Data as a raw JSON string
+	// instead of base64, for Consoles that predate base64 support. Only
+	// valid when the bytes are confirmed valid UTF-8 (see
+	// terminal.NewOutputMessage) — MsgTerminalOutput itself does not
+	// re-validate on marshal.
+	TerminalOutputEncodingUTF8 = "utf8"
+)
+
+// Message types for PTY-backed terminal sessions.
+const (
+	MsgTypeTerminalInput       MessageType = "terminal_input"
+	MsgTypeTerminalBell        MessageType = "terminal_bell"
+	MsgTypeTerminalOutput      MessageType = "terminal_output"
+	MsgTypeTerminalClose       MessageType = "terminal_close"
+	MsgTypeTerminalClosed      MessageType = "terminal_closed"
+	MsgTypeTerminalResize      MessageType = "terminal_resize"
+	MsgTypeTerminalResizeACK   MessageType = "terminal_resize_ack"
+	MsgTypeTerminalResizeError MessageType = "terminal_resize_error"
+	MsgTypeTerminalSendSignal  MessageType = "terminal_send_signal"
+)
+
+// MsgTerminalInput carries raw bytes to write to a session's PTY. Data is
+// transported as a []byte (base64-encoded by encoding/json), not a string,
+// so that NUL bytes and other non-UTF8-safe sequences survive the round
+// trip intact — a string field would risk callers on either end treating
+// embedded NUL bytes as a terminator.
+type MsgTerminalInput struct {
+	SessionID string `json:"session_id"`
+	Data      []byte `json:"data"`
+}
+
+// MsgTerminalBell is sent when a session's PTY output contains a BEL
+// (0x07) character, so the Console can surface a visual or audible alert.
+type MsgTerminalBell struct {
+	SessionID string `json:"session_id"`
+}
+
+// MsgTerminalOutput carries PTY output bound for the Console. When
+// Compressed is true, Data holds gzip-compressed bytes rather than raw PTY
+// output, reducing bandwidth for high-frequency output sessions (e.g. a
+// build log tailing at high volume).
+//
+// Encoding controls how Data is marshaled: TerminalOutputEncodingBase64
+// (the default, used when Encoding is empty) transports it as
+// encoding/json normally would for a []byte, and
+// TerminalOutputEncodingUTF8 transports it as a raw JSON string for
+// Consoles that predate base64 support. MsgTerminalOutput implements
+// json.Marshaler/Unmarshaler to switch between the two.
+type MsgTerminalOutput struct {
+	SessionID  string `json:"session_id"`
+	Data       []byte `json:"data"`
+	Compressed bool   `json:"compressed"`
+	Encoding   string `json:"encoding,omitempty"`
+}
+
+// msgTerminalOutputWire is MsgTerminalOutput's wire representation, with
+// Data left as raw JSON so MarshalJSON/UnmarshalJSON can switch its shape
+// based on Encoding.
+type msgTerminalOutputWire struct {
+	SessionID  string          `json:"session_id"`
+	Data       json.RawMessage `json:"data"`
+	Compressed bool            `json:"compressed"`
+	Encoding   string          `json:"encoding,omitempty"`
+}
+
+// MarshalJSON encodes Data as a raw UTF-8 JSON string when Encoding is
+// TerminalOutputEncodingUTF8, and as base64 (encoding/json's default for
+// []byte) otherwise.
+func (m MsgTerminalOutput) MarshalJSON() ([]byte, error) {
+	wire := msgTerminalOutputWire{
+		SessionID:  m.SessionID,
+		Compressed: m.Compressed,
+		Encoding:   m.Encoding,
+	}
+	if m.Encoding == TerminalOutputEncodingUTF8 {
+		data, err := json.Marshal(string(m.Data))
+		if err != nil {
+			return nil, err
+		}
+		wire.Data = data
+	} else {
+		data, err := json.Marshal(m.Data)
+		if err != nil {
+			return nil, err
+		}
+		wire.Data = data
+	}
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON decodes Data according to Encoding, the inverse of
+// MarshalJSON.
+func (m *MsgTerminalOutput) UnmarshalJSON(data []byte) error {
+	var wire msgTerminalOutputWire
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	m.SessionID = wire.SessionID
+	m.Compressed = wire.Compressed
+	m.Encoding = wire.Encoding
+
+	if wire.Encoding == TerminalOutputEncodingUTF8 {
+		var s string
+		if err := json.Unmarshal(wire.Data, &s); err != nil {
+			return err
+		}
+		m.Data = []byte(s)
+		return nil
+	}
+	var b []byte
+	if err := json.Unmarshal(wire.Data, &b); err != nil {
+		return err
+	}
+	m.Data = b
+	return nil
+}
+
+// MsgTerminalClose asks the agent to terminate a session.
+type MsgTerminalClose struct {
+	SessionID string `json:"session_id"`
+}
+
+// MsgTerminalClosed acknowledges that a session has been terminated,
+// reporting its final exit code.
+type MsgTerminalClosed struct {
+	SessionID string `json:"session_id"`
+	ExitCode  int    `json:"exit_code"`
+}
+
+// MsgTerminalResize asks the agent to resize a session's PTY.
+type MsgTerminalResize struct {
+	SessionID string `json:"session_id"`
+	Cols      uint16 `json:"cols"`
+	Rows      uint16 `json:"rows"`
+}
+
+// MsgTerminalResizeACK confirms that a resize was applied, reporting the
+// dimensions actually in effect (read back from the PTY), which may differ
+// from the requested ones if the terminal clamped them.
+type MsgTerminalResizeACK struct {
+	SessionID string `json:"session_id"`
+	Cols      uint16 `json:"cols"`
+	Rows      uint16 `json:"rows"`
+}
+
+// MsgTerminalResizeError reports that a resize could not be applied.
+type MsgTerminalResizeError struct {
+	SessionID string `json:"session_id"`
+	Error     string `json:"error"`
+}
+
+// MsgTerminalSendSignal asks the agent to deliver an OS signal to a
+// session's child process. Signal is a raw signal number (e.g. 15 for
+// SIGTERM) rather than a name, since the wire format shouldn't depend on
+// syscall.Signal's platform-specific String() representation.
+type MsgTerminalSendSignal struct {
+	SessionID string `json:"session_id"`
+	Signal    int    `json:"signal"`
+}
@@ -0,0 +1,78 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestMsgTerminalInputRoundTripsNULBytes(t *testing.T) {
+	original := MsgTerminalInput{
+		SessionID: "sess-1",
+		Data:      []byte("before\x00middle\x00after"),
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var decoded MsgTerminalInput
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if !bytes.Equal(decoded.Data, original.Data) {
+		t.Errorf("got %q, want %q", decoded.Data, original.Data)
+	}
+}
+
+func TestMsgTerminalOutputDefaultsToBase64Encoding(t *testing.T) {
+	original := MsgTerminalOutput{SessionID: "sess-1", Data: []byte("hello\n")}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !bytes.Contains(data, []byte(`"data":"aGVsbG8K"`)) {
+		t.Errorf("expected base64-encoded data field, got %s", data)
+	}
+
+	var decoded MsgTerminalOutput
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !bytes.Equal(decoded.Data, original.Data) {
+		t.Errorf("got %q, want %q", decoded.Data, original.Data)
+	}
+	if decoded.Encoding != "" {
+		t.Errorf("Encoding = %q, want empty (base64 is the implicit default)", decoded.Encoding)
+	}
+}
+
+func TestMsgTerminalOutputUTF8EncodingRoundTrips(t *testing.T) {
+	original := MsgTerminalOutput{
+		SessionID: "sess-1",
+		Data:      []byte("hello, world\n"),
+		Encoding:  TerminalOutputEncodingUTF8,
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !bytes.Contains(data, []byte(`"data":"hello, world\n"`)) {
+		t.Errorf("expected raw UTF-8 string data field, got %s", data)
+	}
+
+	var decoded MsgTerminalOutput
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !bytes.Equal(decoded.Data, original.Data) {
+		t.Errorf("got %q, want %q", decoded.Data, original.Data)
+	}
+	if decoded.Encoding != TerminalOutputEncodingUTF8 {
+		t.Errorf("Encoding = %q, want %q", decoded.Encoding, TerminalOutputEncodingUTF8)
+	}
+}
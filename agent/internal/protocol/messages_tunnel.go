@@ -0,0 +1,24 @@
+package protocol
+
+// Message types for local TCP port forwarding through the agent.
+const (
+	MsgTypePortForwardStart MessageType = "port_forward_start"
+	MsgTypePortForwardStop  MessageType = "port_forward_stop"
+)
+
+// MsgPortForwardStart asks the agent to forward connections to
+// RemoteHost:RemotePort, exposed locally as LocalPort for the Console's
+// own bookkeeping (the agent itself listens on a Unix socket, never a TCP
+// port — see internal/tunnel).
+type MsgPortForwardStart struct {
+	TunnelID   string `json:"tunnel_id"`
+	RemoteHost string `json:"remote_host"`
+	RemotePort int    `json:"remote_port"`
+	LocalPort  int    `json:"local_port"`
+}
+
+// MsgPortForwardStop asks the agent to tear down a tunnel started by an
+// earlier MsgPortForwardStart.
+type MsgPortForwardStop struct {
+	TunnelID string `json:"tunnel_id"`
+}
@@ -0,0 +1,28 @@
+package protocol
+
+import "time"
+
+// MsgTypeWatchdogPing identifies a MsgWatchdogPing envelope, sent
+// Console -> Agent as an application-level liveness check independent of
+// the WebSocket ping/pong frames, which only prove the TCP connection is
+// still open.
+const MsgTypeWatchdogPing MessageType = "watchdog_ping"
+
+// MsgTypeWatchdogPong identifies a MsgWatchdogPong envelope, sent
+// Agent -> Console in reply to a MsgWatchdogPing.
+const MsgTypeWatchdogPong MessageType = "watchdog_pong"
+
+// MsgWatchdogPing is sent periodically by the Console so the agent can
+// detect a Console that has stopped responding even though the underlying
+// connection looks alive (see watchdog.Monitor).
+type MsgWatchdogPing struct {
+	PingID string    `json:"ping_id"`
+	SentAt time.Time `json:"sent_at"`
+}
+
+// MsgWatchdogPong replies to a MsgWatchdogPing, echoing its PingID so the
+// Console can match the reply and measure round-trip time.
+type MsgWatchdogPong struct {
+	PingID     string    `json:"ping_id"`
+	ReceivedAt time.Time `json:"received_at"`
+}
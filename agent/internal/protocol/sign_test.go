@@ -0,0 +1,64 @@
+package protocol
+
+import "testing"
+
+func TestSignAndVerifyRoundTrip(t *testing.T) {
+	env, err := NewEnvelope(MsgTypeTerminalBell, MsgTerminalBell{SessionID: "sess-1"})
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+	secret := []byte("test-secret")
+
+	if err := Sign(env, secret); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if env.Signature == "" {
+		t.Fatal("expected Signature to be set after Sign")
+	}
+	if err := Verify(*env, secret); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+func TestVerifyRejectsTamperedPayload(t *testing.T) {
+	env, err := NewEnvelope(MsgTypeTerminalBell, MsgTerminalBell{SessionID: "sess-1"})
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+	secret := []byte("test-secret")
+	if err := Sign(env, secret); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	env.Payload = []byte(`{"session_id":"sess-2"}`)
+
+	if err := Verify(*env, secret); err == nil {
+		t.Fatal("expected Verify to reject a tampered payload")
+	}
+}
+
+func TestVerifyRejectsWrongSecret(t *testing.T) {
+	env, err := NewEnvelope(MsgTypeTerminalBell, MsgTerminalBell{SessionID: "sess-1"})
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+	if err := Sign(env, []byte("correct-secret")); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	if err := Verify(*env, []byte("wrong-secret")); err == nil {
+		t.Fatal("expected Verify to reject a mismatched secret")
+	}
+}
+
+func TestVerifyRejectsMalformedSignature(t *testing.T) {
+	env, err := NewEnvelope(MsgTypeTerminalBell, MsgTerminalBell{SessionID: "sess-1"})
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+	env.Signature = "not-hex"
+
+	if err := Verify(*env, []byte("secret")); err == nil {
+		t.Fatal("expected Verify to reject a non-hex signature")
+	}
+}
@@ -0,0 +1,78 @@
+// Package redact detects sensitive prompts in observed PTY output so a
+// session's next input can be withheld from logs that would otherwise
+// capture it in plaintext.
+package redact
+
+import (
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Redacted is substituted for input bytes withheld by a PasswordRedactor,
+// e.g. in a terminal audit log record.
+const Redacted = "***"
+
+// window is how long after a detected password prompt input stays
+// redacted, covering the delay between a prompt being printed and the
+// operator finishing typing their response.
+const window = 5 * time.Second
+
+// ringSize bounds how many trailing bytes of output a PasswordRedactor
+// retains, large enough to catch a prompt pattern split across two
+// consecutive PTY reads without retaining unbounded scrollback.
+const ringSize = 256
+
+// promptPatterns matches common password/passphrase prompts emitted by
+// sudo, ssh, su, and similar tools.
+var promptPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`[Pp]assword:\s*$`),
+	regexp.MustCompile(`[Pp]assword \(again\):\s*$`),
+	regexp.MustCompile(`Enter passphrase.*:\s*$`),
+	regexp.MustCompile(`\(current\) UNIX password:\s*$`),
+}
+
+// PasswordRedactor tracks whether a password prompt was recently observed
+// in a session's PTY output.
+type PasswordRedactor struct {
+	mu     sync.Mutex
+	ring   []byte
+	seenAt time.Time
+}
+
+// NewPasswordRedactor returns a PasswordRedactor that has not observed a
+// prompt yet.
+func NewPasswordRedactor() *PasswordRedactor {
+	return &PasswordRedactor{}
+}
+
+// Observe scans chunk, together with up to ringSize trailing bytes
+// retained from earlier calls, for a password prompt pattern, recording
+// the current time if one is found.
+func (r *PasswordRedactor) Observe(chunk []byte) {
+	r.mu.Lock()
+	r.ring = append(r.ring, chunk...)
+	if len(r.ring) > ringSize {
+		r.ring = r.ring[len(r.ring)-ringSize:]
+	}
+	buf := r.ring
+	r.mu.Unlock()
+
+	for _, p := range promptPatterns {
+		if p.Match(buf) {
+			r.mu.Lock()
+			r.seenAt = time.Now()
+			r.mu.Unlock()
+			return
+		}
+	}
+}
+
+// ShouldRedact reports whether a password prompt was observed within the
+// last 5 seconds, meaning the caller's next input should be withheld from
+// logs.
+func (r *PasswordRedactor) ShouldRedact() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !r.seenAt.IsZero() && time.Since(r.seenAt) < window
+}
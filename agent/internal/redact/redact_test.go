@@ -0,0 +1,43 @@
+package redact
+
+import "testing"
+
+func TestShouldRedactFalseBeforeAnyPromptObserved(t *testing.T) {
+	r := NewPasswordRedactor()
+	if r.ShouldRedact() {
+		t.Error("ShouldRedact() = true before any output observed, want false")
+	}
+}
+
+func TestObserveDetectsPasswordPrompt(t *testing.T) {
+	r := NewPasswordRedactor()
+	r.Observe([]byte("Password: "))
+	if !r.ShouldRedact() {
+		t.Error("ShouldRedact() = false after observing a password prompt, want true")
+	}
+}
+
+func TestObserveDetectsPassphrasePrompt(t *testing.T) {
+	r := NewPasswordRedactor()
+	r.Observe([]byte("Enter passphrase for key '/home/op/.ssh/id_ed25519': "))
+	if !r.ShouldRedact() {
+		t.Error("ShouldRedact() = false after observing a passphrase prompt, want true")
+	}
+}
+
+func TestObserveIgnoresUnrelatedOutput(t *testing.T) {
+	r := NewPasswordRedactor()
+	r.Observe([]byte("$ ls\nfile1.txt file2.txt\n"))
+	if r.ShouldRedact() {
+		t.Error("ShouldRedact() = true after unrelated output, want false")
+	}
+}
+
+func TestObserveDetectsPromptSplitAcrossChunks(t *testing.T) {
+	r := NewPasswordRedactor()
+	r.Observe([]byte("[sudo] "))
+	r.Observe([]byte("password: "))
+	if !r.ShouldRedact() {
+		t.Error("ShouldRedact() = false after prompt split across two Observe calls, want true")
+	}
+}
@@ -0,0 +1,31 @@
+package registration
+
+import (
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/auth"
+)
+
+func TestRegistrarAuthHeadersAppliesStrategy(t *testing.T) {
+	r := NewRegistrar(nil)
+	r.AuthStrategy = auth.BearerTokenStrategy{Token: "secret-token"}
+
+	header, err := r.AuthHeaders()
+	if err != nil {
+		t.Fatalf("AuthHeaders: %v", err)
+	}
+	if got := header.Get("Authorization"); got != "Bearer secret-token" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer secret-token")
+	}
+}
+
+func TestRegistrarAuthHeadersWithoutStrategy(t *testing.T) {
+	r := NewRegistrar(nil)
+	header, err := r.AuthHeaders()
+	if err != nil {
+		t.Fatalf("AuthHeaders: %v", err)
+	}
+	if len(header) != 0 {
+		t.Errorf("expected no headers without a configured strategy, got %v", header)
+	}
+}
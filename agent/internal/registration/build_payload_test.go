@@ -0,0 +1,23 @@
+package registration
+
+import "testing"
+
+func TestBuildPayloadIncludesEnvironment(t *testing.T) {
+	payload := BuildPayload("v1.2.3", "host-1", "staging", "abc123", "2025-01-01")
+	if payload.Environment != "staging" {
+		t.Errorf("Environment = %q, want staging", payload.Environment)
+	}
+	if payload.AgentVersion != "v1.2.3" || payload.Hostname != "host-1" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+	if payload.GitCommit != "abc123" || payload.BuildDate != "2025-01-01" {
+		t.Errorf("unexpected build provenance: %+v", payload)
+	}
+}
+
+func TestBuildPayloadEmptyBuildProvenanceIsNotAnError(t *testing.T) {
+	payload := BuildPayload("v1.2.3", "host-1", "staging", "", "")
+	if payload.GitCommit != "" || payload.BuildDate != "" {
+		t.Errorf("unexpected build provenance: %+v", payload)
+	}
+}
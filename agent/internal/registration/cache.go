@@ -0,0 +1,101 @@
+package registration
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Cache persists instance tags and Console-assigned metadata to disk so
+// they survive an agent restart without requiring a full re-registration
+// to restore them.
+type Cache struct {
+	Path string
+}
+
+// NewCache returns a Cache backed by the file at path.
+func NewCache(path string) *Cache {
+	return &Cache{Path: path}
+}
+
+// cacheState is the on-disk shape of a Cache file.
+type cacheState struct {
+	Tags       map[string]string `json:"tags,omitempty"`
+	AssignedID string            `json:"assigned_id,omitempty"`
+}
+
+// readState reads and parses the cache file, returning a zero cacheState
+// if it doesn't exist yet.
+func (c *Cache) readState() (cacheState, error) {
+	data, err := os.ReadFile(c.Path)
+	if os.IsNotExist(err) {
+		return cacheState{}, nil
+	}
+	if err != nil {
+		return cacheState{}, fmt.Errorf("registration: read cache: %w", err)
+	}
+
+	var state cacheState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return cacheState{}, fmt.Errorf("registration: parse cache: %w", err)
+	}
+	return state, nil
+}
+
+// writeState marshals state to c.Path, replacing any previously cached
+// value.
+func (c *Cache) writeState(state cacheState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("registration: marshal cache: %w", err)
+	}
+	if err := os.WriteFile(c.Path, data, 0o644); err != nil {
+		return fmt.Errorf("registration: write cache: %w", err)
+	}
+	return nil
+}
+
+// Load reads the cached tags from disk. A missing file is not an error; it
+// returns an empty map.
+func (c *Cache) Load() (map[string]string, error) {
+	state, err := c.readState()
+	if err != nil {
+		return nil, err
+	}
+	if state.Tags == nil {
+		return map[string]string{}, nil
+	}
+	return state.Tags, nil
+}
+
+// Save writes tags to disk, replacing any previously cached tags but
+// leaving a cached AssignedID (see SaveAssignedID) untouched.
+func (c *Cache) Save(tags map[string]string) error {
+	state, err := c.readState()
+	if err != nil {
+		return err
+	}
+	state.Tags = tags
+	return c.writeState(state)
+}
+
+// LoadAssignedID returns the Console-assigned instance ID cached by a
+// previous call to SaveAssignedID, or "" if none has been cached.
+func (c *Cache) LoadAssignedID() (string, error) {
+	state, err := c.readState()
+	if err != nil {
+		return "", err
+	}
+	return state.AssignedID, nil
+}
+
+// SaveAssignedID caches id, leaving any previously cached tags (see Save)
+// untouched.
+func (c *Cache) SaveAssignedID(id string) error {
+	state, err := c.readState()
+	if err != nil {
+		return err
+	}
+	state.AssignedID = id
+	return c.writeState(state)
+}
@@ -0,0 +1,45 @@
+package registration
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheSaveAndLoadTagsPreservesAssignedID(t *testing.T) {
+	cache := NewCache(filepath.Join(t.TempDir(), "registration.json"))
+
+	if err := cache.SaveAssignedID("i-1"); err != nil {
+		t.Fatalf("SaveAssignedID: %v", err)
+	}
+	if err := cache.Save(map[string]string{"team": "infra"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	tags, err := cache.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if tags["team"] != "infra" {
+		t.Errorf("Load() = %+v, want team=infra", tags)
+	}
+
+	assignedID, err := cache.LoadAssignedID()
+	if err != nil {
+		t.Fatalf("LoadAssignedID: %v", err)
+	}
+	if assignedID != "i-1" {
+		t.Errorf("LoadAssignedID() = %q, want %q (Save must not clobber a cached AssignedID)", assignedID, "i-1")
+	}
+}
+
+func TestCacheLoadAssignedIDMissingFileReturnsEmpty(t *testing.T) {
+	cache := NewCache(filepath.Join(t.TempDir(), "does-not-exist.json"))
+
+	assignedID, err := cache.LoadAssignedID()
+	if err != nil {
+		t.Fatalf("LoadAssignedID: %v", err)
+	}
+	if assignedID != "" {
+		t.Errorf("LoadAssignedID() = %q, want empty for a missing cache file", assignedID)
+	}
+}
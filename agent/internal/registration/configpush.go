@@ -0,0 +1,41 @@
+package registration
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/config"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// ApplyConfigPush applies msg.Overrides onto cfg's known, safely
+// overridable fields. Unknown keys are ignored rather than rejected, so an
+// older agent can tolerate overrides meant for a newer one.
+func ApplyConfigPush(cfg *config.Config, msg protocol.MsgConfigPush) error {
+	for key, value := range msg.Overrides {
+		switch key {
+		case "environment":
+			cfg.Environment = value
+		case "metrics_interval":
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("registration: parse config override %q: %w", key, err)
+			}
+			cfg.MetricsInterval = d
+		case "hb_disk_pressure_pct":
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("registration: parse config override %q: %w", key, err)
+			}
+			cfg.HBDiskPressurePct = f
+		case "hb_mem_pressure_pct":
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("registration: parse config override %q: %w", key, err)
+			}
+			cfg.HBMemPressurePct = f
+		}
+	}
+	return nil
+}
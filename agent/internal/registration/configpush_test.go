@@ -0,0 +1,43 @@
+package registration
+
+import (
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/config"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestApplyConfigPushOverridesKnownFields(t *testing.T) {
+	cfg := &config.Config{Environment: "production", HBDiskPressurePct: 90}
+
+	err := ApplyConfigPush(cfg, protocol.MsgConfigPush{Overrides: map[string]string{
+		"environment":           "staging",
+		"hb_disk_pressure_pct":  "75",
+		"metrics_interval":      "30s",
+		"unknown_future_option": "ignored",
+	}})
+	if err != nil {
+		t.Fatalf("ApplyConfigPush: %v", err)
+	}
+
+	if cfg.Environment != "staging" {
+		t.Errorf("Environment = %q, want %q", cfg.Environment, "staging")
+	}
+	if cfg.HBDiskPressurePct != 75 {
+		t.Errorf("HBDiskPressurePct = %v, want 75", cfg.HBDiskPressurePct)
+	}
+	if cfg.MetricsInterval.String() != "30s" {
+		t.Errorf("MetricsInterval = %v, want 30s", cfg.MetricsInterval)
+	}
+}
+
+func TestApplyConfigPushRejectsUnparsableValue(t *testing.T) {
+	cfg := &config.Config{}
+
+	err := ApplyConfigPush(cfg, protocol.MsgConfigPush{Overrides: map[string]string{
+		"hb_mem_pressure_pct": "not-a-number",
+	}})
+	if err == nil {
+		t.Fatal("expected an error for an unparsable override value")
+	}
+}
@@ -0,0 +1,98 @@
+package registration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/pacphi/sindri/agent/internal/auth"
+	"github.com/pacphi/sindri/agent/internal/config"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// New returns a Registrar whose Register performs an HTTP POST of the
+// RegistrationPayload to consoleURL. The client's Transport honors standard
+// HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment variables via
+// http.ProxyFromEnvironment, so registration traffic respects the same
+// proxy configuration operators already use for everything else on the
+// host.
+//
+// If the Console responds with a RegistrationResponse body, its AssignedID
+// is cached in cache (logging a warning if it differs from cfg.InstanceID)
+// and its ConfigOverrides are applied to cfg via ApplyConfigPush. Either of
+// cfg or cache may be nil to skip that handling.
+func New(consoleURL string, authStrategy auth.Strategy, cfg *config.Config, cache *Cache) *Registrar {
+	client := &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment},
+	}
+
+	r := NewRegistrar(func(ctx context.Context, payload RegistrationPayload, idempotencyKey string) error {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("registration: marshal payload: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, consoleURL, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("registration: build request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Idempotency-Key", idempotencyKey)
+		if authStrategy != nil {
+			if err := authStrategy.ApplyAuth(&req.Header); err != nil {
+				return fmt.Errorf("registration: apply auth: %w", err)
+			}
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("registration: post: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusConflict {
+			return ErrAlreadyRegistered
+		}
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("registration: console returned status %d", resp.StatusCode)
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("registration: read response body: %w", err)
+		}
+		if len(respBody) == 0 {
+			return nil
+		}
+
+		var regResp RegistrationResponse
+		if err := json.Unmarshal(respBody, &regResp); err != nil {
+			return fmt.Errorf("registration: parse response body: %w", err)
+		}
+
+		if regResp.AssignedID != "" {
+			if cache != nil {
+				if err := cache.SaveAssignedID(regResp.AssignedID); err != nil {
+					return fmt.Errorf("registration: persist assigned id: %w", err)
+				}
+			}
+			if cfg != nil && regResp.AssignedID != cfg.InstanceID {
+				log.Printf("registration: console assigned id %q differs from configured instance id %q", regResp.AssignedID, cfg.InstanceID)
+			}
+		}
+
+		if cfg != nil && len(regResp.ConfigOverrides) > 0 {
+			if err := ApplyConfigPush(cfg, protocol.MsgConfigPush{Overrides: regResp.ConfigOverrides}); err != nil {
+				return fmt.Errorf("registration: apply config overrides: %w", err)
+			}
+		}
+
+		return nil
+	})
+	r.AuthStrategy = authStrategy
+	return r
+}
@@ -0,0 +1,59 @@
+package registration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/config"
+)
+
+func TestNewRoutesThroughHTTPProxy(t *testing.T) {
+	var sawRequestURI string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawRequestURI = r.RequestURI
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	t.Setenv("HTTP_PROXY", proxy.URL)
+	t.Setenv("NO_PROXY", "")
+
+	r := New("http://console.internal/register", nil, nil, nil)
+	if err := r.Run(context.Background(), BuildPayload("v1", "host-1", "production", "", "")); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if sawRequestURI != "http://console.internal/register" {
+		t.Errorf("proxy saw RequestURI = %q, want the absolute console URL", sawRequestURI)
+	}
+}
+
+func TestNewStoresAssignedIDFromRegistrationResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"console_assigned_id":"i-assigned-1","config_overrides":{"environment":"staging"}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{InstanceID: "i-local-1"}
+	cache := NewCache(filepath.Join(t.TempDir(), "registration.json"))
+
+	r := New(server.URL, nil, cfg, cache)
+	if err := r.Run(context.Background(), BuildPayload("v1", "host-1", "production", "", "")); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	assignedID, err := cache.LoadAssignedID()
+	if err != nil {
+		t.Fatalf("LoadAssignedID: %v", err)
+	}
+	if assignedID != "i-assigned-1" {
+		t.Errorf("cached AssignedID = %q, want %q", assignedID, "i-assigned-1")
+	}
+	if cfg.Environment != "staging" {
+		t.Errorf("cfg.Environment = %q, want %q applied from ConfigOverrides", cfg.Environment, "staging")
+	}
+}
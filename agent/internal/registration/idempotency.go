@@ -0,0 +1,20 @@
+package registration
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// newIdempotencyKey returns a random UUIDv4 string, used to tag every
+// attempt within a single Register call so the Console can deduplicate a
+// registration that succeeded but whose response was lost in transit.
+func newIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("registration: generate idempotency key: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,71 @@
+package registration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistrarReusesIdempotencyKeyAcrossRetries(t *testing.T) {
+	var seenKeys []string
+	attempts := 0
+	r := NewRegistrar(func(ctx context.Context, payload RegistrationPayload, idempotencyKey string) error {
+		attempts++
+		seenKeys = append(seenKeys, idempotencyKey)
+		if attempts < 3 {
+			return errors.New("console unavailable")
+		}
+		return nil
+	})
+	r.InitialBackoff = time.Millisecond
+	r.MaxBackoff = 5 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := r.Run(ctx, RegistrationPayload{}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if len(seenKeys) != 3 {
+		t.Fatalf("got %d attempts, want 3", len(seenKeys))
+	}
+	for _, k := range seenKeys {
+		if k == "" {
+			t.Fatal("expected a non-empty idempotency key")
+		}
+		if k != seenKeys[0] {
+			t.Errorf("idempotency key changed across retries: %q != %q", k, seenKeys[0])
+		}
+	}
+}
+
+func TestRegistrarTreatsAlreadyRegisteredAsSuccess(t *testing.T) {
+	r := NewRegistrar(func(ctx context.Context, payload RegistrationPayload, idempotencyKey string) error {
+		return ErrAlreadyRegistered
+	})
+	r.InitialBackoff = time.Millisecond
+	r.MaxBackoff = 5 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := r.Run(ctx, RegistrationPayload{}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+}
+
+func TestNewIdempotencyKeyIsUnique(t *testing.T) {
+	a, err := newIdempotencyKey()
+	if err != nil {
+		t.Fatalf("newIdempotencyKey: %v", err)
+	}
+	b, err := newIdempotencyKey()
+	if err != nil {
+		t.Fatalf("newIdempotencyKey: %v", err)
+	}
+	if a == b {
+		t.Fatal("expected two generated keys to differ")
+	}
+}
@@ -0,0 +1,54 @@
+package registration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/cloudmetadata"
+)
+
+func TestBuildPayloadWithMetadataPrefixesTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"instanceId": "i-abc", "region": "us-east-1"}`))
+	}))
+	defer server.Close()
+
+	payload, err := BuildPayloadWithMetadata(context.Background(), "v1", "host-1", "production", "", "", server.URL, cloudmetadata.AWSParser{})
+	if err != nil {
+		t.Fatalf("BuildPayloadWithMetadata: %v", err)
+	}
+
+	if payload.Tags["cloud_metadata.instanceId"] != "i-abc" {
+		t.Errorf("Tags = %+v, want cloud_metadata.instanceId=i-abc", payload.Tags)
+	}
+	if payload.Tags["cloud_metadata.region"] != "us-east-1" {
+		t.Errorf("Tags = %+v, want cloud_metadata.region=us-east-1", payload.Tags)
+	}
+}
+
+func TestBuildPayloadWithMetadataServerErrorIsNonFatal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	payload, err := BuildPayloadWithMetadata(context.Background(), "v1", "host-1", "production", "", "", server.URL, cloudmetadata.AWSParser{})
+	if err == nil {
+		t.Fatal("expected a non-nil error to report to the caller")
+	}
+	if payload.AgentVersion != "v1" || payload.Tags != nil {
+		t.Errorf("expected a usable payload without cloud metadata tags, got %+v", payload)
+	}
+}
+
+func TestBuildPayloadWithMetadataSkipsWithoutURL(t *testing.T) {
+	payload, err := BuildPayloadWithMetadata(context.Background(), "v1", "host-1", "production", "", "", "", nil)
+	if err != nil {
+		t.Fatalf("BuildPayloadWithMetadata: %v", err)
+	}
+	if payload.Tags != nil {
+		t.Errorf("expected no tags when metadataURL is empty, got %+v", payload.Tags)
+	}
+}
@@ -0,0 +1,228 @@
+// Package registration handles the agent's initial registration with the
+// Console.
+package registration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/auth"
+	"github.com/pacphi/sindri/agent/internal/circuitbreaker"
+	"github.com/pacphi/sindri/agent/internal/cloudmetadata"
+)
+
+// RegistrationPayload identifies this agent instance to the Console during
+// registration.
+type RegistrationPayload struct {
+	AgentVersion string `json:"agent_version"`
+	Hostname     string `json:"hostname"`
+
+	// Environment identifies the deployment environment this agent is
+	// running in (e.g. "production", "staging"), so operators can
+	// distinguish them without relying on tags.
+	Environment string `json:"environment,omitempty"`
+
+	// Tags holds free-form key/value metadata about the instance,
+	// including any cloud instance metadata fetched during registration
+	// (under a "cloud_metadata." key prefix).
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// GitCommit and BuildDate identify the exact build running on this
+	// instance, populated from linker flags at build time. Both are
+	// empty on a non-ldflags (e.g. local "go build") build.
+	GitCommit string `json:"git_commit,omitempty"`
+	BuildDate string `json:"build_date,omitempty"`
+}
+
+// BuildPayload constructs the RegistrationPayload sent to the Console.
+func BuildPayload(agentVersion, hostname, environment, gitCommit, buildDate string) RegistrationPayload {
+	return RegistrationPayload{
+		AgentVersion: agentVersion,
+		Hostname:     hostname,
+		Environment:  environment,
+		GitCommit:    gitCommit,
+		BuildDate:    buildDate,
+	}
+}
+
+// RegistrationResponse is the Console's JSON response body to a successful
+// registration, letting it correct or extend what the agent believes about
+// itself without a separate round trip.
+type RegistrationResponse struct {
+	// AssignedID is the instance ID the Console has assigned, which may
+	// differ from the agent's own cfg.InstanceID (e.g. if the agent was
+	// never given one). Empty if the Console accepted the agent's own ID
+	// as-is.
+	AssignedID string `json:"console_assigned_id,omitempty"`
+
+	// Region corrects the agent's belief about which region it's running
+	// in, e.g. when cloud instance metadata was unavailable or wrong.
+	Region string `json:"region,omitempty"`
+
+	// ConfigOverrides is a flat key/value map applied to the running
+	// config the same way a MsgConfigPush would be (see
+	// registration.ApplyConfigPush).
+	ConfigOverrides map[string]string `json:"config_overrides,omitempty"`
+}
+
+// cloudMetadataTagPrefix namespaces cloud instance metadata within
+// RegistrationPayload.Tags so it can't collide with other tag sources.
+const cloudMetadataTagPrefix = "cloud_metadata."
+
+// BuildPayloadWithMetadata builds a RegistrationPayload as BuildPayload
+// does, additionally fetching cloud instance metadata from metadataURL (if
+// non-empty) and merging it into Tags under a "cloud_metadata." prefix. A
+// failure to fetch or parse metadata is non-fatal: it is returned as an
+// error for the caller to log, but the payload is still returned without
+// the cloud metadata tags so registration can proceed.
+func BuildPayloadWithMetadata(ctx context.Context, agentVersion, hostname, environment, gitCommit, buildDate, metadataURL string, parser cloudmetadata.Parser) (RegistrationPayload, error) {
+	payload := BuildPayload(agentVersion, hostname, environment, gitCommit, buildDate)
+	if metadataURL == "" || parser == nil {
+		return payload, nil
+	}
+
+	tags, err := cloudmetadata.Fetch(ctx, metadataURL, parser, metadataFetchTimeout)
+	if err != nil {
+		return payload, fmt.Errorf("registration: fetch cloud metadata: %w", err)
+	}
+
+	payload.Tags = make(map[string]string, len(tags))
+	for k, v := range tags {
+		payload.Tags[cloudMetadataTagPrefix+k] = v
+	}
+	return payload, nil
+}
+
+// metadataFetchTimeout bounds how long BuildPayloadWithMetadata waits for
+// the cloud metadata service to respond.
+const metadataFetchTimeout = 2 * time.Second
+
+// ErrAlreadyRegistered should be returned by a RegisterFunc when the
+// Console responds 409 Conflict for the same idempotency key, meaning an
+// earlier attempt already succeeded and its response was simply lost in
+// transit. Run treats it the same as a nil error.
+var ErrAlreadyRegistered = errors.New("registration: already registered (idempotent replay)")
+
+// RegisterFunc performs a single registration attempt, sending
+// idempotencyKey as the X-Idempotency-Key header so the Console can
+// deduplicate retries of the same logical registration.
+type RegisterFunc func(ctx context.Context, payload RegistrationPayload, idempotencyKey string) error
+
+// Registrar retries registration with the Console using exponential
+// backoff, bounded by the context passed to Register.
+type Registrar struct {
+	Register RegisterFunc
+
+	// InitialBackoff is the delay before the first retry. Defaults to
+	// 500ms if zero.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries. Defaults to 30s if
+	// zero.
+	MaxBackoff time.Duration
+
+	// AuthStrategy, if set, is applied to the headers returned by
+	// AuthHeaders, letting RegisterFunc implementations authenticate
+	// their request without this package hard-coding a scheme.
+	AuthStrategy auth.Strategy
+
+	// Breaker, if set, wraps every Register call so that a Console
+	// that's down or unreachable stops paying for repeated failed
+	// attempts once Breaker trips open; Run's own backoff still applies
+	// between attempts either way.
+	Breaker *circuitbreaker.Breaker
+
+	mu          sync.Mutex
+	lastSuccess time.Time
+}
+
+// LastSuccess returns the time of r's most recent successful registration,
+// or the zero time if none has ever succeeded.
+func (r *Registrar) LastSuccess() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastSuccess
+}
+
+// AuthHeaders returns the headers produced by r.AuthStrategy, or an empty
+// header set if no strategy is configured. RegisterFunc implementations
+// should include these on their outbound registration request.
+func (r *Registrar) AuthHeaders() (http.Header, error) {
+	header := http.Header{}
+	if r.AuthStrategy == nil {
+		return header, nil
+	}
+	if err := r.AuthStrategy.ApplyAuth(&header); err != nil {
+		return nil, fmt.Errorf("registration: apply auth: %w", err)
+	}
+	return header, nil
+}
+
+// register performs a single attempt via r.Register, routing it through
+// r.Breaker if set so a Console that keeps failing stops taking the cost
+// of the attempt itself (the outbound request, its timeout) once the
+// breaker trips open.
+func (r *Registrar) register(ctx context.Context, payload RegistrationPayload, idempotencyKey string) error {
+	if r.Breaker == nil {
+		return r.Register(ctx, payload, idempotencyKey)
+	}
+	return r.Breaker.Do(func() error {
+		return r.Register(ctx, payload, idempotencyKey)
+	})
+}
+
+// NewRegistrar returns a Registrar that performs attempts via register.
+func NewRegistrar(register RegisterFunc) *Registrar {
+	return &Registrar{
+		Register:       register,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// Run retries Register with exponential backoff until it succeeds or ctx is
+// done, in which case it returns an error wrapping ctx.Err() (so
+// errors.Is matches it) that also names the last attempt's error. A single
+// idempotency key is
+// generated once for the whole call and reused across every retry attempt,
+// so the Console can recognize retries of the same logical registration
+// even if an earlier attempt's response was lost in transit.
+func (r *Registrar) Run(ctx context.Context, payload RegistrationPayload) error {
+	backoff := r.InitialBackoff
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+	maxBackoff := r.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 30 * time.Second
+	}
+
+	idempotencyKey, err := newIdempotencyKey()
+	if err != nil {
+		return err
+	}
+
+	for {
+		err := r.register(ctx, payload, idempotencyKey)
+		if err == nil || errors.Is(err, ErrAlreadyRegistered) {
+			r.mu.Lock()
+			r.lastSuccess = time.Now()
+			r.mu.Unlock()
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("registration: giving up after error %q: %w", err, ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package registration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistrarRetriesUntilSuccess(t *testing.T) {
+	attempts := 0
+	r := NewRegistrar(func(ctx context.Context, payload RegistrationPayload, idempotencyKey string) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("console unavailable")
+		}
+		return nil
+	})
+	r.InitialBackoff = time.Millisecond
+	r.MaxBackoff = 5 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := r.Run(ctx, RegistrationPayload{}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestRegistrarStopsAtContextDeadline(t *testing.T) {
+	r := NewRegistrar(func(ctx context.Context, payload RegistrationPayload, idempotencyKey string) error {
+		return errors.New("console unavailable")
+	})
+	r.InitialBackoff = time.Millisecond
+	r.MaxBackoff = 2 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := r.Run(ctx, RegistrationPayload{})
+	if err == nil {
+		t.Fatal("expected Run to return an error once the deadline passes")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want error wrapping context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,30 @@
+package registration
+
+import (
+	"fmt"
+
+	"github.com/pacphi/sindri/agent/internal/config"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// ApplyTagUpdate merges upd.Tags into cfg.Tags, then deletes upd.Remove
+// keys. The resulting tag set is persisted to cache (if non-nil) so it
+// survives an agent restart without a full re-registration.
+func ApplyTagUpdate(cfg *config.Config, cache *Cache, upd protocol.MsgTagUpdate) error {
+	if cfg.Tags == nil {
+		cfg.Tags = make(map[string]string, len(upd.Tags))
+	}
+	for k, v := range upd.Tags {
+		cfg.Tags[k] = v
+	}
+	for _, k := range upd.Remove {
+		delete(cfg.Tags, k)
+	}
+
+	if cache != nil {
+		if err := cache.Save(cfg.Tags); err != nil {
+			return fmt.Errorf("registration: persist tag update: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package registration
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/config"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestApplyTagUpdateMergesAndRemoves(t *testing.T) {
+	cache := NewCache(filepath.Join(t.TempDir(), "tags.json"))
+	cfg := &config.Config{}
+
+	if err := ApplyTagUpdate(cfg, cache, protocol.MsgTagUpdate{Tags: map[string]string{"job": "active", "team": "infra"}}); err != nil {
+		t.Fatalf("ApplyTagUpdate (add): %v", err)
+	}
+	if cfg.Tags["job"] != "active" || cfg.Tags["team"] != "infra" {
+		t.Fatalf("cfg.Tags = %+v, want job=active team=infra", cfg.Tags)
+	}
+
+	if err := ApplyTagUpdate(cfg, cache, protocol.MsgTagUpdate{Remove: []string{"job"}}); err != nil {
+		t.Fatalf("ApplyTagUpdate (remove): %v", err)
+	}
+	if _, ok := cfg.Tags["job"]; ok {
+		t.Errorf("cfg.Tags still has %q after removal: %+v", "job", cfg.Tags)
+	}
+	if cfg.Tags["team"] != "infra" {
+		t.Errorf("cfg.Tags lost unrelated key: %+v", cfg.Tags)
+	}
+
+	cached, err := cache.Load()
+	if err != nil {
+		t.Fatalf("cache.Load: %v", err)
+	}
+	if len(cached) != 1 || cached["team"] != "infra" {
+		t.Errorf("cached tags = %+v, want {team: infra}", cached)
+	}
+}
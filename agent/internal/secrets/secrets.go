@@ -0,0 +1,88 @@
+// Package secrets holds short-lived, in-memory secrets for injection into
+// PTY session environments. It never logs a secret's value.
+package secrets
+
+import (
+	"sync"
+	"time"
+)
+
+// entry holds a single stored secret. value is a byte slice rather than a
+// string so it can be actively overwritten on expiry — Go strings are
+// immutable, so there is no way to scrub the memory backing one once
+// created.
+type entry struct {
+	sessionID string
+	name      string
+	value     []byte
+	expiresAt time.Time
+}
+
+// Store holds secrets pending injection into PTY session environments,
+// keyed by the session ID they are visible to. It is safe for concurrent
+// use.
+type Store struct {
+	mu      sync.Mutex
+	entries []*entry
+}
+
+// NewStore returns an empty Store.
+func NewStore() *Store {
+	return &Store{}
+}
+
+// Set stores value under name, visible to sessions matching sessionID (or
+// every session, if sessionID is "*"), until ttl elapses. A zero or
+// negative ttl stores a secret that is immediately expired.
+func (s *Store) Set(sessionID, name, value string, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeExpiredLocked()
+	s.entries = append(s.entries, &entry{
+		sessionID: sessionID,
+		name:      name,
+		value:     []byte(value),
+		expiresAt: time.Now().Add(ttl),
+	})
+}
+
+// EnvForSession returns "NAME=VALUE" environment entries for every
+// unexpired secret visible to sessionID, i.e. stored under sessionID or
+// under "*".
+func (s *Store) EnvForSession(sessionID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeExpiredLocked()
+
+	var env []string
+	for _, e := range s.entries {
+		if e.sessionID != sessionID && e.sessionID != "*" {
+			continue
+		}
+		env = append(env, e.name+"="+string(e.value))
+	}
+	return env
+}
+
+// purgeExpiredLocked removes and zeroes every entry whose TTL has elapsed.
+// Callers must hold s.mu.
+func (s *Store) purgeExpiredLocked() {
+	now := time.Now()
+	live := s.entries[:0]
+	for _, e := range s.entries {
+		if now.After(e.expiresAt) {
+			zero(e.value)
+			continue
+		}
+		live = append(live, e)
+	}
+	s.entries = live
+}
+
+// zero overwrites b in place so an expired secret's bytes don't linger on
+// the heap.
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
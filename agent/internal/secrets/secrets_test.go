@@ -0,0 +1,44 @@
+package secrets
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEnvForSessionReturnsMatchingSecret(t *testing.T) {
+	s := NewStore()
+	s.Set("sess-1", "API_KEY", "topsecret", time.Minute)
+
+	env := s.EnvForSession("sess-1")
+	if len(env) != 1 || env[0] != "API_KEY=topsecret" {
+		t.Fatalf("EnvForSession() = %v, want [API_KEY=topsecret]", env)
+	}
+}
+
+func TestEnvForSessionIncludesWildcardSecrets(t *testing.T) {
+	s := NewStore()
+	s.Set("*", "GLOBAL_TOKEN", "shared", time.Minute)
+
+	env := s.EnvForSession("any-session")
+	if len(env) != 1 || env[0] != "GLOBAL_TOKEN=shared" {
+		t.Fatalf("EnvForSession() = %v, want [GLOBAL_TOKEN=shared]", env)
+	}
+}
+
+func TestEnvForSessionExcludesOtherSessions(t *testing.T) {
+	s := NewStore()
+	s.Set("sess-1", "API_KEY", "topsecret", time.Minute)
+
+	if env := s.EnvForSession("sess-2"); len(env) != 0 {
+		t.Fatalf("EnvForSession() = %v, want empty", env)
+	}
+}
+
+func TestEnvForSessionOmitsExpiredSecret(t *testing.T) {
+	s := NewStore()
+	s.Set("sess-1", "API_KEY", "topsecret", -time.Second)
+
+	if env := s.EnvForSession("sess-1"); len(env) != 0 {
+		t.Fatalf("EnvForSession() = %v, want empty after expiry", env)
+	}
+}
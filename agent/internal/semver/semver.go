@@ -0,0 +1,132 @@
+// Package semver parses and compares the semantic version strings used to
+// decide whether an agent auto-update is actually newer than the running
+// build.
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed semantic version, e.g. "1.2.3-rc.1" ->
+// {Major: 1, Minor: 2, Patch: 3, Pre: "rc.1"}. Build metadata (a "+..."
+// suffix) is not supported, since the agent's own versioning never uses it.
+type Version struct {
+	Major, Minor, Patch int
+	Pre                 string
+}
+
+// Parse parses s as a semantic version of the form "MAJOR.MINOR.PATCH" or
+// "MAJOR.MINOR.PATCH-PRERELEASE", with an optional leading "v".
+func Parse(s string) (Version, error) {
+	orig := s
+	s = strings.TrimPrefix(s, "v")
+
+	var pre string
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		pre = s[i+1:]
+		if pre == "" {
+			return Version{}, fmt.Errorf("semver: invalid version %q", orig)
+		}
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("semver: invalid version %q", orig)
+	}
+
+	nums := make([]int, 3)
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("semver: invalid version %q", orig)
+		}
+		nums[i] = n
+	}
+
+	return Version{Major: nums[0], Minor: nums[1], Patch: nums[2], Pre: pre}, nil
+}
+
+// String formats v back into semantic version form.
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// LessThan reports whether v precedes other in semantic version precedence.
+// A pre-release version is always less than its normal counterpart (e.g.
+// "1.0.0-rc.1" < "1.0.0"); between two pre-releases, identifiers are
+// compared as dot-separated fields per semver's precedence rules, with
+// numeric fields compared numerically and everything else lexically.
+func (v Version) LessThan(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	if v.Patch != other.Patch {
+		return v.Patch < other.Patch
+	}
+	if v.Pre == other.Pre {
+		return false
+	}
+	if v.Pre == "" {
+		return false // v is a normal release, other is a pre-release: v is greater
+	}
+	if other.Pre == "" {
+		return true // v is a pre-release, other is a normal release: v is lesser
+	}
+	return comparePre(v.Pre, other.Pre) < 0
+}
+
+// comparePre compares two pre-release strings field by field, returning
+// -1, 0, or 1.
+func comparePre(a, b string) int {
+	aFields := strings.Split(a, ".")
+	bFields := strings.Split(b, ".")
+
+	for i := 0; i < len(aFields) && i < len(bFields); i++ {
+		if c := compareField(aFields[i], bFields[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(aFields) < len(bFields):
+		return -1
+	case len(aFields) > len(bFields):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareField compares a single dot-separated pre-release field,
+// numerically if both sides parse as integers, lexically otherwise (per
+// semver, numeric identifiers always sort before alphanumeric ones).
+func compareField(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		switch {
+		case an < bn:
+			return -1
+		case an > bn:
+			return 1
+		default:
+			return 0
+		}
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
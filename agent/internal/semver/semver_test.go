@@ -0,0 +1,99 @@
+package semver
+
+import "testing"
+
+func TestParseValid(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+	}{
+		{"1.2.3", Version{1, 2, 3, ""}},
+		{"v1.2.3", Version{1, 2, 3, ""}},
+		{"0.1.0", Version{0, 1, 0, ""}},
+		{"1.0.0-rc.1", Version{1, 0, 0, "rc.1"}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []string{"", "1.2", "1.2.3.4", "a.b.c", "1.2.-1", "1.2.3-"}
+	for _, in := range tests {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q): expected an error, got nil", in)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	if got := (Version{1, 2, 3, ""}).String(); got != "1.2.3" {
+		t.Errorf("String() = %q, want 1.2.3", got)
+	}
+	if got := (Version{1, 0, 0, "rc.1"}).String(); got != "1.0.0-rc.1" {
+		t.Errorf("String() = %q, want 1.0.0-rc.1", got)
+	}
+}
+
+func TestLessThanMajorMinorPatch(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"1.0.0", "2.0.0", true},
+		{"2.0.0", "1.0.0", false},
+		{"1.1.0", "1.2.0", true},
+		{"1.2.0", "1.1.0", false},
+		{"1.2.3", "1.2.4", true},
+		{"1.2.4", "1.2.3", false},
+		{"1.2.3", "1.2.3", false},
+	}
+	for _, tt := range tests {
+		a, err := Parse(tt.a)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.a, err)
+		}
+		b, err := Parse(tt.b)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.b, err)
+		}
+		if got := a.LessThan(b); got != tt.want {
+			t.Errorf("(%q).LessThan(%q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestLessThanPrereleaseOrdering(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"1.0.0-rc.1", "1.0.0", true},
+		{"1.0.0", "1.0.0-rc.1", false},
+		{"1.0.0-alpha", "1.0.0-alpha.1", true},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", true},
+		{"1.0.0-alpha.beta", "1.0.0-beta", true},
+		{"1.0.0-rc.1", "1.0.0-rc.2", true},
+		{"1.0.0-rc.2", "1.0.0-rc.1", false},
+	}
+	for _, tt := range tests {
+		a, err := Parse(tt.a)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.a, err)
+		}
+		b, err := Parse(tt.b)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.b, err)
+		}
+		if got := a.LessThan(b); got != tt.want {
+			t.Errorf("(%q).LessThan(%q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
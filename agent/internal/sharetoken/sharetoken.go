@@ -0,0 +1,78 @@
+// Package sharetoken issues and redeems time-limited tokens that let a
+// terminal session be shared with another viewer without exposing the
+// underlying session ID directly (MsgTerminalShareRequest/MsgTerminalJoin).
+package sharetoken
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// tokenBytes is the amount of randomness backing each generated token.
+const tokenBytes = 32
+
+// ErrTokenInvalid is returned by Redeem when token is unknown, expired, or
+// (for a single-use token) already redeemed.
+var ErrTokenInvalid = errors.New("sharetoken: token not found, expired, or already used")
+
+type entry struct {
+	sessionID string
+	expiresAt time.Time
+	multiUse  bool
+}
+
+// Store issues and redeems share tokens. The zero value is not usable;
+// call NewStore.
+type Store struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// NewStore returns an empty Store.
+func NewStore() *Store {
+	return &Store{entries: make(map[string]*entry)}
+}
+
+// Issue generates a cryptographically random token granting access to
+// sessionID for ttl, and returns the token and its expiry. If multiUse is
+// false, the token is consumed by its first Redeem.
+func (s *Store) Issue(sessionID string, ttl time.Duration, multiUse bool) (token string, expiresAt time.Time, err error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, fmt.Errorf("sharetoken: generate token: %w", err)
+	}
+	token = hex.EncodeToString(buf)
+	expiresAt = time.Now().Add(ttl)
+
+	s.mu.Lock()
+	s.entries[token] = &entry{sessionID: sessionID, expiresAt: expiresAt, multiUse: multiUse}
+	s.mu.Unlock()
+
+	return token, expiresAt, nil
+}
+
+// Redeem validates token and returns the session ID it grants access to.
+// A single-use token is removed from the Store on this call, so a second
+// Redeem of the same token returns ErrTokenInvalid; a multi-use token may
+// be redeemed repeatedly until it expires.
+func (s *Store) Redeem(token string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[token]
+	if !ok {
+		return "", ErrTokenInvalid
+	}
+	if time.Now().After(e.expiresAt) {
+		delete(s.entries, token)
+		return "", ErrTokenInvalid
+	}
+	if !e.multiUse {
+		delete(s.entries, token)
+	}
+	return e.sessionID, nil
+}
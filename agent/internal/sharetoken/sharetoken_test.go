@@ -0,0 +1,71 @@
+package sharetoken
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRedeemReturnsIssuedSessionID(t *testing.T) {
+	s := NewStore()
+	token, _, err := s.Issue("sess-1", time.Minute, false)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	sessionID, err := s.Redeem(token)
+	if err != nil {
+		t.Fatalf("Redeem: %v", err)
+	}
+	if sessionID != "sess-1" {
+		t.Errorf("Redeem returned %q, want sess-1", sessionID)
+	}
+}
+
+func TestSingleUseTokenRejectedOnSecondRedeem(t *testing.T) {
+	s := NewStore()
+	token, _, err := s.Issue("sess-1", time.Minute, false)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	if _, err := s.Redeem(token); err != nil {
+		t.Fatalf("first Redeem: %v", err)
+	}
+	if _, err := s.Redeem(token); !errors.Is(err, ErrTokenInvalid) {
+		t.Fatalf("second Redeem = %v, want ErrTokenInvalid", err)
+	}
+}
+
+func TestMultiUseTokenAcceptedRepeatedly(t *testing.T) {
+	s := NewStore()
+	token, _, err := s.Issue("sess-1", time.Minute, true)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := s.Redeem(token); err != nil {
+			t.Fatalf("Redeem #%d: %v", i, err)
+		}
+	}
+}
+
+func TestExpiredTokenRejected(t *testing.T) {
+	s := NewStore()
+	token, _, err := s.Issue("sess-1", -time.Second, false)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	if _, err := s.Redeem(token); !errors.Is(err, ErrTokenInvalid) {
+		t.Fatalf("Redeem = %v, want ErrTokenInvalid", err)
+	}
+}
+
+func TestRedeemUnknownTokenRejected(t *testing.T) {
+	s := NewStore()
+	if _, err := s.Redeem("does-not-exist"); !errors.Is(err, ErrTokenInvalid) {
+		t.Fatalf("Redeem = %v, want ErrTokenInvalid", err)
+	}
+}
@@ -0,0 +1,44 @@
+// Package syslog tails the host's syslog file and streams parsed entries
+// to the Console for an active MsgSyslogSubscribe subscription.
+package syslog
+
+import "time"
+
+// Entry is a single parsed syslog line, independent of whether it arrived
+// in RFC 3164 or RFC 5424 format.
+type Entry struct {
+	Timestamp time.Time
+	Facility  string
+	Severity  string
+	Host      string
+	Program   string
+	Message   string
+}
+
+// facilityNames maps a syslog facility code (0-23) to its conventional
+// name, per RFC 5424 section 6.2.1.
+var facilityNames = [...]string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp", "ntp", "security", "console",
+	"solaris-cron", "local0", "local1", "local2", "local3", "local4",
+	"local5", "local6", "local7",
+}
+
+// severityNames maps a syslog severity code (0-7) to its conventional
+// name, per RFC 5424 section 6.2.1.
+var severityNames = [...]string{
+	"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug",
+}
+
+// decodePriority splits a syslog PRI value into its facility and severity
+// names. An out-of-range value yields empty strings for the invalid part.
+func decodePriority(pri int) (facility, severity string) {
+	f, s := pri/8, pri%8
+	if f >= 0 && f < len(facilityNames) {
+		facility = facilityNames[f]
+	}
+	if s >= 0 && s < len(severityNames) {
+		severity = severityNames[s]
+	}
+	return facility, severity
+}
@@ -0,0 +1,79 @@
+package syslog
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rfc3164Pattern matches the BSD syslog format from RFC 3164:
+// "<PRI>Mmm dd hh:mm:ss HOSTNAME TAG: MSG".
+var rfc3164Pattern = regexp.MustCompile(`^<(\d+)>([A-Z][a-z]{2}\s+\d{1,2}\s\d{2}:\d{2}:\d{2})\s(\S+)\s([^:]+):\s?(.*)$`)
+
+// rfc5424Pattern matches the structured syslog format from RFC 5424:
+// "<PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG". This
+// only handles a "-" (nil) STRUCTURED-DATA value, not a bracketed
+// SD-ELEMENT, which is uncommon enough in practice to defer.
+var rfc5424Pattern = regexp.MustCompile(`^<(\d+)>(\d+)\s(\S+)\s(\S+)\s(\S+)\s(\S+)\s(\S+)\s(\S+)\s(.*)$`)
+
+// ParseLine parses a single syslog line in either RFC 3164 or RFC 5424
+// format.
+func ParseLine(line string) (Entry, error) {
+	if m := rfc5424Pattern.FindStringSubmatch(line); m != nil {
+		return parseRFC5424(m)
+	}
+	if m := rfc3164Pattern.FindStringSubmatch(line); m != nil {
+		return parseRFC3164(m)
+	}
+	return Entry{}, fmt.Errorf("syslog: unrecognized line format: %q", line)
+}
+
+func parseRFC3164(m []string) (Entry, error) {
+	pri, err := strconv.Atoi(m[1])
+	if err != nil {
+		return Entry{}, fmt.Errorf("syslog: parse priority: %w", err)
+	}
+	facility, severity := decodePriority(pri)
+
+	// RFC 3164 timestamps carry no year; assume the current one, which
+	// is wrong only in the narrow window of parsing a year-old backlog
+	// straddling a New Year's rollover.
+	ts, err := time.Parse("Jan _2 15:04:05", m[2])
+	if err != nil {
+		return Entry{}, fmt.Errorf("syslog: parse timestamp: %w", err)
+	}
+	ts = ts.AddDate(time.Now().Year(), 0, 0)
+
+	return Entry{
+		Timestamp: ts,
+		Facility:  facility,
+		Severity:  severity,
+		Host:      m[3],
+		Program:   strings.TrimSpace(m[4]),
+		Message:   m[5],
+	}, nil
+}
+
+func parseRFC5424(m []string) (Entry, error) {
+	pri, err := strconv.Atoi(m[1])
+	if err != nil {
+		return Entry{}, fmt.Errorf("syslog: parse priority: %w", err)
+	}
+	facility, severity := decodePriority(pri)
+
+	ts, err := time.Parse(time.RFC3339Nano, m[3])
+	if err != nil {
+		return Entry{}, fmt.Errorf("syslog: parse timestamp: %w", err)
+	}
+
+	return Entry{
+		Timestamp: ts,
+		Facility:  facility,
+		Severity:  severity,
+		Host:      m[4],
+		Program:   m[5],
+		Message:   m[9],
+	}, nil
+}
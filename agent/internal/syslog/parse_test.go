@@ -0,0 +1,95 @@
+package syslog
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestParseLineRFC3164(t *testing.T) {
+	entry, err := ParseLine("<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8")
+	if err != nil {
+		t.Fatalf("ParseLine: %v", err)
+	}
+	if entry.Facility != "auth" || entry.Severity != "crit" {
+		t.Errorf("Facility/Severity = %s/%s, want auth/crit", entry.Facility, entry.Severity)
+	}
+	if entry.Host != "mymachine" {
+		t.Errorf("Host = %q, want mymachine", entry.Host)
+	}
+	if entry.Program != "su" {
+		t.Errorf("Program = %q, want su", entry.Program)
+	}
+	if entry.Message != "'su root' failed for lonvick on /dev/pts/8" {
+		t.Errorf("Message = %q", entry.Message)
+	}
+	if entry.Timestamp.Month() != time.October || entry.Timestamp.Day() != 11 {
+		t.Errorf("Timestamp = %v, want October 11", entry.Timestamp)
+	}
+}
+
+func TestParseLineRFC5424(t *testing.T) {
+	entry, err := ParseLine(`<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - 'su root' failed for lonvick`)
+	if err != nil {
+		t.Fatalf("ParseLine: %v", err)
+	}
+	if entry.Facility != "local4" || entry.Severity != "notice" {
+		t.Errorf("Facility/Severity = %s/%s, want local4/notice", entry.Facility, entry.Severity)
+	}
+	if entry.Host != "mymachine.example.com" {
+		t.Errorf("Host = %q, want mymachine.example.com", entry.Host)
+	}
+	if entry.Program != "evntslog" {
+		t.Errorf("Program = %q, want evntslog", entry.Program)
+	}
+	wantTS := time.Date(2003, time.October, 11, 22, 14, 15, 3_000_000, time.UTC)
+	if !entry.Timestamp.Equal(wantTS) {
+		t.Errorf("Timestamp = %v, want %v", entry.Timestamp, wantTS)
+	}
+}
+
+func TestParseLineUnrecognizedFormat(t *testing.T) {
+	if _, err := ParseLine("not a syslog line"); err == nil {
+		t.Fatal("expected an error for an unrecognized line format")
+	}
+}
+
+func TestProcessLinesFiltersByFacilityAndSeverity(t *testing.T) {
+	log := strings.Join([]string{
+		"<34>Oct 11 22:14:15 mymachine su: auth message",       // facility=auth severity=crit
+		"<78>Oct 11 22:14:16 mymachine cron: cron message",     // facility=cron severity=info
+		"<38>Oct 11 22:14:17 mymachine su: another auth message", // facility=auth severity=info
+	}, "\n") + "\n"
+
+	entries := ProcessLines(strings.NewReader(log), protocol.MsgSyslogSubscribe{SubscriptionID: "sub-1", Facility: "auth"}, nil)
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	for _, e := range entries {
+		if e.Facility != "auth" {
+			t.Errorf("entry facility = %q, want auth", e.Facility)
+		}
+		if e.SubscriptionID != "sub-1" {
+			t.Errorf("entry SubscriptionID = %q, want sub-1", e.SubscriptionID)
+		}
+	}
+}
+
+func TestProcessLinesSkipsUnparseableLines(t *testing.T) {
+	log := "garbage line\n<34>Oct 11 22:14:15 mymachine su: auth message\n"
+	entries := ProcessLines(strings.NewReader(log), protocol.MsgSyslogSubscribe{}, nil)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+}
+
+func TestProcessLinesAppliesRateLimit(t *testing.T) {
+	log := strings.Repeat("<34>Oct 11 22:14:15 mymachine su: auth message\n", 5)
+	limiter := NewRateLimiter(2)
+	entries := ProcessLines(strings.NewReader(log), protocol.MsgSyslogSubscribe{}, limiter)
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2 (rate limited)", len(entries))
+	}
+}
@@ -0,0 +1,51 @@
+package syslog
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// EntrySender delivers a MsgSyslogEntry for an active subscription, e.g. by
+// writing it to the active transport.
+type EntrySender interface {
+	SendSyslogEntry(entry protocol.MsgSyslogEntry) error
+}
+
+// ProcessLines reads every line from r, parses it, and returns the
+// MsgSyslogEntry for each line that matches sub's Facility/Severity filter
+// and is admitted by limiter. Unparseable lines are skipped rather than
+// treated as an error, since a single malformed line shouldn't interrupt
+// the stream. A nil limiter applies no rate limiting.
+func ProcessLines(r io.Reader, sub protocol.MsgSyslogSubscribe, limiter *RateLimiter) []protocol.MsgSyslogEntry {
+	var out []protocol.MsgSyslogEntry
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		entry, err := ParseLine(scanner.Text())
+		if err != nil {
+			continue
+		}
+		if sub.Facility != "" && entry.Facility != sub.Facility {
+			continue
+		}
+		if sub.Severity != "" && entry.Severity != sub.Severity {
+			continue
+		}
+		if limiter != nil && !limiter.Allow() {
+			continue
+		}
+
+		out = append(out, protocol.MsgSyslogEntry{
+			SubscriptionID: sub.SubscriptionID,
+			Timestamp:      entry.Timestamp,
+			Facility:       entry.Facility,
+			Severity:       entry.Severity,
+			Host:           entry.Host,
+			Program:        entry.Program,
+			Message:        entry.Message,
+		})
+	}
+	return out
+}
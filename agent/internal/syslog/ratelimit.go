@@ -0,0 +1,47 @@
+package syslog
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter caps entries forwarded to the Console at a maximum rate (see
+// SINDRI_AGENT_SYSLOG_RATE_LIMIT), protecting the Console from being
+// flooded by a noisy log source.
+type RateLimiter struct {
+	mu          sync.Mutex
+	perSecond   int
+	windowStart time.Time
+	windowCount int
+
+	// now is overridable in tests for deterministic window behavior.
+	now func() time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing up to perSecond entries
+// per one-second window. A perSecond of 0 or less disables rate limiting.
+func NewRateLimiter(perSecond int) *RateLimiter {
+	return &RateLimiter{perSecond: perSecond, now: time.Now}
+}
+
+// Allow reports whether another entry may be forwarded in the current
+// one-second window, incrementing the window's count if so.
+func (r *RateLimiter) Allow() bool {
+	if r.perSecond <= 0 {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if now.Sub(r.windowStart) >= time.Second {
+		r.windowStart = now
+		r.windowCount = 0
+	}
+	if r.windowCount >= r.perSecond {
+		return false
+	}
+	r.windowCount++
+	return true
+}
@@ -0,0 +1,89 @@
+package syslog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// defaultSyslogPaths lists candidate syslog file locations, tried in
+// order, since the path differs between Debian-family (/var/log/syslog)
+// and RHEL-family (/var/log/messages) distributions.
+var defaultSyslogPaths = []string{"/var/log/syslog", "/var/log/messages"}
+
+// pollInterval bounds how often Tail checks the syslog file for new
+// content.
+const pollInterval = 500 * time.Millisecond
+
+// Tail follows the host's syslog file from its current end, sending each
+// new entry matching sub to sender until stop is closed. It returns an
+// error if no syslog file could be opened at all, or if seeking to its end
+// or reading from it fails; it returns nil once stop is closed.
+func Tail(sub protocol.MsgSyslogSubscribe, limiter *RateLimiter, sender EntrySender, stop <-chan struct{}) error {
+	var f *os.File
+	var err error
+	for _, path := range defaultSyslogPaths {
+		f, err = os.Open(path)
+		if err == nil {
+			break
+		}
+	}
+	if f == nil {
+		return fmt.Errorf("syslog: no syslog file found: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		return fmt.Errorf("syslog: seek to end: %w", err)
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var carry []byte
+	buf := make([]byte, 4096)
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+			n, readErr := f.Read(buf)
+			if n > 0 {
+				carry = append(carry, buf[:n]...)
+				lines := bytes.Split(carry, []byte("\n"))
+				carry = lines[len(lines)-1]
+				for _, line := range lines[:len(lines)-1] {
+					entry, parseErr := ParseLine(string(line))
+					if parseErr != nil {
+						continue
+					}
+					if sub.Facility != "" && entry.Facility != sub.Facility {
+						continue
+					}
+					if sub.Severity != "" && entry.Severity != sub.Severity {
+						continue
+					}
+					if limiter != nil && !limiter.Allow() {
+						continue
+					}
+					_ = sender.SendSyslogEntry(protocol.MsgSyslogEntry{
+						SubscriptionID: sub.SubscriptionID,
+						Timestamp:      entry.Timestamp,
+						Facility:       entry.Facility,
+						Severity:       entry.Severity,
+						Host:           entry.Host,
+						Program:        entry.Program,
+						Message:        entry.Message,
+					})
+				}
+			}
+			if readErr != nil && readErr != io.EOF {
+				return fmt.Errorf("syslog: read: %w", readErr)
+			}
+		}
+	}
+}
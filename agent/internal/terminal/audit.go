@@ -0,0 +1,138 @@
+package terminal
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"iter"
+	"log"
+	"sync"
+	"time"
+)
+
+// auditRecordHeaderLen is the fixed-size portion of a serialized
+// AuditRecord: 8 bytes timestamp, 1 byte direction, 2 bytes session ID
+// length, 4 bytes data length.
+const auditRecordHeaderLen = 8 + 1 + 2 + 4
+
+// AuditDirection distinguishes input (Console to session) from output
+// (session to Console) in an AuditRecord.
+type AuditDirection byte
+
+const (
+	AuditDirectionInput  AuditDirection = 'I'
+	AuditDirectionOutput AuditDirection = 'O'
+)
+
+// AuditRecord is a single logged input or output event, as written by the
+// audit log configured via WithAuditLog and read back by AuditLogParser.
+type AuditRecord struct {
+	Timestamp time.Time
+	SessionID string
+	Direction AuditDirection
+	Data      []byte
+}
+
+// auditLog serializes AuditRecords to an underlying io.Writer for
+// compliance, in a fixed-size-header binary format read back by
+// AuditLogParser. It is safe for concurrent use by multiple sessions'
+// forwarding goroutines plus Manager.WriteInput.
+type auditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// record appends a single AuditRecord to the log.
+func (a *auditLog) record(sessionID string, dir AuditDirection, data []byte) error {
+	header := make([]byte, auditRecordHeaderLen)
+	binary.BigEndian.PutUint64(header[0:8], uint64(time.Now().UnixNano()))
+	header[8] = byte(dir)
+	binary.BigEndian.PutUint16(header[9:11], uint16(len(sessionID)))
+	binary.BigEndian.PutUint32(header[11:15], uint32(len(data)))
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.w.Write(header); err != nil {
+		return fmt.Errorf("terminal: write audit record: %w", err)
+	}
+	if _, err := a.w.Write([]byte(sessionID)); err != nil {
+		return fmt.Errorf("terminal: write audit record: %w", err)
+	}
+	if _, err := a.w.Write(data); err != nil {
+		return fmt.Errorf("terminal: write audit record: %w", err)
+	}
+	return nil
+}
+
+// auditingSender wraps an OutputSender so every chunk it delivers is first
+// appended to the audit log. A failure to write the audit record is
+// logged and does not prevent the chunk from still being forwarded —
+// compliance logging must never be the reason a session's output stalls.
+type auditingSender struct {
+	log  *auditLog
+	next OutputSender
+}
+
+func (s *auditingSender) SendOutput(sessionID string, data []byte) error {
+	if err := s.log.record(sessionID, AuditDirectionOutput, data); err != nil {
+		log.Printf("terminal: audit log: %v", err)
+	}
+	return s.next.SendOutput(sessionID, data)
+}
+
+// WithAuditLog makes the Manager append every byte of session input and
+// output to w, tagged with a timestamp, session ID, and direction, for
+// compliance auditing. Writes to w are serialized with a mutex, so w need
+// not be safe for concurrent use on its own. Use AuditLogParser to read
+// the resulting log back.
+func WithAuditLog(w io.Writer) ManagerOption {
+	return func(m *Manager) { m.audit = &auditLog{w: w} }
+}
+
+// WrapOutputSender returns sender wrapped so its output is also appended
+// to the Manager's configured audit log (see WithAuditLog), or sender
+// unchanged if no audit log is configured. Callers should wrap a
+// session's sender with this before passing it to Session.StartForwarding.
+func (m *Manager) WrapOutputSender(sender OutputSender) OutputSender {
+	if m.audit == nil {
+		return sender
+	}
+	return &auditingSender{log: m.audit, next: sender}
+}
+
+// AuditLogParser returns an iterator over the AuditRecords stored in r, in
+// the order they were written. Iteration stops silently at the first read
+// error, including a clean io.EOF or a truncated final record — an
+// audit-log reader has no one to report a mid-stream I/O error to.
+func AuditLogParser(r io.Reader) iter.Seq[AuditRecord] {
+	return func(yield func(AuditRecord) bool) {
+		header := make([]byte, auditRecordHeaderLen)
+		for {
+			if _, err := io.ReadFull(r, header); err != nil {
+				return
+			}
+			dir := AuditDirection(header[8])
+			idLen := binary.BigEndian.Uint16(header[9:11])
+			dataLen := binary.BigEndian.Uint32(header[11:15])
+			ts := time.Unix(0, int64(binary.BigEndian.Uint64(header[0:8])))
+
+			sessionID := make([]byte, idLen)
+			if _, err := io.ReadFull(r, sessionID); err != nil {
+				return
+			}
+			data := make([]byte, dataLen)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return
+			}
+
+			if !yield(AuditRecord{
+				Timestamp: ts,
+				SessionID: string(sessionID),
+				Direction: dir,
+				Data:      data,
+			}) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package terminal
+
+import "bytes"
+
+const bellByte = 0x07
+
+// containsBell reports whether output contains a BEL (0x07) control
+// character, indicating the shell wants to ring the terminal bell.
+func containsBell(output []byte) bool {
+	return bytes.IndexByte(output, bellByte) != -1
+}
+
+// ObserveOutput inspects a chunk of PTY output as it is forwarded to the
+// Console, invoking OnBell if it contains a bell character and feeding it
+// to the session's PasswordRedactor (see ShouldRedactInput).
+func (s *Session) ObserveOutput(chunk []byte) {
+	if s.OnBell != nil && containsBell(chunk) {
+		s.OnBell()
+	}
+	if s.passwordRedact != nil {
+		s.passwordRedact.Observe(chunk)
+	}
+}
@@ -0,0 +1,18 @@
+package terminal
+
+import "testing"
+
+func TestObserveOutputFiresOnBell(t *testing.T) {
+	var fired bool
+	s := &Session{OnBell: func() { fired = true }}
+
+	s.ObserveOutput([]byte("no bell here"))
+	if fired {
+		t.Fatal("OnBell fired without a bell character")
+	}
+
+	s.ObserveOutput([]byte("ding\x07"))
+	if !fired {
+		t.Fatal("expected OnBell to fire for output containing BEL")
+	}
+}
@@ -0,0 +1,79 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ResourceUsage reports a session's cgroup-accounted CPU and memory
+// consumption.
+type ResourceUsage struct {
+	CPUUsageMicros uint64
+	MemoryBytes    uint64
+}
+
+// cgroupDir returns the cgroup v2 directory for pid, read from
+// /proc/<pid>/cgroup.
+func cgroupDir(pid int) (string, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", fmt.Errorf("terminal: read cgroup: %w", err)
+	}
+	dir, ok := parseCgroupV2Dir(string(data))
+	if !ok {
+		return "", fmt.Errorf("terminal: no cgroup v2 entry for pid %d", pid)
+	}
+	return dir, nil
+}
+
+// parseCgroupV2Dir extracts the unified (v2) cgroup path from the contents
+// of a /proc/<pid>/cgroup file, whose v2 line has the form "0::/path".
+func parseCgroupV2Dir(contents string) (string, bool) {
+	for _, line := range strings.Split(strings.TrimSpace(contents), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) == 3 && parts[0] == "0" {
+			return "/sys/fs/cgroup" + parts[2], true
+		}
+	}
+	return "", false
+}
+
+// Usage reads the session child process's CPU and memory usage from its
+// cgroup accounting files.
+func (s *Session) Usage() (ResourceUsage, error) {
+	dir, err := cgroupDir(s.cmd.Process.Pid)
+	if err != nil {
+		return ResourceUsage{}, err
+	}
+
+	var usage ResourceUsage
+
+	cpuStat, err := os.ReadFile(dir + "/cpu.stat")
+	if err != nil {
+		return ResourceUsage{}, fmt.Errorf("terminal: read cpu.stat: %w", err)
+	}
+	for _, line := range strings.Split(string(cpuStat), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "usage_usec" {
+			v, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return ResourceUsage{}, fmt.Errorf("terminal: parse usage_usec: %w", err)
+			}
+			usage.CPUUsageMicros = v
+		}
+	}
+
+	memCurrent, err := os.ReadFile(dir + "/memory.current")
+	if err != nil {
+		return ResourceUsage{}, fmt.Errorf("terminal: read memory.current: %w", err)
+	}
+	v, err := strconv.ParseUint(strings.TrimSpace(string(memCurrent)), 10, 64)
+	if err != nil {
+		return ResourceUsage{}, fmt.Errorf("terminal: parse memory.current: %w", err)
+	}
+	usage.MemoryBytes = v
+
+	return usage, nil
+}
@@ -0,0 +1,33 @@
+package terminal
+
+import "testing"
+
+func TestParseCgroupV2Dir(t *testing.T) {
+	contents := "0::/user.slice/user-1000.slice/session.scope\n"
+	dir, ok := parseCgroupV2Dir(contents)
+	if !ok {
+		t.Fatal("expected a cgroup v2 entry to be found")
+	}
+	want := "/sys/fs/cgroup/user.slice/user-1000.slice/session.scope"
+	if dir != want {
+		t.Errorf("got %q, want %q", dir, want)
+	}
+}
+
+func TestParseCgroupV2DirHybridHierarchy(t *testing.T) {
+	// A hybrid cgroup v1+v2 mount has multiple lines; only "0:" is v2.
+	contents := "12:cpu,cpuacct:/user.slice\n0::/user.slice/session.scope\n"
+	dir, ok := parseCgroupV2Dir(contents)
+	if !ok {
+		t.Fatal("expected a cgroup v2 entry to be found")
+	}
+	if dir != "/sys/fs/cgroup/user.slice/session.scope" {
+		t.Errorf("got %q", dir)
+	}
+}
+
+func TestParseCgroupV2DirNotFound(t *testing.T) {
+	if _, ok := parseCgroupV2Dir("12:cpu,cpuacct:/user.slice\n"); ok {
+		t.Fatal("expected no cgroup v2 entry to be found in a v1-only hierarchy")
+	}
+}
@@ -0,0 +1,123 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManagerCloseSessionAcknowledgesExit(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat(1) not available on this system")
+	}
+
+	m := NewManager()
+	s, err := NewSession("close-me", "cat")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	m.Add(s)
+
+	type result struct {
+		sessionID string
+		exitCode  int
+	}
+	done := make(chan result, 1)
+	go func() {
+		msg, err := m.CloseSession("close-me")
+		if err != nil {
+			t.Errorf("CloseSession: %v", err)
+		}
+		done <- result{sessionID: msg.SessionID, exitCode: msg.ExitCode}
+	}()
+
+	select {
+	case r := <-done:
+		if r.sessionID != "close-me" {
+			t.Errorf("SessionID = %q, want close-me", r.sessionID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for CloseSession")
+	}
+
+	if _, ok := m.Get("close-me"); ok {
+		t.Error("expected session to be removed from the manager after close")
+	}
+}
+
+func TestManagerCloseSessionUnknownID(t *testing.T) {
+	m := NewManager()
+	if _, err := m.CloseSession("does-not-exist"); err == nil {
+		t.Fatal("expected an error closing an unknown session")
+	}
+}
+
+func TestSessionCloseKillsBackgroundedGrandchild(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash(1) not available on this system")
+	}
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep(1) not available on this system")
+	}
+
+	s, err := NewSession("sess-1", "bash")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	cmd := fmt.Sprintf("sleep 100 & echo $! > %s\n", pidFile)
+	if _, err := s.pty.Write([]byte(cmd)); err != nil {
+		t.Fatalf("write background command: %v", err)
+	}
+
+	var childPID int
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && childPID == 0 {
+		if data, err := os.ReadFile(pidFile); err == nil {
+			if pid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && pid > 0 {
+				childPID = pid
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if childPID == 0 {
+		t.Fatal("background sleep never reported a PID")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	deadline = time.Now().Add(200 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		if !processRunning(childPID) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("backgrounded sleep (pid %d) still running 200ms after Close", childPID)
+}
+
+// processRunning reports whether pid names a process that is still
+// actually executing. A killed process lingers as a zombie — kill(pid, 0)
+// still succeeds against it — until whichever process (its original
+// parent, or an init that inherited it) calls wait on it, which this test
+// has no control over, so a zombie counts as not running.
+func processRunning(pid int) bool {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return false
+	}
+	end := strings.LastIndex(string(data), ")")
+	if end < 0 || end+2 >= len(data) {
+		return false
+	}
+	fields := strings.Fields(string(data)[end+2:])
+	return len(fields) > 0 && fields[0] != "Z"
+}
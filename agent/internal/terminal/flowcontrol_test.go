@@ -0,0 +1,88 @@
+package terminal
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+)
+
+// totalBytes returns the number of bytes sent to sessionID so far, across
+// all recorded SendOutput calls.
+func (r *recordingSender) totalBytes(sessionID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, c := range r.calls {
+		if c.sessionID == sessionID {
+			n += len(c.data)
+		}
+	}
+	return n
+}
+
+// TestManagerFlowControlThrottlesAndFlushesSessionOutput exercises the
+// Manager/Session flow control wiring directly, bypassing the real PTY
+// forwarding goroutine (whose cooked-mode echo would make exact byte
+// counts unpredictable) since flowcontrol.Window's own throttling and
+// buffering behavior is covered by internal/flowcontrol's tests.
+func TestManagerFlowControlThrottlesAndFlushesSessionOutput(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat(1) not available on this system")
+	}
+
+	m := NewManager()
+	s, err := NewSession("sess-1", "cat")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer s.Close()
+	m.Add(s)
+
+	sender := &recordingSender{}
+	s.StartForwarding(sender, false)
+
+	if err := m.EnableFlowControl("sess-1", 1024); err != nil {
+		t.Fatalf("EnableFlowControl: %v", err)
+	}
+	if err := m.OpenFlowWindow("sess-1", 100); err != nil {
+		t.Fatalf("OpenFlowWindow: %v", err)
+	}
+
+	s.mu.RLock()
+	flowSender := s.sender
+	s.mu.RUnlock()
+
+	payload := bytes.Repeat([]byte("x"), 500)
+	if err := flowSender.SendOutput("sess-1", payload); err != nil {
+		t.Fatalf("SendOutput: %v", err)
+	}
+	if got := sender.totalBytes("sess-1"); got != 100 {
+		t.Fatalf("bytes sent after 100-byte window = %d, want 100", got)
+	}
+
+	if err := m.OpenFlowWindow("sess-1", 400); err != nil {
+		t.Fatalf("OpenFlowWindow: %v", err)
+	}
+	if got := sender.totalBytes("sess-1"); got != 500 {
+		t.Fatalf("bytes sent after opening remaining credit = %d, want 500", got)
+	}
+}
+
+func TestManagerOpenFlowWindowErrorsWhenNotEnabled(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat(1) not available on this system")
+	}
+
+	m := NewManager()
+	s, err := NewSession("sess-1", "cat")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer s.Close()
+	m.Add(s)
+	s.StartForwarding(&recordingSender{}, false)
+
+	if err := m.OpenFlowWindow("sess-1", 100); err == nil {
+		t.Fatal("OpenFlowWindow before EnableFlowControl = nil error, want error")
+	}
+}
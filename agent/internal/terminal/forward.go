@@ -0,0 +1,132 @@
+package terminal
+
+import (
+	"fmt"
+
+	"github.com/pacphi/sindri/agent/internal/flowcontrol"
+)
+
+// OutputSender delivers a chunk of PTY output tagged with the session ID it
+// belongs to, e.g. by wrapping it in a MsgTerminalOutput and writing it to
+// the active transport.
+type OutputSender interface {
+	SendOutput(sessionID string, data []byte) error
+}
+
+// maxScrollback bounds the amount of buffered output retained for replay to
+// a session that takes over from another (see Manager.Takeover).
+const maxScrollback = 64 * 1024
+
+// StartForwarding begins forwarding the session's PTY output to sender as
+// it is produced, tagged with the session's current ID. If scrollback is
+// true, output is also retained (up to maxScrollback bytes) so it can be
+// replayed to a new session ID after a takeover. StartForwarding must be
+// called at most once per session, and only once the caller is ready to
+// consume PTY output — direct reads from the PTY afterwards will race with
+// the forwarding goroutine.
+func (s *Session) StartForwarding(sender OutputSender, scrollback bool) {
+	s.mu.Lock()
+	s.sender = sender
+	s.scrollbackEnabled = scrollback
+	s.mu.Unlock()
+	go s.forwardOutput()
+}
+
+func (s *Session) forwardOutput() {
+	buf := make([]byte, 4096)
+	for {
+		n, err := s.pty.Read(buf)
+		if n > 0 {
+			chunk := append([]byte(nil), buf[:n]...)
+			s.ObserveOutput(chunk)
+
+			s.mu.Lock()
+			s.bytesOut += uint64(n)
+			if s.scrollbackEnabled {
+				s.scrollback = append(s.scrollback, chunk...)
+				if len(s.scrollback) > maxScrollback {
+					s.scrollback = s.scrollback[len(s.scrollback)-maxScrollback:]
+				}
+			}
+			sender := s.sender
+			id := s.ID
+			subscribers := make(map[string]OutputSender, len(s.subscribers))
+			for subscriberID, sub := range s.subscribers {
+				subscribers[subscriberID] = sub
+			}
+			s.mu.Unlock()
+
+			if sender != nil {
+				_ = sender.SendOutput(id, chunk)
+			}
+			for subscriberID, sub := range subscribers {
+				_ = sub.SendOutput(subscriberID, chunk)
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// AddSubscriber registers sender to additionally receive this session's
+// PTY output, tagged with subscriberSessionID, alongside the primary
+// sender set by StartForwarding. Used by Manager.Subscribe to let a joined
+// session (see MsgTerminalJoin) observe another session's output without
+// taking over its input.
+func (s *Session) AddSubscriber(subscriberSessionID string, sender OutputSender) {
+	s.mu.Lock()
+	if s.subscribers == nil {
+		s.subscribers = make(map[string]OutputSender)
+	}
+	s.subscribers[subscriberSessionID] = sender
+	s.mu.Unlock()
+}
+
+// RemoveSubscriber detaches the subscriber previously registered via
+// AddSubscriber under subscriberSessionID. It is a no-op if none is
+// registered under that ID.
+func (s *Session) RemoveSubscriber(subscriberSessionID string) {
+	s.mu.Lock()
+	delete(s.subscribers, subscriberSessionID)
+	s.mu.Unlock()
+}
+
+// EnableFlowControl wraps the session's current sender in a flowcontrol.Window
+// with zero initial send credit, so subsequent output blocks (buffering up
+// to maxBufferBytes) until OpenFlowWindow grants credit. StartForwarding
+// must be called first. Calling it again replaces the previous window.
+func (s *Session) EnableFlowControl(maxBufferBytes int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w := flowcontrol.NewWindow(s.sender, maxBufferBytes)
+	s.sender = w
+	s.flow = w
+}
+
+// OpenFlowWindow grants the session's flow control window n additional
+// bytes of send credit (see EnableFlowControl), flushing any output
+// buffered while the window was exhausted. It is an error to call this
+// before EnableFlowControl.
+func (s *Session) OpenFlowWindow(n int) error {
+	s.mu.RLock()
+	w := s.flow
+	s.mu.RUnlock()
+	if w == nil {
+		return fmt.Errorf("terminal: flow control not enabled for session %q", s.ID)
+	}
+	return w.Open(n)
+}
+
+// scrollbackSnapshot returns a copy of the session's buffered scrollback,
+// or nil if scrollback is disabled or empty.
+func (s *Session) scrollbackSnapshot() []byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.scrollbackEnabled || len(s.scrollback) == 0 {
+		return nil
+	}
+	out := make([]byte, len(s.scrollback))
+	copy(out, s.scrollback)
+	return out
+}
@@ -0,0 +1,55 @@
+package terminal
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/pacphi/sindri/agent/internal/idgen"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+	"github.com/pacphi/sindri/agent/internal/redact"
+)
+
+// WriteInput writes data to the named session's PTY, unless it exceeds
+// maxBytes (a value of 0 means no limit), in which case the write is
+// refused and rejected is true — a single oversized MsgTerminalInput would
+// otherwise block the PTY write for a long time. When rejected is true,
+// the returned MsgEvent should be sent to the Console in place of
+// performing the write. If the session's PTY output recently contained a
+// password prompt (see Session.ShouldRedactInput), data is still written
+// to the PTY unchanged, but the audit log records redact.Redacted in its
+// place.
+func (m *Manager) WriteInput(id string, data []byte, maxBytes int) (event protocol.MsgEvent, rejected bool, err error) {
+	if !idgen.IsValidID(id) {
+		return protocol.MsgEvent{}, false, fmt.Errorf("terminal: invalid session id")
+	}
+	if m.suspended.Load() {
+		return protocol.MsgEvent{}, false, ErrSuspended
+	}
+
+	s, ok := m.Get(id)
+	if !ok {
+		return protocol.MsgEvent{}, false, fmt.Errorf("terminal: unknown session %q", id)
+	}
+
+	if maxBytes > 0 && len(data) > maxBytes {
+		return protocol.MsgEvent{
+			Kind:   "terminal_input_rejected",
+			Detail: fmt.Sprintf("session %s: input of %d bytes exceeds max of %d bytes", id, len(data), maxBytes),
+		}, true, nil
+	}
+
+	if m.audit != nil {
+		auditData := data
+		if s.ShouldRedactInput() {
+			auditData = []byte(redact.Redacted)
+		}
+		if err := m.audit.record(id, AuditDirectionInput, auditData); err != nil {
+			log.Printf("terminal: audit log: %v", err)
+		}
+	}
+
+	if _, err := s.WriteInput(data); err != nil {
+		return protocol.MsgEvent{}, false, fmt.Errorf("terminal: write input to session %q: %w", id, err)
+	}
+	return protocol.MsgEvent{}, false, nil
+}
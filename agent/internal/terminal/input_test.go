@@ -0,0 +1,100 @@
+package terminal
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManagerWriteInputRejectsOversizedPayload(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat(1) not available on this system")
+	}
+
+	m := NewManager()
+	s, err := NewSession("sess-1", "cat")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer s.Close()
+	m.Add(s)
+
+	sender := &recordingSender{}
+	s.StartForwarding(sender, false)
+
+	const maxBytes = 64 * 1024
+	oversized := bytes.Repeat([]byte("x"), 128*1024)
+
+	event, rejected, err := m.WriteInput("sess-1", oversized, maxBytes)
+	if err != nil {
+		t.Fatalf("WriteInput: %v", err)
+	}
+	if !rejected {
+		t.Fatal("expected oversized input to be rejected")
+	}
+	if event.Kind != "terminal_input_rejected" {
+		t.Errorf("event.Kind = %q, want terminal_input_rejected", event.Kind)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	for _, c := range sender.calls {
+		if bytes.Contains(c.data, []byte("x")) {
+			t.Fatal("expected no PTY output from a rejected input")
+		}
+	}
+
+	if _, rejected, err := m.WriteInput("sess-1", []byte("hello\n"), maxBytes); err != nil || rejected {
+		t.Fatalf("WriteInput after rejection: rejected=%v err=%v, want accepted", rejected, err)
+	}
+	sender.waitForSessionOutput(t, "sess-1", []byte("hello"))
+}
+
+func TestManagerWriteInputAcceptsWithinLimit(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat(1) not available on this system")
+	}
+
+	m := NewManager()
+	s, err := NewSession("sess-1", "cat")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer s.Close()
+	m.Add(s)
+
+	sender := &recordingSender{}
+	s.StartForwarding(sender, false)
+
+	if _, rejected, err := m.WriteInput("sess-1", []byte("hello\n"), 64*1024); err != nil || rejected {
+		t.Fatalf("WriteInput: rejected=%v err=%v, want accepted", rejected, err)
+	}
+	sender.waitForSessionOutput(t, "sess-1", []byte("hello"))
+}
+
+func TestManagerWriteInputUnknownSession(t *testing.T) {
+	m := NewManager()
+	if _, _, err := m.WriteInput("does-not-exist", []byte("x"), 64*1024); err == nil {
+		t.Fatal("expected an error for an unknown session")
+	}
+}
+
+func TestManagerWriteInputNoLimitAllowsLargePayload(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat(1) not available on this system")
+	}
+
+	m := NewManager()
+	s, err := NewSession("sess-1", "cat")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer s.Close()
+	m.Add(s)
+
+	large := []byte(strings.Repeat("y", 128*1024) + "\n")
+	if _, rejected, err := m.WriteInput("sess-1", large, 0); err != nil || rejected {
+		t.Fatalf("WriteInput: rejected=%v err=%v, want accepted with maxBytes=0", rejected, err)
+	}
+}
@@ -0,0 +1,77 @@
+package terminal
+
+import "time"
+
+// SessionInfo is a read-only snapshot of a Session's identifying and
+// lifecycle metadata, suitable for exposing to the Console without leaking
+// the underlying PTY or process handles.
+type SessionInfo struct {
+	ID        string
+	Shell     string
+	ReadOnly  bool
+	StartedAt time.Time
+}
+
+// SessionFilter narrows the sessions returned by Manager.FilteredList. All
+// fields are optional; the zero value applies no filter.
+type SessionFilter struct {
+	// Shell, if non-empty, restricts results to sessions started with
+	// this shell.
+	Shell string
+
+	// ReadOnly, if non-nil, restricts results to sessions whose ReadOnly
+	// flag matches its value.
+	ReadOnly *bool
+
+	// OlderThan, if positive, restricts results to sessions started more
+	// than this long ago.
+	OlderThan time.Duration
+
+	// YoungerThan, if positive, restricts results to sessions started
+	// less than this long ago.
+	YoungerThan time.Duration
+}
+
+// matches reports whether info satisfies every filter set on f.
+func (f SessionFilter) matches(info SessionInfo) bool {
+	if f.Shell != "" && info.Shell != f.Shell {
+		return false
+	}
+	if f.ReadOnly != nil && info.ReadOnly != *f.ReadOnly {
+		return false
+	}
+
+	age := time.Since(info.StartedAt)
+	if f.OlderThan > 0 && age < f.OlderThan {
+		return false
+	}
+	if f.YoungerThan > 0 && age > f.YoungerThan {
+		return false
+	}
+	return true
+}
+
+// List returns a snapshot of every active session, in no particular order.
+func (m *Manager) List() []SessionInfo {
+	return m.FilteredList(SessionFilter{})
+}
+
+// FilteredList returns a snapshot of active sessions matching f, in no
+// particular order.
+func (m *Manager) FilteredList(f SessionFilter) []SessionInfo {
+	m.mu.RLock()
+	sessions := make([]*Session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		sessions = append(sessions, s)
+	}
+	m.mu.RUnlock()
+
+	infos := make([]SessionInfo, 0, len(sessions))
+	for _, s := range sessions {
+		info := s.info()
+		if f.matches(info) {
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
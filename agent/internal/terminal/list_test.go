@@ -0,0 +1,66 @@
+package terminal
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestManagerFilteredList(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat(1) not available on this system")
+	}
+
+	m := NewManager()
+	var sessions []*Session
+	shells := []string{"cat", "cat", "sh", "sh", "cat"}
+	ages := []time.Duration{time.Hour, 2 * time.Hour, time.Minute, 3 * time.Hour, 30 * time.Minute}
+	readOnly := []bool{false, true, false, true, false}
+
+	for i, shell := range shells {
+		if _, err := exec.LookPath(shell); err != nil {
+			t.Skipf("%s not available on this system", shell)
+		}
+		s, err := NewSession(string(rune('a'+i)), shell)
+		if err != nil {
+			t.Fatalf("NewSession: %v", err)
+		}
+		s.StartedAt = time.Now().Add(-ages[i])
+		s.ReadOnly = readOnly[i]
+		m.Add(s)
+		sessions = append(sessions, s)
+	}
+	defer func() {
+		for _, s := range sessions {
+			s.Close()
+		}
+	}()
+
+	if got := m.List(); len(got) != len(sessions) {
+		t.Fatalf("List() returned %d sessions, want %d", len(got), len(sessions))
+	}
+	if got, want := m.FilteredList(SessionFilter{}), m.List(); len(got) != len(want) {
+		t.Errorf("FilteredList(SessionFilter{}) returned %d sessions, want %d (same as List())", len(got), len(want))
+	}
+
+	if got := m.FilteredList(SessionFilter{Shell: "cat"}); len(got) != 3 {
+		t.Errorf("Shell filter returned %d sessions, want 3", len(got))
+	}
+
+	trueVal := true
+	if got := m.FilteredList(SessionFilter{ReadOnly: &trueVal}); len(got) != 2 {
+		t.Errorf("ReadOnly filter returned %d sessions, want 2", len(got))
+	}
+
+	if got := m.FilteredList(SessionFilter{OlderThan: 90 * time.Minute}); len(got) != 2 {
+		t.Errorf("OlderThan filter returned %d sessions, want 2", len(got))
+	}
+
+	if got := m.FilteredList(SessionFilter{YoungerThan: 90 * time.Minute}); len(got) != 3 {
+		t.Errorf("YoungerThan filter returned %d sessions, want 3", len(got))
+	}
+
+	if got := m.FilteredList(SessionFilter{Shell: "cat", ReadOnly: &trueVal}); len(got) != 1 {
+		t.Errorf("combined Shell+ReadOnly filter returned %d sessions, want 1", len(got))
+	}
+}
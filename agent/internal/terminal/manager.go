@@ -0,0 +1,172 @@
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pacphi/sindri/agent/internal/idgen"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// ErrSuspended is returned by WriteInput while the Manager is suspended
+// (see Suspend), e.g. while the instance is being snapshotted or migrated.
+var ErrSuspended = errors.New("terminal: manager is suspended")
+
+// Manager tracks the set of active PTY sessions.
+type Manager struct {
+	mu        sync.RWMutex
+	sessions  map[string]*Session
+	suspended atomic.Bool
+	audit     *auditLog
+}
+
+// ManagerOption configures a Manager constructed via NewManager.
+type ManagerOption func(*Manager)
+
+// NewManager returns an empty Manager configured with opts.
+func NewManager(opts ...ManagerOption) *Manager {
+	m := &Manager{sessions: make(map[string]*Session)}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Add registers a session with the manager.
+func (m *Manager) Add(s *Session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[s.ID] = s
+}
+
+// Remove unregisters a session from the manager.
+func (m *Manager) Remove(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+}
+
+// CloseSession terminates the named session and returns a MsgTerminalClosed
+// acknowledgment carrying its exit code. It reports an error if no such
+// session is registered.
+func (m *Manager) CloseSession(id string) (protocol.MsgTerminalClosed, error) {
+	if !idgen.IsValidID(id) {
+		return protocol.MsgTerminalClosed{}, fmt.Errorf("terminal: invalid session id")
+	}
+
+	s, ok := m.Get(id)
+	if !ok {
+		return protocol.MsgTerminalClosed{}, fmt.Errorf("terminal: unknown session %q", id)
+	}
+
+	if err := s.Close(); err != nil {
+		return protocol.MsgTerminalClosed{}, fmt.Errorf("terminal: close session %q: %w", id, err)
+	}
+	exitCode := <-s.Wait()
+
+	m.Remove(id)
+	return protocol.MsgTerminalClosed{SessionID: id, ExitCode: exitCode}, nil
+}
+
+// Suspend pauses terminal I/O: subsequent WriteInput calls return
+// ErrSuspended until Resume is called. Existing sessions and their child
+// processes keep running untouched; only new input is refused.
+func (m *Manager) Suspend() {
+	m.suspended.Store(true)
+}
+
+// Resume restores terminal I/O paused by Suspend.
+func (m *Manager) Resume() {
+	m.suspended.Store(false)
+}
+
+// Get returns the session with the given ID, if any.
+func (m *Manager) Get(id string) (*Session, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.sessions[id]
+	return s, ok
+}
+
+// Subscribe attaches subscriberSessionID as an additional observer of
+// sessionID's PTY output via sender, without granting it input access —
+// used by MsgTerminalJoin after redeeming a sharetoken.Store token. It
+// returns an error if sessionID is not a known session.
+func (m *Manager) Subscribe(sessionID, subscriberSessionID string, sender OutputSender) error {
+	if !idgen.IsValidID(sessionID) || !idgen.IsValidID(subscriberSessionID) {
+		return fmt.Errorf("terminal: invalid session id")
+	}
+
+	s, ok := m.Get(sessionID)
+	if !ok {
+		return fmt.Errorf("terminal: unknown session %q", sessionID)
+	}
+	s.AddSubscriber(subscriberSessionID, sender)
+	return nil
+}
+
+// Unsubscribe detaches subscriberSessionID from sessionID's output, added
+// by an earlier Subscribe call. It is a no-op if either ID is unknown.
+func (m *Manager) Unsubscribe(sessionID, subscriberSessionID string) {
+	if s, ok := m.Get(sessionID); ok {
+		s.RemoveSubscriber(subscriberSessionID)
+	}
+}
+
+// EnableFlowControl turns on send-credit throttling for sessionID's output
+// (see Session.EnableFlowControl), bounding how much unread output the
+// agent can have in flight for a slow Console. It returns an error if
+// sessionID is not a known session.
+func (m *Manager) EnableFlowControl(sessionID string, maxBufferBytes int) error {
+	if !idgen.IsValidID(sessionID) {
+		return fmt.Errorf("terminal: invalid session id")
+	}
+	s, ok := m.Get(sessionID)
+	if !ok {
+		return fmt.Errorf("terminal: unknown session %q", sessionID)
+	}
+	s.EnableFlowControl(maxBufferBytes)
+	return nil
+}
+
+// OpenFlowWindow grants sessionID's flow control window n additional bytes
+// of send credit, from a MsgFlowControl. It returns an error if sessionID
+// is not a known session, or if EnableFlowControl was not called for it.
+func (m *Manager) OpenFlowWindow(sessionID string, n int) error {
+	if !idgen.IsValidID(sessionID) {
+		return fmt.Errorf("terminal: invalid session id")
+	}
+	s, ok := m.Get(sessionID)
+	if !ok {
+		return fmt.Errorf("terminal: unknown session %q", sessionID)
+	}
+	return s.OpenFlowWindow(n)
+}
+
+// Broadcast writes data to every active session's PTY, collecting and
+// returning any per-session write errors as a single joined error.
+func (m *Manager) Broadcast(data []byte) error {
+	m.mu.RLock()
+	sessions := make([]*Session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		sessions = append(sessions, s)
+	}
+	m.mu.RUnlock()
+
+	var errs []error
+	for _, s := range sessions {
+		if _, err := s.pty.Write(data); err != nil {
+			errs = append(errs, fmt.Errorf("session %s: %w", s.ID, err))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	joined := errs[0]
+	for _, e := range errs[1:] {
+		joined = fmt.Errorf("%w; %w", joined, e)
+	}
+	return joined
+}
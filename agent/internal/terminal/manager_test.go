@@ -0,0 +1,48 @@
+package terminal
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestManagerBroadcastWritesToAllSessions(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat(1) not available on this system")
+	}
+
+	m := NewManager()
+	var sessions []*Session
+	for i := 0; i < 3; i++ {
+		s, err := NewSession(string(rune('a'+i)), "cat")
+		if err != nil {
+			t.Fatalf("NewSession: %v", err)
+		}
+		m.Add(s)
+		sessions = append(sessions, s)
+	}
+	defer func() {
+		for _, s := range sessions {
+			s.Close()
+		}
+	}()
+
+	if err := m.Broadcast([]byte("hello\n")); err != nil {
+		t.Fatalf("Broadcast: %v", err)
+	}
+
+	// The PTY's default termios echoes with ONLCR, translating the "\n"
+	// we wrote into "\r\n" in the echo, so the echoed line is one byte
+	// longer than what we sent.
+	buf := make([]byte, 7)
+	for _, s := range sessions {
+		s.pty.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, err := s.pty.Read(buf)
+		if err != nil {
+			t.Fatalf("read echoed broadcast: %v", err)
+		}
+		if string(buf[:n]) != "hello\r\n" {
+			t.Errorf("got %q, want %q", buf[:n], "hello\r\n")
+		}
+	}
+}
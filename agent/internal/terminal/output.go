@@ -0,0 +1,74 @@
+package terminal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// defaultCompressionThreshold is the minimum chunk size, in bytes, worth
+// paying gzip's per-message overhead for when threshold is zero. Matches
+// config.defaultCompressThreshold.
+const defaultCompressionThreshold = 4 * 1024
+
+// NewOutputMessage builds a MsgTerminalOutput for chunk, gzip-compressing
+// it when doing so is likely to pay off (chunks below threshold are sent
+// as-is; threshold <= 0 uses defaultCompressionThreshold, from
+// config.Config.CompressThreshold). If enforceUTF8 is true and chunk is
+// valid UTF-8, the message is tagged TerminalOutputEncodingUTF8 for a
+// Console that predates base64 support; invalid UTF-8 always falls back
+// to the default base64 encoding rather than risk transporting corrupt
+// text. A compressed chunk is always base64, since gzip output is
+// binary regardless of what it compresses.
+func NewOutputMessage(sessionID string, chunk []byte, enforceUTF8 bool, threshold int) (protocol.MsgTerminalOutput, error) {
+	if threshold <= 0 {
+		threshold = defaultCompressionThreshold
+	}
+	if len(chunk) < threshold {
+		msg := protocol.MsgTerminalOutput{SessionID: sessionID, Data: chunk}
+		if enforceUTF8 && utf8.Valid(chunk) {
+			msg.Encoding = protocol.TerminalOutputEncodingUTF8
+		}
+		return msg, nil
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(chunk); err != nil {
+		return protocol.MsgTerminalOutput{}, fmt.Errorf("terminal: gzip output: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return protocol.MsgTerminalOutput{}, fmt.Errorf("terminal: gzip output: %w", err)
+	}
+
+	if buf.Len() >= len(chunk) {
+		msg := protocol.MsgTerminalOutput{SessionID: sessionID, Data: chunk}
+		if enforceUTF8 && utf8.Valid(chunk) {
+			msg.Encoding = protocol.TerminalOutputEncodingUTF8
+		}
+		return msg, nil
+	}
+	return protocol.MsgTerminalOutput{SessionID: sessionID, Data: buf.Bytes(), Compressed: true}, nil
+}
+
+// DecodeOutputMessage returns the raw PTY output carried by msg,
+// decompressing it if necessary.
+func DecodeOutputMessage(msg protocol.MsgTerminalOutput) ([]byte, error) {
+	if !msg.Compressed {
+		return msg.Data, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(msg.Data))
+	if err != nil {
+		return nil, fmt.Errorf("terminal: gzip reader: %w", err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("terminal: gunzip output: %w", err)
+	}
+	return data, nil
+}
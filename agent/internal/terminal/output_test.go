@@ -0,0 +1,102 @@
+package terminal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestOutputMessageRoundTripCompressed(t *testing.T) {
+	chunk := []byte(strings.Repeat("build log line\n", 100))
+
+	msg, err := NewOutputMessage("sess-1", chunk, false, 256)
+	if err != nil {
+		t.Fatalf("NewOutputMessage: %v", err)
+	}
+	if !msg.Compressed {
+		t.Fatal("expected large repetitive chunk to be compressed")
+	}
+
+	decoded, err := DecodeOutputMessage(msg)
+	if err != nil {
+		t.Fatalf("DecodeOutputMessage: %v", err)
+	}
+	if !bytes.Equal(decoded, chunk) {
+		t.Error("decoded output does not match original chunk")
+	}
+}
+
+func TestOutputMessageSkipsCompressionForSmallChunks(t *testing.T) {
+	chunk := []byte("ok\n")
+	msg, err := NewOutputMessage("sess-1", chunk, false, 256)
+	if err != nil {
+		t.Fatalf("NewOutputMessage: %v", err)
+	}
+	if msg.Compressed {
+		t.Error("expected small chunk to be sent uncompressed")
+	}
+	if !bytes.Equal(msg.Data, chunk) {
+		t.Error("uncompressed data should match the chunk verbatim")
+	}
+}
+
+func TestNewOutputMessageUsesDefaultThresholdWhenZero(t *testing.T) {
+	small := []byte(strings.Repeat("x", defaultCompressionThreshold-1))
+	msg, err := NewOutputMessage("sess-1", small, false, 0)
+	if err != nil {
+		t.Fatalf("NewOutputMessage: %v", err)
+	}
+	if msg.Compressed {
+		t.Error("expected chunk just under the default threshold to be sent uncompressed")
+	}
+
+	large := []byte(strings.Repeat("x", defaultCompressionThreshold+1))
+	msg, err = NewOutputMessage("sess-1", large, false, 0)
+	if err != nil {
+		t.Fatalf("NewOutputMessage: %v", err)
+	}
+	if !msg.Compressed {
+		t.Error("expected chunk over the default threshold to be compressed")
+	}
+}
+
+func TestNewOutputMessageTagsUTF8EncodingWhenEnforced(t *testing.T) {
+	chunk := []byte("hello, world\n")
+	msg, err := NewOutputMessage("sess-1", chunk, true, 256)
+	if err != nil {
+		t.Fatalf("NewOutputMessage: %v", err)
+	}
+	if msg.Encoding != protocol.TerminalOutputEncodingUTF8 {
+		t.Errorf("Encoding = %q, want %q", msg.Encoding, protocol.TerminalOutputEncodingUTF8)
+	}
+	if !bytes.Equal(msg.Data, chunk) {
+		t.Error("Data should match the chunk verbatim")
+	}
+}
+
+func TestNewOutputMessageFallsBackToBase64ForInvalidUTF8(t *testing.T) {
+	chunk := []byte{0xff, 0xfe, 0x00, 0x01}
+	msg, err := NewOutputMessage("sess-1", chunk, true, 256)
+	if err != nil {
+		t.Fatalf("NewOutputMessage: %v", err)
+	}
+	if msg.Encoding == protocol.TerminalOutputEncodingUTF8 {
+		t.Error("expected invalid UTF-8 to fall back to base64 encoding, not be tagged utf8")
+	}
+	if !bytes.Equal(msg.Data, chunk) {
+		t.Error("Data should still match the chunk verbatim")
+	}
+}
+
+func TestNewOutputMessageWithoutEnforceUTF8UsesBase64(t *testing.T) {
+	chunk := []byte("hello, world\n")
+	msg, err := NewOutputMessage("sess-1", chunk, false, 256)
+	if err != nil {
+		t.Fatalf("NewOutputMessage: %v", err)
+	}
+	if msg.Encoding != "" {
+		t.Errorf("Encoding = %q, want empty when enforceUTF8 is false", msg.Encoding)
+	}
+}
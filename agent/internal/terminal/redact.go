@@ -0,0 +1,8 @@
+package terminal
+
+// ShouldRedactInput reports whether s's PTY output recently contained a
+// password prompt (see ObserveOutput), meaning the next input written to
+// it should be withheld from the audit log rather than logged verbatim.
+func (s *Session) ShouldRedactInput() bool {
+	return s.passwordRedact != nil && s.passwordRedact.ShouldRedact()
+}
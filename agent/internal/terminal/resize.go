@@ -0,0 +1,50 @@
+package terminal
+
+import (
+	"syscall"
+
+	"github.com/creack/pty"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// Resize applies the requested dimensions to the session's PTY and returns
+// a MsgTerminalResizeACK carrying the dimensions actually in effect, read
+// back from the PTY via pty.GetsizeFull — a terminal may clamp the
+// requested size, and the Console should reconcile its own state against
+// what was really applied rather than assuming the request took effect
+// verbatim. If applying the resize fails, ok is false and the returned
+// MsgTerminalResizeError should be sent to the Console instead.
+func (s *Session) Resize(cols, rows uint16) (protocol.MsgTerminalResizeACK, protocol.MsgTerminalResizeError, bool) {
+	if err := pty.Setsize(s.pty, &pty.Winsize{Cols: cols, Rows: rows}); err != nil {
+		return protocol.MsgTerminalResizeACK{}, protocol.MsgTerminalResizeError{
+			SessionID: s.ID,
+			Error:     err.Error(),
+		}, false
+	}
+
+	size, err := pty.GetsizeFull(s.pty)
+	if err != nil {
+		return protocol.MsgTerminalResizeACK{}, protocol.MsgTerminalResizeError{
+			SessionID: s.ID,
+			Error:     err.Error(),
+		}, false
+	}
+
+	s.mu.Lock()
+	s.Cols, s.Rows = size.Cols, size.Rows
+	s.mu.Unlock()
+
+	// Some terminal applications (vim, tmux) only re-read the PTY's
+	// dimensions on SIGWINCH rather than polling ioctl(TIOCGWINSZ), so
+	// the resize wouldn't otherwise take visible effect until the next
+	// unrelated signal. Ignore the error: a process that has already
+	// exited doesn't invalidate an otherwise-successful Resize.
+	_ = s.cmd.Process.Signal(syscall.SIGWINCH)
+
+	return protocol.MsgTerminalResizeACK{
+		SessionID: s.ID,
+		Cols:      size.Cols,
+		Rows:      size.Rows,
+	}, protocol.MsgTerminalResizeError{}, true
+}
@@ -0,0 +1,29 @@
+package terminal
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestSessionResizeReturnsACKWithAppliedDimensions(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat(1) not available on this system")
+	}
+
+	s, err := NewSession("resize-me", "cat")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer s.Close()
+
+	ack, resizeErr, ok := s.Resize(120, 40)
+	if !ok {
+		t.Fatalf("Resize failed: %+v", resizeErr)
+	}
+	if ack.SessionID != "resize-me" {
+		t.Errorf("SessionID = %q, want resize-me", ack.SessionID)
+	}
+	if ack.Cols != 120 || ack.Rows != 40 {
+		t.Errorf("ACK dimensions = %dx%d, want 120x40", ack.Cols, ack.Rows)
+	}
+}
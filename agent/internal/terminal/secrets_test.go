@@ -0,0 +1,77 @@
+package terminal
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/secrets"
+)
+
+func TestNewSessionInjectsSecretAsEnvVar(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh(1) not available on this system")
+	}
+
+	store := secrets.NewStore()
+	store.Set("sess-1", "SINDRI_TEST_SECRET", "topsecret", time.Minute)
+
+	s, err := NewSession("sess-1", "sh", store.EnvForSession("sess-1")...)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer s.Close()
+
+	if _, err := s.pty.Write([]byte("echo $SINDRI_TEST_SECRET\n")); err != nil {
+		t.Fatalf("write to pty: %v", err)
+	}
+
+	buf := make([]byte, 4096)
+	deadline := time.Now().Add(5 * time.Second)
+	var seen []byte
+	for time.Now().Before(deadline) {
+		s.pty.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		n, _ := s.pty.Read(buf)
+		seen = append(seen, buf[:n]...)
+		if bytes.Contains(seen, []byte("topsecret")) {
+			return
+		}
+	}
+	t.Fatalf("expected pty output to contain injected secret value, got %q", seen)
+}
+
+func TestNewSessionOmitsExpiredSecretFromEnv(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh(1) not available on this system")
+	}
+
+	store := secrets.NewStore()
+	store.Set("sess-1", "SINDRI_TEST_SECRET", "topsecret", -time.Second)
+
+	s, err := NewSession("sess-1", "sh", store.EnvForSession("sess-1")...)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer s.Close()
+
+	if _, err := s.pty.Write([]byte("echo [$SINDRI_TEST_SECRET]\n")); err != nil {
+		t.Fatalf("write to pty: %v", err)
+	}
+
+	buf := make([]byte, 4096)
+	deadline := time.Now().Add(2 * time.Second)
+	var seen []byte
+	for time.Now().Before(deadline) {
+		s.pty.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		n, _ := s.pty.Read(buf)
+		seen = append(seen, buf[:n]...)
+		if bytes.Contains(seen, []byte("[]")) {
+			return
+		}
+		if bytes.Contains(seen, []byte("topsecret")) {
+			t.Fatalf("expected expired secret to be absent from env, got %q", seen)
+		}
+	}
+	t.Fatalf("timed out waiting for shell output, got %q", seen)
+}
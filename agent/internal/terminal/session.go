@@ -0,0 +1,198 @@
+// Package terminal manages PTY-backed remote shell sessions on behalf of
+// the Console.
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/creack/pty"
+
+	"github.com/pacphi/sindri/agent/internal/flowcontrol"
+	"github.com/pacphi/sindri/agent/internal/redact"
+)
+
+// Session is a single PTY-backed shell session.
+type Session struct {
+	ID string
+
+	// Shell is the command this session's PTY was started with.
+	Shell string
+
+	// ReadOnly marks a session as accepting output forwarding but not
+	// input, e.g. when shared for another operator to observe.
+	ReadOnly bool
+
+	// EnforceUTF8 marks a session as confirmed to only ever produce valid
+	// UTF-8 output (e.g. detected via a UTF-8 BOM or a LANG=*.UTF-8
+	// locale at session start), letting NewOutputMessage tag its output
+	// TerminalOutputEncodingUTF8 for a Console that predates base64
+	// support. Defaults to false, the safe choice for a shell that might
+	// emit arbitrary binary data.
+	EnforceUTF8 bool
+
+	// StartedAt records when the session's child process was started.
+	StartedAt time.Time
+
+	// Env holds the environment the session's child process was started
+	// with, for inclusion in Snapshot.
+	Env []string
+
+	// WorkingDir is the session's working directory, for inclusion in
+	// Snapshot.
+	WorkingDir string
+
+	// Cols and Rows record the PTY's current dimensions, as last applied
+	// by Resize.
+	Cols, Rows uint16
+
+	// Annotations holds free-form operator-set metadata about the
+	// session (e.g. a reason or ticket reference), for inclusion in
+	// Snapshot.
+	Annotations map[string]string
+
+	// OnBell, if set, is invoked whenever output observed via
+	// ObserveOutput contains a BEL control character.
+	OnBell func()
+
+	// passwordRedact tracks whether output observed via ObserveOutput
+	// recently contained a password prompt, so Manager.WriteInput knows
+	// to withhold the next input from the audit log.
+	passwordRedact *redact.PasswordRedactor
+
+	// cmd and pty are set exactly once, in NewSession, and never
+	// reassigned afterwards; a session rebuilt by Manager.Restore has
+	// neither (both stay nil). Every other
+	// goroutine (forwardOutput, Resize, SendSignal, Usage, ...) only ever
+	// reads them, so they are safe to access without holding mu — there
+	// is no writer to race against post-construction. Do not add a path
+	// that mutates either field after construction without revisiting
+	// this comment.
+	cmd *exec.Cmd
+	pty *os.File
+
+	// mu guards every field below it, all of which do change over a
+	// session's lifetime. Lock ordering: a caller holding Manager.mu must
+	// never then try to acquire a Session's mu — Manager methods always
+	// release m.mu (e.g. via Get) before calling into a Session — so
+	// Session.mu is always the innermost lock.
+	mu                sync.RWMutex
+	exitCode          int
+	done              chan int
+	sender            OutputSender
+	scrollbackEnabled bool
+	scrollback        []byte
+	bytesIn           uint64
+	bytesOut          uint64
+
+	// subscribers holds additional OutputSenders that observe this
+	// session's output alongside its primary sender, keyed by the
+	// subscribing session's own ID (see Manager.Subscribe). Unlike
+	// sender, a subscriber has no input access to this session.
+	subscribers map[string]OutputSender
+
+	// flow is non-nil once EnableFlowControl has wrapped sender in a
+	// flowcontrol.Window, letting OpenFlowWindow grant it send credit.
+	flow *flowcontrol.Window
+}
+
+// NewSession starts shell as a PTY-backed child process. extraEnv, if
+// given, is appended to the child's inherited environment (e.g. secrets
+// injected via secrets.Store.EnvForSession) — a later entry overrides an
+// earlier one with the same name, matching os/exec's own precedence.
+func NewSession(id, shell string, extraEnv ...string) (*Session, error) {
+	cmd := exec.Command(shell)
+	if len(extraEnv) > 0 {
+		cmd.Env = append(os.Environ(), extraEnv...)
+	}
+	f, err := pty.Start(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("terminal: start pty: %w", err)
+	}
+
+	s := &Session{
+		ID:             id,
+		Shell:          shell,
+		StartedAt:      time.Now(),
+		cmd:            cmd,
+		pty:            f,
+		done:           make(chan int, 1),
+		passwordRedact: redact.NewPasswordRedactor(),
+	}
+	go s.wait()
+	return s, nil
+}
+
+// info returns a snapshot of s's identifying and lifecycle metadata.
+func (s *Session) info() SessionInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return SessionInfo{
+		ID:        s.ID,
+		Shell:     s.Shell,
+		ReadOnly:  s.ReadOnly,
+		StartedAt: s.StartedAt,
+	}
+}
+
+// wait blocks until the child process exits, then publishes its exit code
+// on the done channel and closes it.
+func (s *Session) wait() {
+	err := s.cmd.Wait()
+	code := 0
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			code = exitErr.ExitCode()
+		} else {
+			code = -1
+		}
+	}
+
+	s.mu.Lock()
+	s.exitCode = code
+	s.mu.Unlock()
+
+	s.done <- code
+	close(s.done)
+}
+
+// Wait returns a channel that receives the session's exit code exactly
+// once the underlying process terminates, and is then closed. Call Wait
+// once per session and retain the result; a second receive on the
+// returned channel observes a closed channel, not the exit code again.
+func (s *Session) Wait() <-chan int {
+	return s.done
+}
+
+// Close terminates every process descended from the session's shell and
+// releases its PTY. pty.Start makes the shell a session leader via Setsid,
+// so its own PID is also its session ID (see sessionID), shared for life
+// by everything it spawns — including a backgrounded job a shell with job
+// control has moved into a process group of its own. killSession is used
+// instead of signaling the shell's process group directly for that
+// reason. If the shell's session can't be read (e.g. it has already
+// exited), Close falls back to killing the shell process alone; either
+// way the PTY is closed.
+func (s *Session) Close() error {
+	pid := s.cmd.Process.Pid
+	if sid, err := sessionID(pid); err == nil {
+		killSession(sid, syscall.SIGKILL)
+	} else {
+		_ = s.cmd.Process.Kill()
+	}
+	return s.pty.Close()
+}
+
+// WriteInput writes data to the session's PTY, tracking cumulative bytes
+// written for Snapshot.
+func (s *Session) WriteInput(data []byte) (int, error) {
+	n, err := s.pty.Write(data)
+	s.mu.Lock()
+	s.bytesIn += uint64(n)
+	s.mu.Unlock()
+	return n, err
+}
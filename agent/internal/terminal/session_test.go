@@ -0,0 +1,63 @@
+package terminal
+
+import (
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSessionWaitReportsExitCode(t *testing.T) {
+	if _, err := exec.LookPath("false"); err != nil {
+		t.Skip("false(1) not available on this system")
+	}
+
+	s, err := NewSession("test-session", "false")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+
+	select {
+	case code := <-s.Wait():
+		if code != 1 {
+			t.Errorf("got exit code %d, want 1", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for session exit")
+	}
+}
+
+// TestSessionConcurrentAccessIsRaceFree exercises info(), Snapshot(), and
+// StartForwarding's own goroutine concurrently, catching any regression
+// back to unguarded reads of Session's mutable fields under `go test -race`.
+func TestSessionConcurrentAccessIsRaceFree(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat(1) not available on this system")
+	}
+
+	s, err := NewSession("race-session", "cat")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer s.Close()
+
+	s.StartForwarding(noopSender{}, true)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = s.info()
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = s.Snapshot()
+		}()
+	}
+	wg.Wait()
+}
+
+type noopSender struct{}
+
+func (noopSender) SendOutput(sessionID string, data []byte) error { return nil }
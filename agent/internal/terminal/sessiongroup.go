@@ -0,0 +1,70 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// sessionID reads the POSIX session ID of pid from /proc/<pid>/stat. Since
+// pty.Start starts every session's shell with Setsid, the shell's own PID
+// is also its session ID, and every process it (or anything it spawns)
+// forks inherits that same session ID for the life of the session — unlike
+// its process group, which a shell with job control enabled (e.g. bash
+// once it finishes initializing) can move a backgrounded job into on its
+// own. Session ID is therefore the only stable handle for "every process
+// descended from this session's shell".
+func sessionID(pid int) (int, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, fmt.Errorf("terminal: read stat: %w", err)
+	}
+	return parseStatSessionID(string(data))
+}
+
+// parseStatSessionID extracts the session field from the contents of a
+// /proc/<pid>/stat file. The second field, comm, is parenthesized and may
+// itself contain spaces or parens, so fields are counted from the last
+// ")" rather than by naively splitting on spaces.
+func parseStatSessionID(contents string) (int, error) {
+	end := strings.LastIndex(contents, ")")
+	if end < 0 || end+2 >= len(contents) {
+		return 0, fmt.Errorf("terminal: malformed stat line")
+	}
+	// Fields after comm, 1-indexed from state: state(3) ppid(4) pgrp(5)
+	// session(6) — i.e. index 3 (0-indexed) of the remainder.
+	fields := strings.Fields(contents[end+2:])
+	if len(fields) < 4 {
+		return 0, fmt.Errorf("terminal: malformed stat line")
+	}
+	return strconv.Atoi(fields[3])
+}
+
+// killSession sends sig to every process on the system whose session ID
+// (per /proc/<pid>/stat) equals sid, ignoring processes that exit or
+// disappear while it is walking /proc. It returns the number of processes
+// signaled.
+func killSession(sid int, sig syscall.Signal) int {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return 0
+	}
+
+	killed := 0
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		got, err := sessionID(pid)
+		if err != nil || got != sid {
+			continue
+		}
+		if err := syscall.Kill(pid, sig); err == nil {
+			killed++
+		}
+	}
+	return killed
+}
@@ -0,0 +1,43 @@
+package terminal
+
+import (
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestSessionIDMatchesOwnProcess(t *testing.T) {
+	pid := os.Getpid()
+	sid, err := sessionID(pid)
+	if err != nil {
+		t.Fatalf("sessionID: %v", err)
+	}
+	if sid <= 0 {
+		t.Errorf("sessionID = %d, want a positive session ID", sid)
+	}
+}
+
+func TestParseStatSessionIDHandlesParensInComm(t *testing.T) {
+	// comm can itself contain parens (e.g. a process named "a)b(c"); the
+	// parser must find the *last* ")" rather than the first.
+	line := "123 (a)b(c) S 1 456 789 0 -1 4194304 0 0 0 0 0 0 0 0 20 0 1 0"
+	sid, err := parseStatSessionID(line)
+	if err != nil {
+		t.Fatalf("parseStatSessionID: %v", err)
+	}
+	if sid != 789 {
+		t.Errorf("sid = %d, want 789", sid)
+	}
+}
+
+func TestParseStatSessionIDRejectsMalformedLine(t *testing.T) {
+	if _, err := parseStatSessionID("not a stat line"); err == nil {
+		t.Error("expected an error for a malformed stat line")
+	}
+}
+
+func TestKillSessionSignalsZeroForUnusedSessionID(t *testing.T) {
+	if killed := killSession(-1, syscall.Signal(0)); killed != 0 {
+		t.Errorf("killSession = %d, want 0 for a session ID no process has", killed)
+	}
+}
@@ -0,0 +1,53 @@
+package terminal
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/sharetoken"
+)
+
+func TestJoinViaShareTokenReceivesOutputAndTokenIsSingleUse(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat(1) not available on this system")
+	}
+
+	m := NewManager()
+	s, err := NewSession("host-session", "cat")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer s.Close()
+	m.Add(s)
+
+	owner := &recordingSender{}
+	s.StartForwarding(owner, false)
+
+	tokens := sharetoken.NewStore()
+	token, _, err := tokens.Issue("host-session", time.Minute, false)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	sessionID, err := tokens.Redeem(token)
+	if err != nil {
+		t.Fatalf("Redeem: %v", err)
+	}
+
+	viewer := &recordingSender{}
+	if err := m.Subscribe(sessionID, "viewer-session", viewer); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	if _, err := s.pty.Write([]byte("hello from host\n")); err != nil {
+		t.Fatalf("write to pty: %v", err)
+	}
+
+	owner.waitForSessionOutput(t, "host-session", []byte("hello from host"))
+	viewer.waitForSessionOutput(t, "viewer-session", []byte("hello from host"))
+
+	if _, err := tokens.Redeem(token); err == nil {
+		t.Fatal("expected second Redeem of a single-use token to fail")
+	}
+}
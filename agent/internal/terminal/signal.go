@@ -0,0 +1,48 @@
+package terminal
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/pacphi/sindri/agent/internal/idgen"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// allowedSignals lists the signals the Console is permitted to deliver to a
+// session's child process. Anything else (e.g. SIGSEGV) is rejected, since
+// there's no legitimate remote-terminal use case for it and allowing
+// arbitrary signal numbers would let a compromised Console crash or corrupt
+// agent-adjacent processes.
+var allowedSignals = map[int]bool{
+	int(syscall.SIGHUP):  true,
+	int(syscall.SIGINT):  true,
+	int(syscall.SIGKILL): true,
+	int(syscall.SIGTERM): true,
+	int(syscall.SIGTSTP): true,
+}
+
+// SendSignal delivers the OS signal identified by sig to the named
+// session's child process. It returns a MsgEvent summarizing the outcome
+// and an error if sig is not allowlisted or delivery fails.
+func (m *Manager) SendSignal(id string, sig int) (protocol.MsgEvent, error) {
+	if !allowedSignals[sig] {
+		return protocol.MsgEvent{}, fmt.Errorf("terminal: signal %d is not allowlisted", sig)
+	}
+	if !idgen.IsValidID(id) {
+		return protocol.MsgEvent{}, fmt.Errorf("terminal: invalid session id")
+	}
+
+	s, ok := m.Get(id)
+	if !ok {
+		return protocol.MsgEvent{}, fmt.Errorf("terminal: unknown session %q", id)
+	}
+
+	if err := s.cmd.Process.Signal(syscall.Signal(sig)); err != nil {
+		return protocol.MsgEvent{}, fmt.Errorf("terminal: signal session %q: %w", id, err)
+	}
+
+	return protocol.MsgEvent{
+		Kind:   "terminal_signal_sent",
+		Detail: fmt.Sprintf("delivered signal %d to session %s", sig, id),
+	}, nil
+}
@@ -0,0 +1,74 @@
+package terminal
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestManagerSendSignalRejectsUnallowlistedSignal(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep(1) not available on this system")
+	}
+
+	m := NewManager()
+	s, err := NewSession("sess-1", "sleep")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer s.Close()
+	m.Add(s)
+
+	// SIGSEGV (11) has no legitimate remote-terminal use case.
+	if _, err := m.SendSignal("sess-1", 11); err == nil {
+		t.Fatal("expected an error for a non-allowlisted signal")
+	}
+}
+
+func TestManagerSendSignalUnknownSession(t *testing.T) {
+	m := NewManager()
+	if _, err := m.SendSignal("does-not-exist", 15); err == nil {
+		t.Fatal("expected an error for an unknown session")
+	}
+}
+
+func TestManagerSendSignalTerminatesChildProcess(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh(1) not available on this system")
+	}
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep(1) not available on this system")
+	}
+
+	m := NewManager()
+	s, err := NewSession("sess-1", "sh")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	m.Add(s)
+	defer s.Close()
+
+	// exec replaces the shell's process image with "sleep 60" in place,
+	// so signaling s.cmd.Process still targets the running sleep(1).
+	if _, err := s.pty.Write([]byte("exec sleep 60\n")); err != nil {
+		t.Fatalf("write exec command: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond) // let the shell exec before signaling
+
+	event, err := m.SendSignal("sess-1", 15) // SIGTERM
+	if err != nil {
+		t.Fatalf("SendSignal: %v", err)
+	}
+	if event.Kind != "terminal_signal_sent" {
+		t.Errorf("event.Kind = %q, want terminal_signal_sent", event.Kind)
+	}
+
+	select {
+	case code := <-s.Wait():
+		if code == 0 {
+			t.Errorf("exit code = 0, want non-zero (signal exit) after SIGTERM")
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("session did not exit within 500ms of SIGTERM")
+	}
+}
@@ -0,0 +1,64 @@
+package terminal
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// readUntil reads from s's PTY until seen contains a line matching want or
+// deadline elapses, returning the accumulated output.
+func readUntil(t *testing.T, s *Session, want string, deadline time.Time) []byte {
+	t.Helper()
+
+	buf := make([]byte, 4096)
+	var seen []byte
+	for time.Now().Before(deadline) {
+		s.pty.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		n, _ := s.pty.Read(buf)
+		seen = append(seen, buf[:n]...)
+		if bytes.Contains(seen, []byte(want)) {
+			return seen
+		}
+	}
+	t.Fatalf("timed out waiting for %q, got %q", want, seen)
+	return seen
+}
+
+func TestResizeDeliversSIGWINCHSoRunningCommandSeesNewSize(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh(1) not available on this system")
+	}
+	if _, err := exec.LookPath("stty"); err != nil {
+		t.Skip("stty(1) not available on this system")
+	}
+
+	s, err := NewSession("winch-me", "sh")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer s.Close()
+
+	if _, _, ok := s.Resize(80, 24); !ok {
+		t.Fatal("initial Resize failed")
+	}
+
+	// trap SIGWINCH and re-print `stty size` each time it fires, so the
+	// second reading only appears once the signal has actually arrived.
+	script := "trap 'stty size' WINCH\n" +
+		"stty size\n" +
+		"while :; do sleep 0.05; done\n"
+	if _, err := s.pty.Write([]byte(script)); err != nil {
+		t.Fatalf("write script to pty: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	readUntil(t, s, "24 80", deadline)
+
+	if _, _, ok := s.Resize(100, 40); !ok {
+		t.Fatal("second Resize failed")
+	}
+
+	readUntil(t, s, "40 100", time.Now().Add(5*time.Second))
+}
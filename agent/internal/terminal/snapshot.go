@@ -0,0 +1,84 @@
+package terminal
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/redact"
+)
+
+// SessionSnapshot captures a Session's metadata for hot-standby failover:
+// enough for the Console to present the correct session info on a standby
+// agent, even though the PTY and child process themselves don't survive
+// failover and must be re-established separately.
+type SessionSnapshot struct {
+	ID          string            `json:"id"`
+	Shell       string            `json:"shell"`
+	Env         []string          `json:"env,omitempty"`
+	WorkingDir  string            `json:"working_dir,omitempty"`
+	Cols        uint16            `json:"cols"`
+	Rows        uint16            `json:"rows"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	BytesIn     uint64            `json:"bytes_in"`
+	BytesOut    uint64            `json:"bytes_out"`
+	ReadOnly    bool              `json:"read_only"`
+	EnforceUTF8 bool              `json:"enforce_utf8,omitempty"`
+	StartedAt   time.Time         `json:"started_at"`
+}
+
+// Snapshot serializes s's metadata to JSON for Manager.Restore.
+func (s *Session) Snapshot() ([]byte, error) {
+	s.mu.RLock()
+	snap := SessionSnapshot{
+		ID:          s.ID,
+		Shell:       s.Shell,
+		Env:         s.Env,
+		WorkingDir:  s.WorkingDir,
+		Cols:        s.Cols,
+		Rows:        s.Rows,
+		Annotations: s.Annotations,
+		BytesIn:     s.bytesIn,
+		BytesOut:    s.bytesOut,
+		ReadOnly:    s.ReadOnly,
+		EnforceUTF8: s.EnforceUTF8,
+		StartedAt:   s.StartedAt,
+	}
+	s.mu.RUnlock()
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return nil, fmt.Errorf("terminal: marshal session snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// Restore reconstructs a session record from a snapshot produced by
+// Session.Snapshot, without re-spawning its process — the PTY is gone
+// after failover, but the metadata lets the Console present the correct
+// session info until a real session takes its place (see Manager.Takeover).
+func (m *Manager) Restore(snapshot []byte) error {
+	var snap SessionSnapshot
+	if err := json.Unmarshal(snapshot, &snap); err != nil {
+		return fmt.Errorf("terminal: unmarshal session snapshot: %w", err)
+	}
+
+	s := &Session{
+		ID:             snap.ID,
+		Shell:          snap.Shell,
+		Env:            snap.Env,
+		WorkingDir:     snap.WorkingDir,
+		Cols:           snap.Cols,
+		Rows:           snap.Rows,
+		Annotations:    snap.Annotations,
+		ReadOnly:       snap.ReadOnly,
+		EnforceUTF8:    snap.EnforceUTF8,
+		StartedAt:      snap.StartedAt,
+		bytesIn:        snap.BytesIn,
+		bytesOut:       snap.BytesOut,
+		done:           make(chan int),
+		passwordRedact: redact.NewPasswordRedactor(),
+	}
+	m.Add(s)
+	return nil
+}
@@ -0,0 +1,71 @@
+package terminal
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestSessionSnapshotAndManagerRestore(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat(1) not available on this system")
+	}
+
+	m := NewManager()
+	s, err := NewSession("sess-1", "cat")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	m.Add(s)
+
+	s.Env = []string{"FOO=bar"}
+	s.WorkingDir = "/tmp"
+	s.Cols, s.Rows = 120, 40
+	s.Annotations = map[string]string{"ticket": "OPS-42"}
+	s.ReadOnly = true
+	if _, err := s.WriteInput([]byte("hello")); err != nil {
+		t.Fatalf("WriteInput: %v", err)
+	}
+	s.bytesOut = 99
+
+	snap, err := s.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	s.Close()
+	m.Remove(s.ID)
+
+	if err := m.Restore(snap); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	restored, ok := m.Get("sess-1")
+	if !ok {
+		t.Fatal("Get: restored session not found")
+	}
+
+	if restored.Shell != "cat" {
+		t.Errorf("Shell = %q, want cat", restored.Shell)
+	}
+	if len(restored.Env) != 1 || restored.Env[0] != "FOO=bar" {
+		t.Errorf("Env = %v, want [FOO=bar]", restored.Env)
+	}
+	if restored.WorkingDir != "/tmp" {
+		t.Errorf("WorkingDir = %q, want /tmp", restored.WorkingDir)
+	}
+	if restored.Cols != 120 || restored.Rows != 40 {
+		t.Errorf("dimensions = %dx%d, want 120x40", restored.Cols, restored.Rows)
+	}
+	if restored.Annotations["ticket"] != "OPS-42" {
+		t.Errorf("Annotations = %+v, want ticket=OPS-42", restored.Annotations)
+	}
+	if !restored.ReadOnly {
+		t.Error("ReadOnly = false, want true")
+	}
+	if restored.bytesIn != uint64(len("hello")) {
+		t.Errorf("bytesIn = %d, want %d", restored.bytesIn, len("hello"))
+	}
+	if restored.bytesOut != 99 {
+		t.Errorf("bytesOut = %d, want 99", restored.bytesOut)
+	}
+}
@@ -0,0 +1,32 @@
+package terminal
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+)
+
+func TestWriteInputReturnsErrSuspendedWhilePaused(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat(1) not available on this system")
+	}
+
+	m := NewManager()
+	s, err := NewSession("sess-1", "cat")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer s.Close()
+	m.Add(s)
+	s.StartForwarding(&recordingSender{}, false)
+
+	m.Suspend()
+	if _, _, err := m.WriteInput("sess-1", []byte("hi\n"), 0); !errors.Is(err, ErrSuspended) {
+		t.Fatalf("WriteInput while suspended = %v, want ErrSuspended", err)
+	}
+
+	m.Resume()
+	if _, _, err := m.WriteInput("sess-1", []byte("hi\n"), 0); err != nil {
+		t.Fatalf("WriteInput after Resume: %v", err)
+	}
+}
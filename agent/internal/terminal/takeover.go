@@ -0,0 +1,36 @@
+package terminal
+
+import "fmt"
+
+// Takeover atomically re-keys a session from existingSessionID to
+// newSessionID, e.g. when a user's browser reconnects with a new session
+// after a brief network drop and should resume its previous shell rather
+// than starting a new one. The underlying PTY and its child process
+// continue running untouched; only the registry key and the session's own
+// ID (used to tag subsequently forwarded output) change. Any buffered
+// scrollback is replayed to newSessionID via the session's OutputSender so
+// the reconnecting client can catch up on what it missed.
+func (m *Manager) Takeover(existingSessionID, newSessionID string) error {
+	m.mu.Lock()
+	s, ok := m.sessions[existingSessionID]
+	if !ok {
+		m.mu.Unlock()
+		return fmt.Errorf("terminal: unknown session %q", existingSessionID)
+	}
+	delete(m.sessions, existingSessionID)
+	m.sessions[newSessionID] = s
+	m.mu.Unlock()
+
+	s.mu.Lock()
+	s.ID = newSessionID
+	sender := s.sender
+	s.mu.Unlock()
+
+	scrollback := s.scrollbackSnapshot()
+	if sender != nil && len(scrollback) > 0 {
+		if err := sender.SendOutput(newSessionID, scrollback); err != nil {
+			return fmt.Errorf("terminal: replay scrollback to %q: %w", newSessionID, err)
+		}
+	}
+	return nil
+}
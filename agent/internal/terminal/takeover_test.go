@@ -0,0 +1,94 @@
+package terminal
+
+import (
+	"bytes"
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingSender struct {
+	mu    sync.Mutex
+	calls []struct {
+		sessionID string
+		data      []byte
+	}
+}
+
+func (r *recordingSender) SendOutput(sessionID string, data []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := append([]byte(nil), data...)
+	r.calls = append(r.calls, struct {
+		sessionID string
+		data      []byte
+	}{sessionID, cp})
+	return nil
+}
+
+func (r *recordingSender) waitForSessionOutput(t *testing.T, sessionID string, want []byte) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		r.mu.Lock()
+		for _, c := range r.calls {
+			if c.sessionID == sessionID && bytes.Contains(c.data, want) {
+				r.mu.Unlock()
+				return
+			}
+		}
+		r.mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for sender to receive %q tagged with session %q", want, sessionID)
+}
+
+func TestManagerTakeoverRetagsOutputAndReplaysScrollback(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat(1) not available on this system")
+	}
+
+	m := NewManager()
+	s, err := NewSession("A", "cat")
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	m.Add(s)
+	defer s.Close()
+
+	sender := &recordingSender{}
+	s.StartForwarding(sender, true)
+
+	if _, err := s.pty.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("write to pty: %v", err)
+	}
+	sender.waitForSessionOutput(t, "A", []byte("hello"))
+
+	if err := m.Takeover("A", "B"); err != nil {
+		t.Fatalf("Takeover: %v", err)
+	}
+
+	if _, ok := m.Get("A"); ok {
+		t.Error("expected old session ID to no longer be registered after takeover")
+	}
+	got, ok := m.Get("B")
+	if !ok || got != s {
+		t.Fatal("expected the session to be registered under the new ID after takeover")
+	}
+
+	// The scrollback captured under "A" is replayed tagged with "B".
+	sender.waitForSessionOutput(t, "B", []byte("hello"))
+
+	if _, err := s.pty.Write([]byte("world\n")); err != nil {
+		t.Fatalf("write to pty: %v", err)
+	}
+	sender.waitForSessionOutput(t, "B", []byte("world"))
+}
+
+func TestManagerTakeoverUnknownSession(t *testing.T) {
+	m := NewManager()
+	if err := m.Takeover("does-not-exist", "new"); err == nil {
+		t.Fatal("expected an error taking over an unknown session")
+	}
+}
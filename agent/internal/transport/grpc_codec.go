@@ -0,0 +1,32 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// envelopeCodec implements google.golang.org/grpc/encoding.Codec, marshaling
+// protocol.Envelope as JSON directly on the wire. This lets the gRPC
+// transport reuse the same envelope format as the WebSocket transport
+// instead of requiring a parallel protobuf schema and generated stubs.
+type envelopeCodec struct{}
+
+func (envelopeCodec) Name() string { return "sindri-envelope-json" }
+
+func (envelopeCodec) Marshal(v interface{}) ([]byte, error) {
+	env, ok := v.(*protocol.Envelope)
+	if !ok {
+		return nil, fmt.Errorf("transport: grpc codec: unsupported type %T", v)
+	}
+	return json.Marshal(env)
+}
+
+func (envelopeCodec) Unmarshal(data []byte, v interface{}) error {
+	env, ok := v.(*protocol.Envelope)
+	if !ok {
+		return fmt.Errorf("transport: grpc codec: unsupported type %T", v)
+	}
+	return json.Unmarshal(data, env)
+}
@@ -0,0 +1,36 @@
+package transport
+
+import (
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestEnvelopeCodecRoundTrip(t *testing.T) {
+	codec := envelopeCodec{}
+	original, err := protocol.NewEnvelope(protocol.MsgTypeTerminalBell, protocol.MsgTerminalBell{SessionID: "sess-1"})
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+
+	data, err := codec.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var decoded protocol.Envelope
+	if err := codec.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded.Type != original.Type {
+		t.Errorf("Type = %q, want %q", decoded.Type, original.Type)
+	}
+
+	var bell protocol.MsgTerminalBell
+	if err := decoded.Decode(&bell); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if bell.SessionID != "sess-1" {
+		t.Errorf("SessionID = %q, want sess-1", bell.SessionID)
+	}
+}
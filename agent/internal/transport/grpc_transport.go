@@ -0,0 +1,71 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+const bridgeStreamMethod = "/sindri.agent.v1.Bridge/Stream"
+
+func init() {
+	encoding.RegisterCodec(envelopeCodec{})
+}
+
+// grpcTransport carries protocol Envelopes over a single bidirectional gRPC
+// stream, as an alternative to the WebSocket transport for environments
+// that prefer gRPC (e.g. behind an existing gRPC-aware load balancer).
+type grpcTransport struct {
+	conn   *grpc.ClientConn
+	stream grpc.ClientStream
+}
+
+// DialGRPC opens a gRPC connection to target and establishes the
+// agent-Console bridge stream.
+func DialGRPC(ctx context.Context, target string) (Transport, error) {
+	conn, err := grpc.NewClient(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(envelopeCodec{}.Name())),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("transport: dial grpc: %w", err)
+	}
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{
+		StreamName:    "Stream",
+		ServerStreams: true,
+		ClientStreams: true,
+	}, bridgeStreamMethod)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("transport: open bridge stream: %w", err)
+	}
+
+	return &grpcTransport{conn: conn, stream: stream}, nil
+}
+
+func (t *grpcTransport) Send(env *protocol.Envelope) error {
+	if err := t.stream.SendMsg(env); err != nil {
+		return fmt.Errorf("transport: grpc send: %w", err)
+	}
+	return nil
+}
+
+func (t *grpcTransport) Receive() (*protocol.Envelope, error) {
+	var env protocol.Envelope
+	if err := t.stream.RecvMsg(&env); err != nil {
+		return nil, fmt.Errorf("transport: grpc receive: %w", err)
+	}
+	return &env, nil
+}
+
+func (t *grpcTransport) Close() error {
+	return t.conn.Close()
+}
+
+var _ Transport = (*grpcTransport)(nil)
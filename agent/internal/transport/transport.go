@@ -0,0 +1,12 @@
+// Package transport abstracts the agent-to-Console link so the WebSocket
+// and gRPC implementations can be swapped without touching call sites.
+package transport
+
+import "github.com/pacphi/sindri/agent/internal/protocol"
+
+// Transport sends and receives protocol Envelopes with the Console.
+type Transport interface {
+	Send(*protocol.Envelope) error
+	Receive() (*protocol.Envelope, error)
+	Close() error
+}
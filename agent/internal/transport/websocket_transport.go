@@ -0,0 +1,11 @@
+package transport
+
+import "github.com/pacphi/sindri/agent/internal/websocket"
+
+// compile-time assertion that websocket.Client satisfies Transport.
+var _ Transport = (*websocket.Client)(nil)
+
+// NewWebSocket wraps client as a Transport. This is the default transport.
+func NewWebSocket(client *websocket.Client) Transport {
+	return client
+}
@@ -0,0 +1,171 @@
+// Package tunnel forwards TCP connections through the agent to a remote
+// host and port, on behalf of the Console, guarded by
+// SINDRI_AGENT_PORT_FORWARD_ENABLED and SINDRI_AGENT_ALLOWED_FORWARD_HOSTS.
+package tunnel
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pacphi/sindri/agent/internal/idgen"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// ErrDisabled is returned by Start when the agent has not opted in to
+// port forwarding via SINDRI_AGENT_PORT_FORWARD_ENABLED.
+var ErrDisabled = errors.New("tunnel: port forwarding is disabled")
+
+// ErrHostNotAllowed is returned by Start when the requested remote host is
+// not in SINDRI_AGENT_ALLOWED_FORWARD_HOSTS.
+var ErrHostNotAllowed = errors.New("tunnel: remote host is not allowlisted")
+
+// ErrInvalidTunnelID is returned by Start when req.TunnelID is unfit to
+// use as a Unix socket filename component.
+var ErrInvalidTunnelID = errors.New("tunnel: invalid tunnel id")
+
+// Manager tracks active port-forward tunnels.
+type Manager struct {
+	enabled      bool
+	allowedHosts map[string]bool
+
+	mu      sync.Mutex
+	tunnels map[string]*tunnel
+}
+
+// NewManager returns a Manager gated by enabled and constrained to
+// allowedHosts.
+func NewManager(enabled bool, allowedHosts []string) *Manager {
+	allowed := make(map[string]bool, len(allowedHosts))
+	for _, h := range allowedHosts {
+		allowed[h] = true
+	}
+	return &Manager{enabled: enabled, allowedHosts: allowed, tunnels: make(map[string]*tunnel)}
+}
+
+// tunnel is a single active port forward: a Unix socket listener whose
+// accepted connections are piped to a fixed remote address.
+type tunnel struct {
+	listener net.Listener
+	sockPath string
+}
+
+// Start begins forwarding req.TunnelID: it listens on a Unix socket
+// (deliberately not a TCP port, so nothing outside the local filesystem
+// can reach it) and pipes each accepted connection to
+// req.RemoteHost:req.RemotePort via net.Dial. It returns the Unix socket
+// path the Console-side client should connect to.
+func (m *Manager) Start(req protocol.MsgPortForwardStart) (string, error) {
+	if !m.enabled {
+		return "", ErrDisabled
+	}
+	if !m.allowedHosts[req.RemoteHost] {
+		return "", ErrHostNotAllowed
+	}
+	if !isValidTunnelID(req.TunnelID) {
+		return "", ErrInvalidTunnelID
+	}
+
+	sockPath := filepath.Join(os.TempDir(), fmt.Sprintf("sindri-tunnel-%s.sock", req.TunnelID))
+	os.Remove(sockPath) // stale socket left behind by a prior crashed run, if any
+
+	listener, err := net.Listen("unix", sockPath)
+	if err != nil {
+		return "", fmt.Errorf("tunnel: listen on %q: %w", sockPath, err)
+	}
+
+	m.mu.Lock()
+	m.tunnels[req.TunnelID] = &tunnel{listener: listener, sockPath: sockPath}
+	m.mu.Unlock()
+
+	remoteAddr := fmt.Sprintf("%s:%d", req.RemoteHost, req.RemotePort)
+	go acceptLoop(listener, remoteAddr)
+
+	return sockPath, nil
+}
+
+// isValidTunnelID reports whether id is fit to interpolate into a Unix
+// socket filename: idgen.IsValidID's usual bounds, plus a rejection of
+// path separators and ".." so a Console-supplied TunnelID can't walk
+// sockPath out of os.TempDir() (e.g. "../../tmp/evil").
+func isValidTunnelID(id string) bool {
+	return idgen.IsValidID(id) &&
+		!strings.ContainsAny(id, `/\`) &&
+		id != ".." && id != "."
+}
+
+// Stop tears down the tunnel identified by tunnelID, closing its listener
+// and removing the Unix socket file. It is a no-op if no such tunnel is
+// active.
+func (m *Manager) Stop(tunnelID string) error {
+	m.mu.Lock()
+	t, ok := m.tunnels[tunnelID]
+	delete(m.tunnels, tunnelID)
+	m.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	err := t.listener.Close()
+	os.Remove(t.sockPath)
+	if err != nil {
+		return fmt.Errorf("tunnel: close listener: %w", err)
+	}
+	return nil
+}
+
+// acceptLoop accepts connections on listener until it is closed (by
+// Manager.Stop), forwarding each one to remoteAddr.
+func acceptLoop(listener net.Listener, remoteAddr string) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go forward(conn, remoteAddr)
+	}
+}
+
+// forward dials remoteAddr and pipes bytes bidirectionally between conn and
+// the remote connection until both directions have finished.
+func forward(conn net.Conn, remoteAddr string) {
+	defer conn.Close()
+
+	remote, err := net.Dial("tcp", remoteAddr)
+	if err != nil {
+		return
+	}
+	defer remote.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		io.Copy(remote, conn)
+		closeWrite(remote)
+	}()
+	go func() {
+		defer wg.Done()
+		io.Copy(conn, remote)
+		closeWrite(conn)
+	}()
+	wg.Wait()
+}
+
+// closeWrite half-closes conn's write side, if it supports doing so,
+// signaling EOF to the peer without tearing down the whole connection —
+// needed so e.g. an HTTP server sees the end of the request body while its
+// response is still being streamed back.
+func closeWrite(conn net.Conn) {
+	type writeCloser interface {
+		CloseWrite() error
+	}
+	if wc, ok := conn.(writeCloser); ok {
+		_ = wc.CloseWrite()
+	}
+}
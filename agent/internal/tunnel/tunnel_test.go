@@ -0,0 +1,137 @@
+package tunnel
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestManagerForwardsConnectionToHTTPServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "hello from remote")
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatalf("strconv.Atoi: %v", err)
+	}
+
+	m := NewManager(true, []string{"127.0.0.1"})
+	sockPath, err := m.Start(protocol.MsgPortForwardStart{
+		TunnelID:   "t1",
+		RemoteHost: "127.0.0.1",
+		RemotePort: port,
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop("t1")
+
+	conn, err := net.DialTimeout("unix", sockPath, time.Second)
+	if err != nil {
+		t.Fatalf("Dial unix socket: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\nConnection: close\r\n\r\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatalf("ReadResponse: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(body) != "hello from remote" {
+		t.Errorf("body = %q, want %q", body, "hello from remote")
+	}
+}
+
+func TestManagerStartDisabledReturnsErrDisabled(t *testing.T) {
+	m := NewManager(false, nil)
+	_, err := m.Start(protocol.MsgPortForwardStart{TunnelID: "t2", RemoteHost: "127.0.0.1", RemotePort: 80})
+	if !errors.Is(err, ErrDisabled) {
+		t.Fatalf("Start() error = %v, want ErrDisabled", err)
+	}
+}
+
+func TestManagerStartRejectsDisallowedHost(t *testing.T) {
+	m := NewManager(true, []string{"127.0.0.1"})
+	_, err := m.Start(protocol.MsgPortForwardStart{TunnelID: "t3", RemoteHost: "evil.example.com", RemotePort: 80})
+	if !errors.Is(err, ErrHostNotAllowed) {
+		t.Fatalf("Start() error = %v, want ErrHostNotAllowed", err)
+	}
+}
+
+func TestManagerStartRejectsPathTraversalTunnelID(t *testing.T) {
+	m := NewManager(true, []string{"127.0.0.1"})
+	_, err := m.Start(protocol.MsgPortForwardStart{
+		TunnelID:   "foo/../../../../tmp/evil-poc",
+		RemoteHost: "127.0.0.1",
+		RemotePort: 80,
+	})
+	if !errors.Is(err, ErrInvalidTunnelID) {
+		t.Fatalf("Start() error = %v, want ErrInvalidTunnelID", err)
+	}
+}
+
+func TestManagerStartRejectsTunnelIDWithSlash(t *testing.T) {
+	m := NewManager(true, []string{"127.0.0.1"})
+	_, err := m.Start(protocol.MsgPortForwardStart{
+		TunnelID:   "sub/dir",
+		RemoteHost: "127.0.0.1",
+		RemotePort: 80,
+	})
+	if !errors.Is(err, ErrInvalidTunnelID) {
+		t.Fatalf("Start() error = %v, want ErrInvalidTunnelID", err)
+	}
+}
+
+func TestManagerStartRejectsEmptyTunnelID(t *testing.T) {
+	m := NewManager(true, []string{"127.0.0.1"})
+	_, err := m.Start(protocol.MsgPortForwardStart{TunnelID: "", RemoteHost: "127.0.0.1", RemotePort: 80})
+	if !errors.Is(err, ErrInvalidTunnelID) {
+		t.Fatalf("Start() error = %v, want ErrInvalidTunnelID", err)
+	}
+}
+
+func TestManagerStopRemovesSocketFile(t *testing.T) {
+	m := NewManager(true, []string{"127.0.0.1"})
+	sockPath, err := m.Start(protocol.MsgPortForwardStart{TunnelID: "t4", RemoteHost: "127.0.0.1", RemotePort: 1})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := m.Stop("t4"); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if _, err := os.Stat(sockPath); !os.IsNotExist(err) {
+		t.Errorf("socket file still exists after Stop: %v", err)
+	}
+}
+
+func TestManagerStopUnknownTunnelIsNoOp(t *testing.T) {
+	m := NewManager(true, nil)
+	if err := m.Stop("does-not-exist"); err != nil {
+		t.Errorf("Stop() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,128 @@
+// Package updater implements self-update of the running agent binary: it
+// downloads a new build, verifies its checksum, swaps it in for the
+// currently running executable, and re-execs into it.
+package updater
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"syscall"
+
+	"github.com/pacphi/sindri/agent/internal/semver"
+)
+
+// ErrUpToDate is returned by Update when availableVersion is not newer
+// than currentVersion, so no download is attempted.
+var ErrUpToDate = errors.New("updater: already up to date")
+
+// Manager performs self-updates of the agent binary.
+type Manager struct {
+	// HTTPClient is used to download the new binary. Defaults to
+	// http.DefaultClient when nil.
+	HTTPClient *http.Client
+}
+
+// NewManager returns a Manager ready to use.
+func NewManager() *Manager {
+	return &Manager{HTTPClient: http.DefaultClient}
+}
+
+// Update verifies that availableVersion is newer than currentVersion
+// (returning ErrUpToDate otherwise), then downloads the binary at
+// downloadURL, verifies it against the expected SHA-256 checksum
+// (hex-encoded), replaces the currently running executable, and re-execs
+// into it. On success it does not return.
+func (m *Manager) Update(currentVersion, availableVersion, downloadURL, checksum string) error {
+	current, err := semver.Parse(currentVersion)
+	if err != nil {
+		return fmt.Errorf("updater: parse current version: %w", err)
+	}
+	available, err := semver.Parse(availableVersion)
+	if err != nil {
+		return fmt.Errorf("updater: parse available version: %w", err)
+	}
+	if !current.LessThan(available) {
+		return ErrUpToDate
+	}
+
+	tmpPath, err := m.download(downloadURL)
+	if err != nil {
+		return fmt.Errorf("updater: download: %w", err)
+	}
+	defer os.Remove(tmpPath)
+
+	if err := verifyChecksum(tmpPath, checksum); err != nil {
+		return fmt.Errorf("updater: verify checksum: %w", err)
+	}
+
+	execPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("updater: resolve executable path: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0o755); err != nil {
+		return fmt.Errorf("updater: chmod new binary: %w", err)
+	}
+	if err := os.Rename(tmpPath, execPath); err != nil {
+		return fmt.Errorf("updater: replace binary: %w", err)
+	}
+
+	if err := syscall.Exec(execPath, os.Args, os.Environ()); err != nil {
+		return fmt.Errorf("updater: re-exec: %w", err)
+	}
+	return nil
+}
+
+// download fetches url into a temp file and returns its path. The caller is
+// responsible for removing it.
+func (m *Manager) download(url string) (string, error) {
+	client := m.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	f, err := os.CreateTemp("", "sindri-agent-update-*")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// verifyChecksum returns an error if the SHA-256 of the file at path does
+// not match the expected hex-encoded checksum.
+func verifyChecksum(path, expected string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	got := hex.EncodeToString(h.Sum(nil))
+	if got != expected {
+		return fmt.Errorf("checksum mismatch: got %s, want %s", got, expected)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package updater
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestVerifyChecksumRejectsCorruptedBinary(t *testing.T) {
+	content := []byte("a legitimate binary payload")
+	sum := sha256.Sum256(content)
+	validChecksum := hex.EncodeToString(sum[:])
+
+	corrupted := []byte("a corrupted, tampered payload")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(corrupted)
+	}))
+	defer server.Close()
+
+	m := NewManager()
+	path, err := m.download(server.URL)
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	defer os.Remove(path)
+
+	if err := verifyChecksum(path, validChecksum); err == nil {
+		t.Fatal("expected checksum verification to fail for corrupted binary, got nil error")
+	}
+}
+
+func TestVerifyChecksumAcceptsMatchingBinary(t *testing.T) {
+	content := []byte("a legitimate binary payload")
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	m := NewManager()
+	path, err := m.download(server.URL)
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	defer os.Remove(path)
+
+	if err := verifyChecksum(path, checksum); err != nil {
+		t.Fatalf("expected checksum verification to pass, got: %v", err)
+	}
+}
+
+func TestUpdateReturnsErrUpToDateWhenNotNewer(t *testing.T) {
+	m := NewManager()
+	err := m.Update("1.2.0", "1.2.0", "http://unused.invalid", "deadbeef")
+	if !errors.Is(err, ErrUpToDate) {
+		t.Fatalf("Update() error = %v, want ErrUpToDate", err)
+	}
+}
+
+func TestUpdateReturnsErrUpToDateWhenOlder(t *testing.T) {
+	m := NewManager()
+	err := m.Update("2.0.0", "1.2.0", "http://unused.invalid", "deadbeef")
+	if !errors.Is(err, ErrUpToDate) {
+		t.Fatalf("Update() error = %v, want ErrUpToDate", err)
+	}
+}
+
+func TestUpdateRejectsInvalidVersionStrings(t *testing.T) {
+	m := NewManager()
+	if err := m.Update("not-a-version", "1.2.0", "http://unused.invalid", "deadbeef"); err == nil {
+		t.Fatal("expected an error for an invalid current version")
+	}
+	if err := m.Update("1.2.0", "not-a-version", "http://unused.invalid", "deadbeef"); err == nil {
+		t.Fatal("expected an error for an invalid available version")
+	}
+}
@@ -0,0 +1,167 @@
+// Package wal implements a write-ahead log that buffers metrics payloads on
+// disk while the agent is disconnected from the Console, so no samples are
+// lost across a WebSocket outage.
+package wal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pacphi/sindri/agent/internal/metrics"
+)
+
+// Writer appends MetricsPayload records to a local file. It is safe for
+// concurrent use.
+type Writer struct {
+	mu         sync.Mutex
+	path       string
+	maxRecords int
+}
+
+// NewWriter creates a Writer that appends to path, capping the log at
+// maxRecords records by pruning the oldest entries once the cap is reached.
+func NewWriter(path string, maxRecords int) (*Writer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("wal: create dir: %w", err)
+	}
+	return &Writer{path: path, maxRecords: maxRecords}, nil
+}
+
+// Append writes payload as a new record. If the log now exceeds
+// maxRecords, the oldest records are pruned.
+func (w *Writer) Append(payload *metrics.MetricsPayload) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	f, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("wal: open for append: %w", err)
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("wal: marshal record: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("wal: write record: %w", err)
+	}
+
+	return w.pruneLocked()
+}
+
+// pruneLocked drops the oldest records so the log holds at most
+// w.maxRecords entries. Callers must hold w.mu.
+func (w *Writer) pruneLocked() error {
+	records, err := readRecords(w.path)
+	if err != nil {
+		return err
+	}
+	if len(records) <= w.maxRecords {
+		return nil
+	}
+	return writeRecords(w.path, records[len(records)-w.maxRecords:])
+}
+
+// Prune removes records from the log, keeping only those after the given
+// count has been successfully delivered. It is called once the Console has
+// ACKed a batch of n replayed records.
+func (w *Writer) Prune(n int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	records, err := readRecords(w.path)
+	if err != nil {
+		return err
+	}
+	if n >= len(records) {
+		return writeRecords(w.path, nil)
+	}
+	return writeRecords(w.path, records[n:])
+}
+
+// Replayer reads buffered records from the WAL for redelivery on reconnect.
+type Replayer struct {
+	path       string
+	maxRecords int
+}
+
+// NewReplayer creates a Replayer over the log at path.
+func NewReplayer(path string, maxRecords int) *Replayer {
+	return &Replayer{path: path, maxRecords: maxRecords}
+}
+
+// Replay returns the buffered records in the order they were written,
+// oldest first, capped at maxRecords.
+func (r *Replayer) Replay() ([]*metrics.MetricsPayload, error) {
+	records, err := readRecords(r.path)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > r.maxRecords {
+		records = records[len(records)-r.maxRecords:]
+	}
+	return records, nil
+}
+
+func readRecords(path string) ([]*metrics.MetricsPayload, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("wal: open log: %w", err)
+	}
+	defer f.Close()
+
+	var records []*metrics.MetricsPayload
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var payload metrics.MetricsPayload
+		if err := json.Unmarshal(line, &payload); err != nil {
+			return nil, fmt.Errorf("wal: decode record: %w", err)
+		}
+		records = append(records, &payload)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("wal: scan log: %w", err)
+	}
+	return records, nil
+}
+
+func writeRecords(path string, records []*metrics.MetricsPayload) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("wal: open temp log: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	for _, rec := range records {
+		line, err := json.Marshal(rec)
+		if err != nil {
+			f.Close()
+			return fmt.Errorf("wal: marshal record: %w", err)
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			f.Close()
+			return fmt.Errorf("wal: write record: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("wal: flush temp log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("wal: close temp log: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,80 @@
+package wal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/metrics"
+)
+
+func TestWriterReplayerRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.wal")
+
+	writer, err := NewWriter(path, 1440)
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+
+	const offlineRecords = 10
+	base := time.Now().UTC()
+	for i := 0; i < offlineRecords; i++ {
+		payload := &metrics.MetricsPayload{
+			Timestamp:  base.Add(time.Duration(i) * time.Minute),
+			CPUPercent: float64(i),
+		}
+		if err := writer.Append(payload); err != nil {
+			t.Fatalf("Append(%d): %v", i, err)
+		}
+	}
+
+	replayer := NewReplayer(path, 1440)
+	replayed, err := replayer.Replay()
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if len(replayed) != offlineRecords {
+		t.Fatalf("got %d replayed records, want %d", len(replayed), offlineRecords)
+	}
+	for i, rec := range replayed {
+		if rec.CPUPercent != float64(i) {
+			t.Errorf("record %d: got CPUPercent %v, want %v (out of order)", i, rec.CPUPercent, i)
+		}
+	}
+
+	if err := writer.Prune(offlineRecords); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	remaining, err := replayer.Replay()
+	if err != nil {
+		t.Fatalf("Replay after prune: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Fatalf("got %d records after prune, want 0", len(remaining))
+	}
+}
+
+func TestWriterPrunesOldestOnOverflow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.wal")
+	writer, err := NewWriter(path, 3)
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := writer.Append(&metrics.MetricsPayload{CPUPercent: float64(i)}); err != nil {
+			t.Fatalf("Append(%d): %v", i, err)
+		}
+	}
+
+	replayer := NewReplayer(path, 10)
+	records, err := replayer.Replay()
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3 (oldest pruned)", len(records))
+	}
+	if records[0].CPUPercent != 2 {
+		t.Errorf("got oldest surviving CPUPercent %v, want 2", records[0].CPUPercent)
+	}
+}
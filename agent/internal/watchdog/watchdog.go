@@ -0,0 +1,134 @@
+// Package watchdog detects a Console that has stopped responding at the
+// application level, even though the underlying WebSocket connection's
+// TCP ping/pong still looks alive — e.g. a Console stuck behind a
+// misbehaving load balancer that keeps the socket open but drops traffic.
+package watchdog
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// Reconnector tears down and re-establishes the agent's connection to the
+// Console, e.g. websocket.Client's Close followed by Dial.
+type Reconnector interface {
+	Reconnect() error
+}
+
+// Monitor tracks the time of the most recently received MsgWatchdogPing
+// and, once a ping interval has been set via SetInterval (from the
+// Console's MsgConsoleInfo),
+// considers the Console unresponsive if 2x that interval elapses without
+// one.
+type Monitor struct {
+	mu sync.Mutex
+
+	// pingInterval is the Console's expected MsgWatchdogPing interval, as
+	// reported in MsgConsoleInfo.WatchdogPingIntervalMS. Zero disables
+	// the watchdog: Unresponsive always reports false.
+	pingInterval time.Duration
+
+	lastPing time.Time
+	done     chan struct{}
+}
+
+// NewMonitor returns a Monitor with no interval configured (disabled)
+// until SetInterval is called.
+func NewMonitor() *Monitor {
+	return &Monitor{}
+}
+
+// SetInterval configures m's expected ping interval, e.g. from a received
+// MsgConsoleInfo.
+func (m *Monitor) SetInterval(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pingInterval = d
+}
+
+// Interval returns m's currently configured expected ping interval.
+func (m *Monitor) Interval() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.pingInterval
+}
+
+// RecordPing records that a MsgWatchdogPing was just received at now.
+func (m *Monitor) RecordPing(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastPing = now
+}
+
+// Unresponsive reports whether more than 2x the configured ping interval
+// has elapsed since the last RecordPing, as of now. It always reports
+// false if the interval is unset (zero) or no ping has ever been
+// recorded.
+func (m *Monitor) Unresponsive(now time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.pingInterval <= 0 || m.lastPing.IsZero() {
+		return false
+	}
+	return now.Sub(m.lastPing) > 2*m.pingInterval
+}
+
+// HandlePing records ping's arrival (resetting Unresponsive's deadline)
+// and returns the MsgWatchdogPong the agent should send back to the
+// Console, echoing ping's PingID.
+func (m *Monitor) HandlePing(ping protocol.MsgWatchdogPing) protocol.MsgWatchdogPong {
+	now := time.Now()
+	m.RecordPing(now)
+	return protocol.MsgWatchdogPong{PingID: ping.PingID, ReceivedAt: now}
+}
+
+// defaultCheckInterval is used by Start when it needs to pick a polling
+// period finer than the configured ping interval itself.
+const defaultCheckInterval = 50 * time.Millisecond
+
+// Start begins polling Unresponsive in a background goroutine, calling
+// r.Reconnect the first time it reports true after a Start (or after a
+// prior detection has been cleared by a fresh RecordPing), until Stop is
+// called. The poll period is the shorter of Interval (as of the Start
+// call) and
+// defaultCheckInterval, so a short test interval is still detected
+// promptly. Calling Start more than once without an intervening Stop
+// leaks the earlier goroutine.
+func (m *Monitor) Start(r Reconnector) {
+	m.done = make(chan struct{})
+	interval := defaultCheckInterval
+	if pi := m.Interval(); pi > 0 && pi < interval {
+		interval = pi
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		tripped := false
+		for {
+			select {
+			case <-ticker.C:
+				if m.Unresponsive(time.Now()) {
+					if !tripped {
+						tripped = true
+						_ = r.Reconnect()
+					}
+				} else {
+					tripped = false
+				}
+			case <-m.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the background polling goroutine started by Start.
+func (m *Monitor) Stop() {
+	if m.done != nil {
+		close(m.done)
+	}
+}
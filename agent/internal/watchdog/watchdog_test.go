@@ -0,0 +1,102 @@
+package watchdog
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// countingReconnector counts how many times Reconnect is called.
+type countingReconnector struct {
+	count atomic.Int32
+}
+
+func (r *countingReconnector) Reconnect() error {
+	r.count.Add(1)
+	return nil
+}
+
+func TestUnresponsiveFalseBeforeAnyPing(t *testing.T) {
+	m := NewMonitor()
+	m.SetInterval(200 * time.Millisecond)
+
+	if m.Unresponsive(time.Now()) {
+		t.Error("Unresponsive should be false before any ping is recorded")
+	}
+}
+
+func TestUnresponsiveFalseWithoutInterval(t *testing.T) {
+	m := NewMonitor()
+	m.RecordPing(time.Now().Add(-time.Hour))
+
+	if m.Unresponsive(time.Now()) {
+		t.Error("Unresponsive should be false with no interval configured")
+	}
+}
+
+func TestUnresponsiveAfterTwiceInterval(t *testing.T) {
+	m := NewMonitor()
+	m.SetInterval(200 * time.Millisecond)
+	now := time.Now()
+	m.RecordPing(now)
+
+	if m.Unresponsive(now.Add(300 * time.Millisecond)) {
+		t.Error("Unresponsive should still be false before 2x the interval elapses")
+	}
+	if !m.Unresponsive(now.Add(500 * time.Millisecond)) {
+		t.Error("Unresponsive should be true after 2x the interval elapses")
+	}
+}
+
+func TestStartReconnectsAfterWatchdogSilence(t *testing.T) {
+	m := NewMonitor()
+	m.SetInterval(200 * time.Millisecond)
+	m.RecordPing(time.Now())
+
+	r := &countingReconnector{}
+	m.Start(r)
+	defer m.Stop()
+
+	time.Sleep(500 * time.Millisecond)
+
+	if r.count.Load() < 1 {
+		t.Error("expected Reconnect to be called after 500ms of watchdog silence with a 200ms interval")
+	}
+}
+
+func TestHandlePingRecordsPingAndEchoesID(t *testing.T) {
+	m := NewMonitor()
+	m.SetInterval(200 * time.Millisecond)
+
+	pong := m.HandlePing(protocol.MsgWatchdogPing{PingID: "ping-1", SentAt: time.Now()})
+	if pong.PingID != "ping-1" {
+		t.Errorf("PingID = %q, want %q", pong.PingID, "ping-1")
+	}
+	if pong.ReceivedAt.IsZero() {
+		t.Error("ReceivedAt should be set")
+	}
+	if m.Unresponsive(time.Now()) {
+		t.Error("Unresponsive should be false right after HandlePing")
+	}
+}
+
+func TestStartDoesNotReconnectWhilePingsContinue(t *testing.T) {
+	m := NewMonitor()
+	m.SetInterval(50 * time.Millisecond)
+	m.RecordPing(time.Now())
+
+	r := &countingReconnector{}
+	m.Start(r)
+	defer m.Stop()
+
+	for i := 0; i < 5; i++ {
+		time.Sleep(20 * time.Millisecond)
+		m.RecordPing(time.Now())
+	}
+
+	if r.count.Load() != 0 {
+		t.Errorf("Reconnect called %d times, want 0 while pings keep arriving", r.count.Load())
+	}
+}
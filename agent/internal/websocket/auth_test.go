@@ -0,0 +1,37 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/pacphi/sindri/agent/internal/auth"
+)
+
+func TestDialAppliesAuthStrategyHeaders(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}))
+	defer server.Close()
+
+	url := "ws" + server.URL[len("http"):]
+
+	client := NewClient().WithAuthStrategy(auth.BearerTokenStrategy{Token: "secret-token"})
+	if err := client.Dial(url, nil); err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer client.Close()
+
+	if gotAuth != "Bearer secret-token" {
+		t.Errorf("Authorization header = %q, want %q", gotAuth, "Bearer secret-token")
+	}
+}
@@ -0,0 +1,365 @@
+// Package websocket manages the agent's persistent WebSocket connection to
+// the Console.
+package websocket
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/pacphi/sindri/agent/internal/auth"
+	"github.com/pacphi/sindri/agent/internal/circuitbreaker"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// ErrSendQueueFull is returned by Send when the outbound write queue is at
+// capacity and the caller did not request a blocking retry.
+var ErrSendQueueFull = errors.New("websocket: send queue full")
+
+// ConnState reports whether a Client currently has an active connection.
+type ConnState string
+
+const (
+	StateDisconnected ConnState = "disconnected"
+	StateConnected    ConnState = "connected"
+)
+
+// defaultSendQueueDepth bounds how many envelopes may be buffered for send
+// before Send starts rejecting (or, with retry, blocking).
+const defaultSendQueueDepth = 256
+
+// Client wraps a WebSocket connection to the Console.
+type Client struct {
+	// TLSServerName overrides the SNI/ServerName used during the TLS
+	// handshake. It is useful when the endpoint is dialed by IP address
+	// or an internal hostname that differs from the certificate's
+	// CN/SAN. Verification is still performed against this name.
+	TLSServerName string
+
+	// TLSConfig, if set, seeds the dialer's TLS configuration (e.g. a
+	// custom RootCAs pool in tests). TLSServerName, when set, always
+	// overrides its ServerName field.
+	TLSConfig *tls.Config
+
+	// AuthStrategy, if set, is applied to the connection headers in Dial,
+	// letting the agent switch authentication schemes (bearer token,
+	// OIDC, HMAC, ...) without changing this transport code.
+	AuthStrategy auth.Strategy
+
+	// MessageSecret, if set, is used to HMAC-SHA256-sign every outbound
+	// envelope in Send and verify every inbound envelope in Receive
+	// (SINDRI_AGENT_MESSAGE_SECRET). Nil disables signing entirely.
+	MessageSecret []byte
+
+	// MaxMessageBytes sets the fragmentation threshold: an outbound
+	// payload is split into fragments carrying at most MaxMessageBytes
+	// minus fragmentHeadroom raw bytes each. A fragment frame stays under
+	// MaxMessageBytes with MsgpackCodec but not with JSONCodec, which
+	// base64-encodes each chunk (see fragmentHeadroom). It is only
+	// enforced when AutoFragment is true; zero (the default) leaves Send
+	// unfragmented.
+	MaxMessageBytes int
+
+	// AutoFragment splits an outbound envelope whose Payload exceeds
+	// MaxMessageBytes into multiple fragment envelopes (see
+	// protocol.Envelope.FragmentID), reassembled transparently by the
+	// peer's Receive before the payload reaches its handler. Useful for
+	// large payloads like file chunks or terminal recordings that would
+	// otherwise silently fail to send.
+	AutoFragment bool
+
+	// Breaker, if set, wraps every Dial attempt so a Console that's down
+	// or unreachable stops paying for repeated failed connection
+	// attempts once Breaker trips open.
+	Breaker *circuitbreaker.Breaker
+
+	// Codec controls how envelopes are encoded on the wire. Nil (the
+	// default) behaves as if set to JSONCodec{}.
+	Codec Codec
+
+	conn      *websocket.Conn
+	sendQueue chan *protocol.Envelope
+	depth     atomic.Int64
+	done      chan struct{}
+	dedup     dedupRing
+	fragments fragmentAssembler
+	metrics   ClientMetrics
+	connected atomic.Bool
+	seqNum    atomic.Uint64
+}
+
+// ClientMetrics exposes counters for observability into Client's delivery
+// behavior.
+type ClientMetrics struct {
+	// DuplicatesDropped counts inbound envelopes dropped by Receive because
+	// their MessageID matched one already seen, e.g. a retransmit after a
+	// missing ACK that the Console sent anyway.
+	DuplicatesDropped atomic.Uint64
+
+	// InvalidSignaturesDropped counts inbound envelopes dropped by Receive
+	// because they failed protocol.Verify, e.g. a message mutated or
+	// forged by a compromised intermediary. Only incremented when
+	// MessageSecret is configured.
+	InvalidSignaturesDropped atomic.Uint64
+}
+
+// NewClient returns a Client with no active connection.
+func NewClient() *Client {
+	return &Client{}
+}
+
+// WithAuthStrategy sets the auth.Strategy applied to connection headers on
+// Dial, and returns c for chaining.
+func (c *Client) WithAuthStrategy(s auth.Strategy) *Client {
+	c.AuthStrategy = s
+	return c
+}
+
+// WithCodec sets the Codec used to encode and decode envelopes on the
+// wire, and returns c for chaining. Switching to MsgpackCodec trades
+// JSON's readability for a smaller, faster encoding on high-frequency
+// payloads like metrics.
+func (c *Client) WithCodec(codec Codec) *Client {
+	c.Codec = codec
+	return c
+}
+
+// codec returns c.Codec, defaulting to JSONCodec{} when unset.
+func (c *Client) codec() Codec {
+	if c.Codec == nil {
+		return JSONCodec{}
+	}
+	return c.Codec
+}
+
+// Dial connects to the given WebSocket URL.
+func (c *Client) Dial(url string, header map[string][]string) error {
+	if c.sendQueue == nil {
+		c.sendQueue = make(chan *protocol.Envelope, defaultSendQueueDepth)
+	}
+	if c.done == nil {
+		c.done = make(chan struct{})
+	}
+
+	dialer := *websocket.DefaultDialer
+
+	if c.TLSConfig != nil || c.TLSServerName != "" {
+		tlsConfig := &tls.Config{}
+		if c.TLSConfig != nil {
+			tlsConfig = c.TLSConfig.Clone()
+		}
+		if c.TLSServerName != "" {
+			tlsConfig.ServerName = c.TLSServerName
+		}
+		dialer.TLSClientConfig = tlsConfig
+	}
+
+	connectHeader := http.Header{}
+	for k, vs := range header {
+		for _, v := range vs {
+			connectHeader.Add(k, v)
+		}
+	}
+	if c.AuthStrategy != nil {
+		if err := c.AuthStrategy.ApplyAuth(&connectHeader); err != nil {
+			return fmt.Errorf("websocket: apply auth: %w", err)
+		}
+	}
+
+	dialFn := func() error {
+		conn, _, err := dialer.Dial(url, connectHeader)
+		if err != nil {
+			return fmt.Errorf("websocket: dial: %w", err)
+		}
+		c.conn = conn
+		return nil
+	}
+	if c.Breaker != nil {
+		if err := c.Breaker.Do(dialFn); err != nil {
+			return err
+		}
+	} else if err := dialFn(); err != nil {
+		return err
+	}
+
+	c.connected.Store(true)
+	go c.writeLoop()
+	return nil
+}
+
+// Status reports whether c currently has an active connection.
+func (c *Client) Status() ConnState {
+	if c.connected.Load() {
+		return StateConnected
+	}
+	return StateDisconnected
+}
+
+// writeLoop drains the send queue onto the wire, decrementing the queue
+// depth counter as each envelope is dequeued.
+func (c *Client) writeLoop() {
+	codec := c.codec()
+	for {
+		select {
+		case env := <-c.sendQueue:
+			c.depth.Add(-1)
+			data, err := codec.Marshal(env)
+			if err != nil {
+				continue
+			}
+			_ = c.conn.WriteMessage(codec.FrameType(), data)
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// QueueDepth returns the number of envelopes currently buffered awaiting
+// send, for observability under high message volume.
+func (c *Client) QueueDepth() int {
+	return int(c.depth.Load())
+}
+
+// Metrics returns c's delivery counters.
+func (c *Client) Metrics() *ClientMetrics {
+	return &c.metrics
+}
+
+// SendOptions controls Send's behavior when the outbound queue is full.
+type SendOptions struct {
+	// RetryTimeout, if positive, makes Send block until space is
+	// available in the queue or RetryTimeout elapses, instead of
+	// immediately returning ErrSendQueueFull.
+	RetryTimeout time.Duration
+}
+
+// Send enqueues env for delivery, returning ErrSendQueueFull immediately if
+// the outbound queue is at capacity. Use SendWithRetry to block for room
+// instead of failing fast.
+func (c *Client) Send(env *protocol.Envelope) error {
+	return c.SendWithRetry(env, SendOptions{})
+}
+
+// SendWithRetry enqueues env for delivery. If the send queue is full, it
+// returns ErrSendQueueFull unless opts.RetryTimeout is set, in which case
+// it blocks up to that duration for room to free up. If AutoFragment is
+// enabled and env's payload exceeds MaxMessageBytes, env is split into
+// multiple fragment envelopes (see protocol.Envelope.FragmentID), each
+// enqueued in turn. Every envelope actually sent on the wire (each
+// fragment counts separately) is assigned the next value from c's own
+// per-connection sequence counter, overwriting whatever SeqNum it may
+// already have carried, so the peer can detect a gap in what this Client
+// sent regardless of how the envelope was originally constructed.
+func (c *Client) SendWithRetry(env *protocol.Envelope, opts SendOptions) error {
+	if env.MessageID == "" {
+		id, err := newMessageID()
+		if err != nil {
+			return err
+		}
+		env.MessageID = id
+	}
+
+	envelopes := []*protocol.Envelope{env}
+	if c.AutoFragment && c.MaxMessageBytes > 0 {
+		fragments, err := fragment(env, c.MaxMessageBytes)
+		if err != nil {
+			return err
+		}
+		envelopes = fragments
+	}
+
+	for _, e := range envelopes {
+		e.SeqNum = c.seqNum.Add(1)
+		if len(c.MessageSecret) > 0 {
+			if err := protocol.Sign(e, c.MessageSecret); err != nil {
+				return fmt.Errorf("websocket: sign envelope: %w", err)
+			}
+		}
+		if err := c.enqueue(e, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueue places env on the send queue, returning ErrSendQueueFull
+// immediately if it is full unless opts.RetryTimeout is set, in which case
+// it blocks up to that duration for room to free up.
+func (c *Client) enqueue(env *protocol.Envelope, opts SendOptions) error {
+	select {
+	case c.sendQueue <- env:
+		c.depth.Add(1)
+		return nil
+	default:
+	}
+
+	if opts.RetryTimeout <= 0 {
+		return ErrSendQueueFull
+	}
+
+	timer := time.NewTimer(opts.RetryTimeout)
+	defer timer.Stop()
+	select {
+	case c.sendQueue <- env:
+		c.depth.Add(1)
+		return nil
+	case <-timer.C:
+		return ErrSendQueueFull
+	}
+}
+
+// Receive blocks until the next valid, non-duplicate, fully-reassembled
+// Envelope arrives on the connection. An inbound envelope whose MessageID
+// matches one already seen (e.g. a retransmit after a missing ACK) is
+// dropped and counted in Metrics().DuplicatesDropped. When MessageSecret
+// is configured, an envelope that fails protocol.Verify is dropped and
+// counted in Metrics().InvalidSignaturesDropped rather than returned to
+// the caller. An envelope fragmented by the sender's AutoFragment (see
+// protocol.Envelope.FragmentID) is buffered and not returned until every
+// sibling fragment has arrived.
+func (c *Client) Receive() (*protocol.Envelope, error) {
+	codec := c.codec()
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return nil, fmt.Errorf("websocket: receive: %w", err)
+		}
+		var env protocol.Envelope
+		if err := codec.Unmarshal(data, &env); err != nil {
+			return nil, fmt.Errorf("websocket: receive: %w", err)
+		}
+		if len(c.MessageSecret) > 0 {
+			if err := protocol.Verify(env, c.MessageSecret); err != nil {
+				c.metrics.InvalidSignaturesDropped.Add(1)
+				continue
+			}
+		}
+		if c.dedup.seenOrRecord(env.MessageID) {
+			c.metrics.DuplicatesDropped.Add(1)
+			continue
+		}
+		if env.FragmentTotal > 0 {
+			reassembled, complete := c.fragments.add(&env)
+			if !complete {
+				continue
+			}
+			return reassembled, nil
+		}
+		return &env, nil
+	}
+}
+
+// Close stops the write loop and closes the underlying connection.
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	c.connected.Store(false)
+	close(c.done)
+	return c.conn.Close()
+}
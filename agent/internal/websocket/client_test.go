@@ -0,0 +1,92 @@
+package websocket
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newCertForCN generates a self-signed certificate valid for the given
+// common name, for use as a test TLS server certificate.
+func newCertForCN(t *testing.T, cn string) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		Leaf:        cert,
+	}
+}
+
+func TestDialWithTLSServerNameOverride(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}))
+	cert := newCertForCN(t, "example.com")
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	server.StartTLS()
+	defer server.Close()
+
+	// server.Listener.Addr() is an IP:port; dialing it directly means the
+	// handshake's implicit ServerName won't match the cert's CN unless we
+	// override it explicitly.
+	url := "wss://" + server.Listener.Addr().String() + "/"
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert.Leaf)
+
+	client := &Client{
+		TLSServerName: "example.com",
+		TLSConfig:     &tls.Config{RootCAs: pool},
+	}
+
+	if err := client.Dial(url, nil); err != nil {
+		t.Fatalf("Dial with server name override: %v", err)
+	}
+	defer client.Close()
+
+	if !strings.Contains(url, server.Listener.Addr().String()) {
+		t.Fatalf("sanity check: url %q should dial the IP directly", url)
+	}
+}
@@ -0,0 +1,32 @@
+package websocket
+
+import (
+	"encoding/json"
+
+	"github.com/gorilla/websocket"
+)
+
+// Codec encodes and decodes values for the wire, letting Client swap the
+// envelope encoding without changing the connection-handling code in
+// writeLoop and Receive.
+type Codec interface {
+	// Marshal encodes v.
+	Marshal(v any) ([]byte, error)
+
+	// Unmarshal decodes data into v, which must be a pointer.
+	Unmarshal(data []byte, v any) error
+
+	// FrameType is the gorilla/websocket message type (TextMessage or
+	// BinaryMessage) that Marshal's output should be sent as.
+	FrameType() int
+}
+
+// JSONCodec is the default Codec, preserving the transport's original
+// wire format.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+func (JSONCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+func (JSONCodec) FrameType() int { return websocket.TextMessage }
@@ -0,0 +1,85 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestClientDefaultsToJSONCodec(t *testing.T) {
+	c := NewClient()
+	if _, ok := c.codec().(JSONCodec); !ok {
+		t.Errorf("codec() = %T, want JSONCodec", c.codec())
+	}
+}
+
+func TestWithCodecSetsCodec(t *testing.T) {
+	c := NewClient().WithCodec(MsgpackCodec{})
+	if _, ok := c.codec().(MsgpackCodec); !ok {
+		t.Errorf("codec() = %T, want MsgpackCodec", c.codec())
+	}
+}
+
+// TestClientSendsAndReceivesWithMsgpackCodec exercises a full round trip
+// with a raw server connection speaking the same MsgpackCodec, verifying
+// Send writes a BinaryMessage frame the server can decode, and Receive
+// decodes a BinaryMessage frame the server wrote.
+func TestClientSendsAndReceivesWithMsgpackCodec(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	codec := MsgpackCodec{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		msgType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if msgType != websocket.BinaryMessage {
+			t.Errorf("server received frame type %d, want BinaryMessage", msgType)
+		}
+		var env protocol.Envelope
+		if err := codec.Unmarshal(data, &env); err != nil {
+			t.Errorf("server decode: %v", err)
+			return
+		}
+		if env.MessageID != "ping-1" {
+			t.Errorf("server decoded MessageID = %q, want ping-1", env.MessageID)
+		}
+
+		reply, err := codec.Marshal(&protocol.Envelope{Type: "test", MessageID: "pong-1"})
+		if err != nil {
+			t.Errorf("server encode: %v", err)
+			return
+		}
+		conn.WriteMessage(websocket.BinaryMessage, reply)
+	}))
+	defer server.Close()
+
+	url := "ws" + server.URL[len("http"):]
+	client := NewClient().WithCodec(codec)
+	if err := client.Dial(url, nil); err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Send(&protocol.Envelope{Type: "test", MessageID: "ping-1"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	got, err := client.Receive()
+	if err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	if got.MessageID != "pong-1" {
+		t.Errorf("Receive MessageID = %q, want pong-1", got.MessageID)
+	}
+}
@@ -0,0 +1,49 @@
+package websocket
+
+import "sync"
+
+// dedupRingSize bounds how many recently-seen MessageIDs are remembered.
+// The Console retransmits at most a few times after a missing ACK, so a
+// small ring is enough to catch a retransmit racing the original.
+const dedupRingSize = 128
+
+// dedupRing tracks the last dedupRingSize MessageIDs seen on Receive,
+// letting duplicate deliveries (e.g. a retransmit after a missing ACK) be
+// detected and dropped.
+type dedupRing struct {
+	mu    sync.Mutex
+	seen  map[string]struct{}
+	order [dedupRingSize]string
+	next  int
+	full  bool
+}
+
+// seenOrRecord reports whether id has already been recorded, and records it
+// if not. An empty id is never deduplicated, since not every envelope
+// carries a MessageID.
+func (d *dedupRing) seenOrRecord(id string) bool {
+	if id == "" {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.seen == nil {
+		d.seen = make(map[string]struct{}, dedupRingSize)
+	}
+	if _, ok := d.seen[id]; ok {
+		return true
+	}
+
+	if d.full {
+		delete(d.seen, d.order[d.next])
+	}
+	d.order[d.next] = id
+	d.seen[id] = struct{}{}
+	d.next = (d.next + 1) % dedupRingSize
+	if d.next == 0 {
+		d.full = true
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// TestReceiveDropsDuplicateMessageID injects the same MessageID twice from
+// the server, followed by a distinct message, and verifies the caller only
+// ever observes the message once.
+func TestReceiveDropsDuplicateMessageID(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		env := protocol.Envelope{Type: "test", MessageID: "dup-1"}
+		conn.WriteJSON(&env)
+		conn.WriteJSON(&env) // retransmit of the same message
+		conn.WriteJSON(&protocol.Envelope{Type: "test", MessageID: "dup-2"})
+	}))
+	defer server.Close()
+
+	url := "ws" + server.URL[len("http"):]
+	client := NewClient()
+	if err := client.Dial(url, nil); err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer client.Close()
+
+	first, err := client.Receive()
+	if err != nil {
+		t.Fatalf("Receive (first): %v", err)
+	}
+	if first.MessageID != "dup-1" {
+		t.Fatalf("first message id = %q, want dup-1", first.MessageID)
+	}
+
+	second, err := client.Receive()
+	if err != nil {
+		t.Fatalf("Receive (second): %v", err)
+	}
+	if second.MessageID != "dup-2" {
+		t.Fatalf("second message id = %q, want dup-2 (retransmit of dup-1 should have been dropped)", second.MessageID)
+	}
+
+	if got := client.Metrics().DuplicatesDropped.Load(); got != 1 {
+		t.Errorf("DuplicatesDropped = %d, want 1", got)
+	}
+}
+
+func TestDedupRingSeenOrRecord(t *testing.T) {
+	var d dedupRing
+
+	if d.seenOrRecord("a") {
+		t.Error("first sighting of \"a\" should not be reported as a duplicate")
+	}
+	if !d.seenOrRecord("a") {
+		t.Error("second sighting of \"a\" should be reported as a duplicate")
+	}
+	if d.seenOrRecord("") {
+		t.Error("an empty id should never be treated as a duplicate")
+	}
+}
+
+func TestDedupRingEvictsOldestBeyondCapacity(t *testing.T) {
+	var d dedupRing
+
+	for i := 0; i < dedupRingSize; i++ {
+		id := string(rune('a' + i))
+		if d.seenOrRecord(id) {
+			t.Fatalf("id %q reported as a duplicate on first sighting", id)
+		}
+	}
+
+	// The ring is now full; recording one more id evicts the oldest ("a"),
+	// so it should no longer be considered seen.
+	d.seenOrRecord("overflow")
+	if d.seenOrRecord("a") {
+		t.Error("\"a\" should have been evicted once the ring wrapped")
+	}
+}
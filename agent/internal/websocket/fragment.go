@@ -0,0 +1,101 @@
+package websocket
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// fragmentHeadroom reserves space in each fragment envelope for framing
+// overhead (type, fragment metadata, JSON structure): each fragment
+// carries at most maxMessageBytes-fragmentHeadroom raw payload bytes.
+// With MsgpackCodec, which encodes FragmentChunk as binary, a fragment
+// envelope's marshaled size stays under maxMessageBytes; with JSONCodec,
+// FragmentChunk is base64-encoded (about 4/3 its raw size), so a full
+// fragment's marshaled size exceeds maxMessageBytes.
+const fragmentHeadroom = 256
+
+// fragment splits env's Payload into chunks of at most
+// maxMessageBytes-fragmentHeadroom bytes, returning one Envelope per
+// chunk, all sharing a newly generated FragmentID. It returns env
+// unmodified, as a single-element slice, if the payload does not need
+// splitting.
+func fragment(env *protocol.Envelope, maxMessageBytes int) ([]*protocol.Envelope, error) {
+	chunkSize := maxMessageBytes - fragmentHeadroom
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("websocket: maxMessageBytes %d too small to fragment", maxMessageBytes)
+	}
+	if len(env.Payload) <= chunkSize {
+		return []*protocol.Envelope{env}, nil
+	}
+
+	fragmentID, err := newMessageID()
+	if err != nil {
+		return nil, fmt.Errorf("websocket: generate fragment id: %w", err)
+	}
+
+	total := (len(env.Payload) + chunkSize - 1) / chunkSize
+	fragments := make([]*protocol.Envelope, 0, total)
+	for i := 0; i < total; i++ {
+		start := i * chunkSize
+		end := start + chunkSize
+		if end > len(env.Payload) {
+			end = len(env.Payload)
+		}
+		fragments = append(fragments, &protocol.Envelope{
+			Type:          env.Type,
+			MessageID:     fmt.Sprintf("%s-%d", fragmentID, i),
+			ChannelID:     env.ChannelID,
+			FragmentID:    fragmentID,
+			FragmentIndex: i,
+			FragmentTotal: total,
+			FragmentChunk: append([]byte(nil), env.Payload[start:end]...),
+		})
+	}
+	return fragments, nil
+}
+
+// fragmentAssembler reassembles envelopes fragmented by fragment,
+// buffering chunks by FragmentID until all of a fragment set's siblings
+// have arrived.
+type fragmentAssembler struct {
+	mu      sync.Mutex
+	pending map[string][][]byte
+}
+
+// add records fragment env's chunk. Once every chunk sharing its
+// FragmentID has arrived, add returns the reassembled Envelope (with
+// Payload set to the concatenated chunks) and true; otherwise it returns
+// (nil, false).
+func (a *fragmentAssembler) add(env *protocol.Envelope) (*protocol.Envelope, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.pending == nil {
+		a.pending = make(map[string][][]byte)
+	}
+	chunks, ok := a.pending[env.FragmentID]
+	if !ok {
+		chunks = make([][]byte, env.FragmentTotal)
+	}
+	chunks[env.FragmentIndex] = env.FragmentChunk
+	a.pending[env.FragmentID] = chunks
+
+	for _, c := range chunks {
+		if c == nil {
+			return nil, false
+		}
+	}
+	delete(a.pending, env.FragmentID)
+
+	var payload []byte
+	for _, c := range chunks {
+		payload = append(payload, c...)
+	}
+	return &protocol.Envelope{
+		Type:      env.Type,
+		Payload:   payload,
+		ChannelID: env.ChannelID,
+	}, true
+}
@@ -0,0 +1,106 @@
+package websocket
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestSendWithAutoFragmentSplitsOversizedPayload(t *testing.T) {
+	payload := strings.Repeat("x", 200*1024)
+
+	c := &Client{
+		sendQueue:       make(chan *protocol.Envelope, 16),
+		done:            make(chan struct{}),
+		AutoFragment:    true,
+		MaxMessageBytes: 65536,
+	}
+
+	env, err := protocol.NewEnvelope(protocol.MsgTypeTerminalBell, payload)
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+	if err := c.Send(env); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if got := c.QueueDepth(); got != 4 {
+		t.Fatalf("fragment envelopes sent = %d, want 4", got)
+	}
+
+	var fragments []*protocol.Envelope
+	for i := 0; i < 4; i++ {
+		fragments = append(fragments, <-c.sendQueue)
+	}
+
+	var assembler fragmentAssembler
+	var reassembled *protocol.Envelope
+	for _, f := range fragments {
+		if f.FragmentTotal != 4 {
+			t.Errorf("fragment FragmentTotal = %d, want 4", f.FragmentTotal)
+		}
+		env, complete := assembler.add(f)
+		if complete {
+			reassembled = env
+		}
+	}
+	if reassembled == nil {
+		t.Fatal("fragments never reassembled into a complete envelope")
+	}
+
+	var got string
+	if err := reassembled.Decode(&got); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got != payload {
+		t.Fatalf("reassembled payload does not match original (got %d bytes, want %d)", len(got), len(payload))
+	}
+}
+
+func TestSendWithoutAutoFragmentDoesNotSplit(t *testing.T) {
+	c := &Client{sendQueue: make(chan *protocol.Envelope, 4), done: make(chan struct{})}
+
+	payload := strings.Repeat("x", 200*1024)
+	env, err := protocol.NewEnvelope(protocol.MsgTypeTerminalBell, payload)
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+	if err := c.Send(env); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got := c.QueueDepth(); got != 1 {
+		t.Fatalf("envelopes sent = %d, want 1 (unfragmented)", got)
+	}
+}
+
+func TestFragmentAssemblerHandlesOutOfOrderFragments(t *testing.T) {
+	env, err := protocol.NewEnvelope(protocol.MsgTypeTerminalBell, []byte(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+
+	fragments, err := fragment(env, 8+fragmentHeadroom)
+	if err != nil {
+		t.Fatalf("fragment: %v", err)
+	}
+	if len(fragments) < 2 {
+		t.Fatalf("got %d fragments, want at least 2 to exercise reassembly", len(fragments))
+	}
+
+	var assembler fragmentAssembler
+	for i := len(fragments) - 1; i >= 0; i-- {
+		reassembled, complete := assembler.add(fragments[i])
+		if i == 0 {
+			if !complete {
+				t.Fatal("expected reassembly to complete on the last fragment received")
+			}
+			if !bytes.Equal([]byte(reassembled.Payload), env.Payload) {
+				t.Errorf("reassembled Payload = %s, want %s", reassembled.Payload, env.Payload)
+			}
+		} else if complete {
+			t.Fatalf("reassembly completed early at fragment %d", i)
+		}
+	}
+}
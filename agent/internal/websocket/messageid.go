@@ -0,0 +1,20 @@
+package websocket
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// newMessageID returns a random UUIDv4 string used to tag an outbound
+// Envelope, letting the receiver deduplicate it if it is later
+// retransmitted after a missing ACK.
+func newMessageID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("websocket: generate message id: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,554 @@
+package websocket
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// MsgpackCodec encodes envelopes using the MessagePack binary format
+// instead of JSON, trading readability for a smaller, faster encoding on
+// high-frequency payloads (e.g. metrics). It supports the same struct
+// kinds NewEnvelope's payloads already use: primitives, strings, byte
+// slices, slices, maps, pointers, time.Time, and structs with `json`
+// tags. It intentionally avoids pulling in a third-party MessagePack
+// library for the small, well-defined subset of encodings this transport
+// actually needs.
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) FrameType() int { return websocket.BinaryMessage }
+
+func (MsgpackCodec) Marshal(v any) ([]byte, error) {
+	var buf []byte
+	buf, err := msgpackEncode(buf, reflect.ValueOf(v))
+	if err != nil {
+		return nil, fmt.Errorf("websocket: msgpack marshal: %w", err)
+	}
+	return buf, nil
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("websocket: msgpack unmarshal: destination must be a non-nil pointer")
+	}
+	if _, err := msgpackDecode(data, 0, rv.Elem()); err != nil {
+		return fmt.Errorf("websocket: msgpack unmarshal: %w", err)
+	}
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func msgpackEncode(buf []byte, v reflect.Value) ([]byte, error) {
+	if !v.IsValid() {
+		return append(buf, 0xc0), nil
+	}
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return append(buf, 0xc0), nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		return msgpackEncodeInt(buf, v.Interface().(time.Time).UnixNano()), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(buf, 0xc3), nil
+		}
+		return append(buf, 0xc2), nil
+
+	case reflect.String:
+		return msgpackEncodeString(buf, v.String()), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return msgpackEncodeInt(buf, v.Int()), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return msgpackEncodeUint(buf, v.Uint()), nil
+
+	case reflect.Float32, reflect.Float64:
+		buf = append(buf, 0xcb)
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], math.Float64bits(v.Float()))
+		return append(buf, b[:]...), nil
+
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return msgpackEncodeBin(buf, v.Bytes()), nil
+		}
+		buf = msgpackEncodeArrayHeader(buf, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			var err error
+			buf, err = msgpackEncode(buf, v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+
+	case reflect.Map:
+		keys := v.MapKeys()
+		buf = msgpackEncodeMapHeader(buf, len(keys))
+		for _, k := range keys {
+			var err error
+			buf, err = msgpackEncode(buf, k)
+			if err != nil {
+				return nil, err
+			}
+			buf, err = msgpackEncode(buf, v.MapIndex(k))
+			if err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+
+	case reflect.Struct:
+		fields := msgpackStructFields(v.Type())
+		var names []string
+		var values []reflect.Value
+		for _, f := range fields {
+			fv := v.FieldByIndex(f.index)
+			if f.omitempty && fv.IsZero() {
+				continue
+			}
+			names = append(names, f.name)
+			values = append(values, fv)
+		}
+		buf = msgpackEncodeMapHeader(buf, len(names))
+		for i, name := range names {
+			buf = msgpackEncodeString(buf, name)
+			var err error
+			buf, err = msgpackEncode(buf, values[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported kind %s", v.Kind())
+	}
+}
+
+type msgpackField struct {
+	name      string
+	omitempty bool
+	index     []int
+}
+
+func msgpackStructFields(t reflect.Type) []msgpackField {
+	fields := make([]msgpackField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, msgpackField{
+			name:      name,
+			omitempty: strings.Contains(opts, "omitempty"),
+			index:     sf.Index,
+		})
+	}
+	return fields
+}
+
+func msgpackEncodeString(buf []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		buf = append(buf, 0xa0|byte(n))
+	case n < 1<<8:
+		buf = append(buf, 0xd9, byte(n))
+	case n < 1<<16:
+		buf = append(buf, 0xda, byte(n>>8), byte(n))
+	default:
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(n))
+		buf = append(buf, 0xdb)
+		buf = append(buf, b[:]...)
+	}
+	return append(buf, s...)
+}
+
+func msgpackEncodeBin(buf, data []byte) []byte {
+	n := len(data)
+	switch {
+	case n < 1<<8:
+		buf = append(buf, 0xc4, byte(n))
+	case n < 1<<16:
+		buf = append(buf, 0xc5, byte(n>>8), byte(n))
+	default:
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(n))
+		buf = append(buf, 0xc6)
+		buf = append(buf, b[:]...)
+	}
+	return append(buf, data...)
+}
+
+func msgpackEncodeArrayHeader(buf []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, 0x90|byte(n))
+	case n < 1<<16:
+		return append(buf, 0xdc, byte(n>>8), byte(n))
+	default:
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(n))
+		return append(append(buf, 0xdd), b[:]...)
+	}
+}
+
+func msgpackEncodeMapHeader(buf []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, 0x80|byte(n))
+	case n < 1<<16:
+		return append(buf, 0xde, byte(n>>8), byte(n))
+	default:
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(n))
+		return append(append(buf, 0xdf), b[:]...)
+	}
+}
+
+func msgpackEncodeInt(buf []byte, n int64) []byte {
+	switch {
+	case n >= 0:
+		return msgpackEncodeUint(buf, uint64(n))
+	case n >= -32:
+		return append(buf, byte(n))
+	case n >= math.MinInt8:
+		return append(buf, 0xd0, byte(n))
+	case n >= math.MinInt16:
+		var b [2]byte
+		binary.BigEndian.PutUint16(b[:], uint16(n))
+		return append(append(buf, 0xd1), b[:]...)
+	case n >= math.MinInt32:
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(n))
+		return append(append(buf, 0xd2), b[:]...)
+	default:
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], uint64(n))
+		return append(append(buf, 0xd3), b[:]...)
+	}
+}
+
+func msgpackEncodeUint(buf []byte, n uint64) []byte {
+	switch {
+	case n < 1<<7:
+		return append(buf, byte(n))
+	case n < 1<<8:
+		return append(buf, 0xcc, byte(n))
+	case n < 1<<16:
+		var b [2]byte
+		binary.BigEndian.PutUint16(b[:], uint16(n))
+		return append(append(buf, 0xcd), b[:]...)
+	case n < 1<<32:
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(n))
+		return append(append(buf, 0xce), b[:]...)
+	default:
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], n)
+		return append(append(buf, 0xcf), b[:]...)
+	}
+}
+
+// msgpackDecode reads one MessagePack value from data starting at off into
+// dst, returning the offset just past the value read.
+func msgpackDecode(data []byte, off int, dst reflect.Value) (int, error) {
+	if off >= len(data) {
+		return off, fmt.Errorf("unexpected end of input")
+	}
+
+	for dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		dst = dst.Elem()
+	}
+
+	b := data[off]
+
+	switch {
+	case b == 0xc0:
+		dst.Set(reflect.Zero(dst.Type()))
+		return off + 1, nil
+
+	case b == 0xc2 || b == 0xc3:
+		msgpackSetBool(dst, b == 0xc3)
+		return off + 1, nil
+
+	case b>>7 == 0: // positive fixint
+		msgpackSetInt(dst, int64(b))
+		return off + 1, nil
+
+	case b>>5 == 0x07: // negative fixint
+		msgpackSetInt(dst, int64(int8(b)))
+		return off + 1, nil
+
+	case b>>5 == 0x05: // fixstr
+		n := int(b & 0x1f)
+		return msgpackSetString(dst, data, off+1, n)
+
+	case b>>4 == 0x08: // fixmap
+		n := int(b & 0x0f)
+		return msgpackDecodeMap(data, off+1, n, dst)
+
+	case b>>4 == 0x09: // fixarray
+		n := int(b & 0x0f)
+		return msgpackDecodeArray(data, off+1, n, dst)
+
+	default:
+		return msgpackDecodeExt(data, off, b, dst)
+	}
+}
+
+func msgpackSetBool(dst reflect.Value, b bool) {
+	if dst.Kind() == reflect.Interface {
+		dst.Set(reflect.ValueOf(b))
+		return
+	}
+	dst.SetBool(b)
+}
+
+func msgpackSetInt(dst reflect.Value, n int64) {
+	if dst.Type() == timeType {
+		dst.Set(reflect.ValueOf(time.Unix(0, n).UTC()))
+		return
+	}
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		dst.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		dst.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		dst.SetFloat(float64(n))
+	case reflect.Interface:
+		dst.Set(reflect.ValueOf(n))
+	}
+}
+
+func msgpackSetString(dst reflect.Value, data []byte, off, n int) (int, error) {
+	if off+n > len(data) {
+		return off, fmt.Errorf("string overruns input")
+	}
+	s := string(data[off : off+n])
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(s)
+	case reflect.Interface:
+		dst.Set(reflect.ValueOf(s))
+	}
+	return off + n, nil
+}
+
+func msgpackDecodeMap(data []byte, off, n int, dst reflect.Value) (int, error) {
+	switch {
+	case dst.Kind() == reflect.Struct:
+		fields := msgpackStructFields(dst.Type())
+		byName := make(map[string][]int, len(fields))
+		for _, f := range fields {
+			byName[f.name] = f.index
+		}
+		for i := 0; i < n; i++ {
+			var key string
+			var err error
+			off, err = msgpackDecode(data, off, reflect.ValueOf(&key).Elem())
+			if err != nil {
+				return off, err
+			}
+			idx, ok := byName[key]
+			if !ok {
+				off, err = msgpackSkip(data, off)
+				if err != nil {
+					return off, err
+				}
+				continue
+			}
+			off, err = msgpackDecode(data, off, dst.FieldByIndex(idx))
+			if err != nil {
+				return off, err
+			}
+		}
+		return off, nil
+	case dst.Kind() == reflect.Map:
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(dst.Type(), n))
+		}
+		kt, vt := dst.Type().Key(), dst.Type().Elem()
+		for i := 0; i < n; i++ {
+			kv := reflect.New(kt).Elem()
+			var err error
+			off, err = msgpackDecode(data, off, kv)
+			if err != nil {
+				return off, err
+			}
+			vv := reflect.New(vt).Elem()
+			off, err = msgpackDecode(data, off, vv)
+			if err != nil {
+				return off, err
+			}
+			dst.SetMapIndex(kv, vv)
+		}
+		return off, nil
+	default:
+		for i := 0; i < n; i++ {
+			var err error
+			off, err = msgpackSkip(data, off)
+			if err != nil {
+				return off, err
+			}
+			off, err = msgpackSkip(data, off)
+			if err != nil {
+				return off, err
+			}
+		}
+		return off, nil
+	}
+}
+
+func msgpackDecodeArray(data []byte, off, n int, dst reflect.Value) (int, error) {
+	switch dst.Kind() {
+	case reflect.Slice:
+		dst.Set(reflect.MakeSlice(dst.Type(), n, n))
+		for i := 0; i < n; i++ {
+			var err error
+			off, err = msgpackDecode(data, off, dst.Index(i))
+			if err != nil {
+				return off, err
+			}
+		}
+		return off, nil
+	case reflect.Array:
+		for i := 0; i < n && i < dst.Len(); i++ {
+			var err error
+			off, err = msgpackDecode(data, off, dst.Index(i))
+			if err != nil {
+				return off, err
+			}
+		}
+		return off, nil
+	default:
+		for i := 0; i < n; i++ {
+			var err error
+			off, err = msgpackSkip(data, off)
+			if err != nil {
+				return off, err
+			}
+		}
+		return off, nil
+	}
+}
+
+func msgpackDecodeExt(data []byte, off int, b byte, dst reflect.Value) (int, error) {
+	switch b {
+	case 0xcc:
+		msgpackSetInt(dst, int64(data[off+1]))
+		return off + 2, nil
+	case 0xcd:
+		msgpackSetInt(dst, int64(binary.BigEndian.Uint16(data[off+1:])))
+		return off + 3, nil
+	case 0xce:
+		msgpackSetInt(dst, int64(binary.BigEndian.Uint32(data[off+1:])))
+		return off + 5, nil
+	case 0xcf:
+		msgpackSetInt(dst, int64(binary.BigEndian.Uint64(data[off+1:])))
+		return off + 9, nil
+	case 0xd0:
+		msgpackSetInt(dst, int64(int8(data[off+1])))
+		return off + 2, nil
+	case 0xd1:
+		msgpackSetInt(dst, int64(int16(binary.BigEndian.Uint16(data[off+1:]))))
+		return off + 3, nil
+	case 0xd2:
+		msgpackSetInt(dst, int64(int32(binary.BigEndian.Uint32(data[off+1:]))))
+		return off + 5, nil
+	case 0xd3:
+		msgpackSetInt(dst, int64(binary.BigEndian.Uint64(data[off+1:])))
+		return off + 9, nil
+	case 0xcb:
+		bits := binary.BigEndian.Uint64(data[off+1:])
+		f := math.Float64frombits(bits)
+		if dst.Kind() == reflect.Float32 || dst.Kind() == reflect.Float64 {
+			dst.SetFloat(f)
+		} else if dst.Kind() == reflect.Interface {
+			dst.Set(reflect.ValueOf(f))
+		}
+		return off + 9, nil
+	case 0xd9:
+		n := int(data[off+1])
+		return msgpackSetString(dst, data, off+2, n)
+	case 0xda:
+		n := int(binary.BigEndian.Uint16(data[off+1:]))
+		return msgpackSetString(dst, data, off+3, n)
+	case 0xdb:
+		n := int(binary.BigEndian.Uint32(data[off+1:]))
+		return msgpackSetString(dst, data, off+5, n)
+	case 0xc4:
+		n := int(data[off+1])
+		return msgpackSetBytes(dst, data, off+2, n)
+	case 0xc5:
+		n := int(binary.BigEndian.Uint16(data[off+1:]))
+		return msgpackSetBytes(dst, data, off+3, n)
+	case 0xc6:
+		n := int(binary.BigEndian.Uint32(data[off+1:]))
+		return msgpackSetBytes(dst, data, off+5, n)
+	case 0xdc:
+		n := int(binary.BigEndian.Uint16(data[off+1:]))
+		return msgpackDecodeArray(data, off+3, n, dst)
+	case 0xdd:
+		n := int(binary.BigEndian.Uint32(data[off+1:]))
+		return msgpackDecodeArray(data, off+5, n, dst)
+	case 0xde:
+		n := int(binary.BigEndian.Uint16(data[off+1:]))
+		return msgpackDecodeMap(data, off+3, n, dst)
+	case 0xdf:
+		n := int(binary.BigEndian.Uint32(data[off+1:]))
+		return msgpackDecodeMap(data, off+5, n, dst)
+	default:
+		return off, fmt.Errorf("unsupported msgpack tag 0x%x", b)
+	}
+}
+
+func msgpackSetBytes(dst reflect.Value, data []byte, off, n int) (int, error) {
+	if off+n > len(data) {
+		return off, fmt.Errorf("bin overruns input")
+	}
+	b := make([]byte, n)
+	copy(b, data[off:off+n])
+	if dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8 {
+		dst.SetBytes(b)
+	} else if dst.Kind() == reflect.Interface {
+		dst.Set(reflect.ValueOf(b))
+	}
+	return off + n, nil
+}
+
+// msgpackSkip advances past one encoded value without decoding it, used to
+// skip a struct field with no matching destination.
+func msgpackSkip(data []byte, off int) (int, error) {
+	var v any
+	return msgpackDecode(data, off, reflect.ValueOf(&v).Elem())
+}
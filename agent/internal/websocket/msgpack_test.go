@@ -0,0 +1,178 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/pacphi/sindri/agent/internal/metrics"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestMsgpackCodecFrameTypeIsBinary(t *testing.T) {
+	if got := (MsgpackCodec{}).FrameType(); got != websocket.BinaryMessage {
+		t.Errorf("FrameType() = %d, want BinaryMessage", got)
+	}
+}
+
+func TestMsgpackCodecRoundTripsEnvelope(t *testing.T) {
+	want := &protocol.Envelope{
+		Type:          protocol.MsgTypeTerminalBell,
+		Payload:       []byte(`{"session_id":"sess-1"}`),
+		MessageID:     "msg-1",
+		ChannelID:     "chan-1",
+		FragmentIndex: 2,
+		FragmentTotal: 4,
+		SeqNum:        42,
+	}
+
+	codec := MsgpackCodec{}
+	data, err := codec.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got protocol.Envelope
+	if err := codec.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got.Type != want.Type || got.MessageID != want.MessageID || got.ChannelID != want.ChannelID ||
+		got.FragmentIndex != want.FragmentIndex || got.FragmentTotal != want.FragmentTotal || got.SeqNum != want.SeqNum {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+	if string(got.Payload) != string(want.Payload) {
+		t.Errorf("Payload = %q, want %q", got.Payload, want.Payload)
+	}
+}
+
+func TestMsgpackCodecRoundTripsMetricsPayload(t *testing.T) {
+	want := &metrics.MetricsPayload{
+		Timestamp:        time.Unix(1700000000, 0).UTC(),
+		CPUPercent:       42.5,
+		MemoryUsedBytes:  1024,
+		MemoryTotalBytes: 2048,
+		DiskUsedBytes:    4096,
+		DiskTotalBytes:   8192,
+		CustomMetrics:    map[string]float64{"queue_depth": 3.0},
+	}
+
+	codec := MsgpackCodec{}
+	data, err := codec.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got metrics.MetricsPayload
+	if err := codec.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if !got.Timestamp.Equal(want.Timestamp) {
+		t.Errorf("Timestamp = %v, want %v", got.Timestamp, want.Timestamp)
+	}
+	if got.CPUPercent != want.CPUPercent || got.MemoryUsedBytes != want.MemoryUsedBytes {
+		t.Errorf("got = %+v, want %+v", got, want)
+	}
+	if got.CustomMetrics["queue_depth"] != 3.0 {
+		t.Errorf("CustomMetrics[queue_depth] = %v, want 3.0", got.CustomMetrics["queue_depth"])
+	}
+}
+
+func TestMsgpackCodecRoundTripsSliceAndNestedStruct(t *testing.T) {
+	want := &metrics.MetricsPayload{
+		IOStats: []metrics.DiskIOStats{
+			{Device: "sda"},
+			{Device: "sdb"},
+		},
+	}
+
+	codec := MsgpackCodec{}
+	data, err := codec.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got metrics.MetricsPayload
+	if err := codec.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(got.IOStats) != 2 || got.IOStats[0].Device != "sda" || got.IOStats[1].Device != "sdb" {
+		t.Errorf("IOStats = %+v, want 2 entries [sda sdb]", got.IOStats)
+	}
+}
+
+func BenchmarkJSONCodecMarshalMetricsPayload(b *testing.B) {
+	payload := benchmarkMetricsPayload()
+	codec := JSONCodec{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := codec.Marshal(payload); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMsgpackCodecMarshalMetricsPayload(b *testing.B) {
+	payload := benchmarkMetricsPayload()
+	codec := MsgpackCodec{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := codec.Marshal(payload); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkJSONCodecRoundTripMetricsPayload(b *testing.B) {
+	payload := benchmarkMetricsPayload()
+	codec := JSONCodec{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		data, err := codec.Marshal(payload)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var out metrics.MetricsPayload
+		if err := codec.Unmarshal(data, &out); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMsgpackCodecRoundTripMetricsPayload(b *testing.B) {
+	payload := benchmarkMetricsPayload()
+	codec := MsgpackCodec{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		data, err := codec.Marshal(payload)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var out metrics.MetricsPayload
+		if err := codec.Unmarshal(data, &out); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkMetricsPayload() *metrics.MetricsPayload {
+	return &metrics.MetricsPayload{
+		Timestamp:        time.Unix(1700000000, 0).UTC(),
+		CPUPercent:       37.2,
+		MemoryUsedBytes:  4 * 1024 * 1024 * 1024,
+		MemoryTotalBytes: 16 * 1024 * 1024 * 1024,
+		DiskUsedBytes:    100 * 1024 * 1024 * 1024,
+		DiskTotalBytes:   500 * 1024 * 1024 * 1024,
+		IOStats: []metrics.DiskIOStats{
+			{Device: "sda"},
+			{Device: "nvme0n1"},
+		},
+		CustomMetrics: map[string]float64{
+			"queue_depth":  3.0,
+			"cache_hit_%":  92.5,
+			"open_handles": 128,
+		},
+	}
+}
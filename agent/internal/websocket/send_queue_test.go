@@ -0,0 +1,65 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+func TestSendReturnsErrSendQueueFullWhenSaturated(t *testing.T) {
+	c := &Client{sendQueue: make(chan *protocol.Envelope, 2), done: make(chan struct{})}
+	env := &protocol.Envelope{Type: protocol.MsgTypeTerminalBell}
+
+	if err := c.Send(env); err != nil {
+		t.Fatalf("Send 1: %v", err)
+	}
+	if err := c.Send(env); err != nil {
+		t.Fatalf("Send 2: %v", err)
+	}
+	if c.QueueDepth() != 2 {
+		t.Fatalf("QueueDepth = %d, want 2", c.QueueDepth())
+	}
+
+	if err := c.Send(env); err != ErrSendQueueFull {
+		t.Fatalf("Send 3 = %v, want ErrSendQueueFull", err)
+	}
+}
+
+func TestSendAssignsMonotonicallyIncreasingSeqNum(t *testing.T) {
+	c := &Client{sendQueue: make(chan *protocol.Envelope, 3), done: make(chan struct{})}
+
+	for i := 0; i < 3; i++ {
+		if err := c.Send(&protocol.Envelope{Type: protocol.MsgTypeTerminalBell}); err != nil {
+			t.Fatalf("Send %d: %v", i, err)
+		}
+	}
+
+	var prev uint64
+	for i := 0; i < 3; i++ {
+		env := <-c.sendQueue
+		if i > 0 && env.SeqNum <= prev {
+			t.Errorf("envelope %d: SeqNum = %d, want greater than previous %d", i, env.SeqNum, prev)
+		}
+		prev = env.SeqNum
+	}
+}
+
+func TestSendWithRetryWaitsForRoom(t *testing.T) {
+	c := &Client{sendQueue: make(chan *protocol.Envelope, 1), done: make(chan struct{})}
+	env := &protocol.Envelope{Type: protocol.MsgTypeTerminalBell}
+
+	if err := c.Send(env); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		<-c.sendQueue
+		c.depth.Add(-1)
+	}()
+
+	if err := c.SendWithRetry(env, SendOptions{RetryTimeout: time.Second}); err != nil {
+		t.Fatalf("SendWithRetry: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package websocket
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+)
+
+// LoadTLSConfig builds the *tls.Config to assign to Client.TLSConfig
+// before Dial, for connecting to a Console that requires mutual TLS.
+//
+// certFile and keyFile, if both set, are loaded as the client
+// certificate presented during the handshake (SINDRI_AGENT_TLS_CERT,
+// SINDRI_AGENT_TLS_KEY). caFile, if set, is used as the sole trusted
+// root for verifying the Console's certificate instead of the system
+// pool (SINDRI_AGENT_TLS_CA) — needed for a self-hosted Console with a
+// private CA. insecureSkipVerify disables verification of the Console's
+// certificate entirely (SINDRI_AGENT_TLS_SKIP_VERIFY) and should only
+// ever be used against a development Console; LoadTLSConfig logs a loud
+// warning whenever it's true.
+//
+// All arguments may be zero-valued, in which case LoadTLSConfig returns
+// an empty, non-nil *tls.Config — safe to assign to Client.TLSConfig
+// unconditionally.
+func LoadTLSConfig(certFile, keyFile, caFile string, insecureSkipVerify bool) (*tls.Config, error) {
+	cfg := &tls.Config{}
+
+	if certFile != "" && keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("websocket: load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("websocket: read CA file %s: %w", caFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("websocket: no PEM certificates found in CA file %s", caFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if insecureSkipVerify {
+		log.Print("websocket: SINDRI_AGENT_TLS_SKIP_VERIFY is enabled — the Console's TLS certificate will NOT be verified, do not use outside development")
+		cfg.InsecureSkipVerify = true
+	}
+
+	return cfg, nil
+}
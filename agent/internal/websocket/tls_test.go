@@ -0,0 +1,167 @@
+package websocket
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newClientCertForCN generates a self-signed certificate valid for
+// client authentication, unlike newCertForCN's server-only cert, so it
+// passes a server's tls.RequireAndVerifyClientCert check.
+func newClientCertForCN(t *testing.T, cn string) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		Leaf:        cert,
+	}
+}
+
+// writeCertAndKeyFiles PEM-encodes cert to certPath and its ECDSA private
+// key to keyPath, for use with LoadTLSConfig's tls.LoadX509KeyPair.
+func writeCertAndKeyFiles(t *testing.T, cert tls.Certificate, certPath, keyPath string) {
+	t.Helper()
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	if err := os.WriteFile(certPath, certPEM, 0o600); err != nil {
+		t.Fatalf("write cert file: %v", err)
+	}
+
+	keyBytes, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes})
+	if err := os.WriteFile(keyPath, keyPEM, 0o600); err != nil {
+		t.Fatalf("write key file: %v", err)
+	}
+}
+
+func TestLoadTLSConfigDialsWithClientCertAndCustomCA(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}))
+
+	serverCert := newCertForCN(t, "console.internal")
+	clientCert := newClientCertForCN(t, "agent-1")
+
+	clientCAPool := x509.NewCertPool()
+	clientCAPool.AddCert(clientCert.Leaf)
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAPool,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "client.pem")
+	keyPath := filepath.Join(dir, "client-key.pem")
+	writeCertAndKeyFiles(t, clientCert, certPath, keyPath)
+
+	caPath := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: serverCert.Certificate[0]}), 0o600); err != nil {
+		t.Fatalf("write CA file: %v", err)
+	}
+
+	tlsConfig, err := LoadTLSConfig(certPath, keyPath, caPath, false)
+	if err != nil {
+		t.Fatalf("LoadTLSConfig: %v", err)
+	}
+
+	client := &Client{
+		TLSServerName: "console.internal",
+		TLSConfig:     tlsConfig,
+	}
+	url := "wss://" + server.Listener.Addr().String() + "/"
+	if err := client.Dial(url, nil); err != nil {
+		t.Fatalf("Dial with client certificate: %v", err)
+	}
+	defer client.Close()
+}
+
+func TestLoadTLSConfigSkipVerifyDialsWithoutCAFile(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{newCertForCN(t, "console.internal")}}
+	server.StartTLS()
+	defer server.Close()
+
+	tlsConfig, err := LoadTLSConfig("", "", "", true)
+	if err != nil {
+		t.Fatalf("LoadTLSConfig: %v", err)
+	}
+	if !tlsConfig.InsecureSkipVerify {
+		t.Error("InsecureSkipVerify = false, want true")
+	}
+
+	client := &Client{TLSConfig: tlsConfig}
+	url := "wss://" + server.Listener.Addr().String() + "/"
+	if err := client.Dial(url, nil); err != nil {
+		t.Fatalf("Dial with skip-verify: %v", err)
+	}
+	defer client.Close()
+}
+
+func TestLoadTLSConfigReturnsErrorForMissingCertFile(t *testing.T) {
+	if _, err := LoadTLSConfig("/nonexistent/cert.pem", "/nonexistent/key.pem", "", false); err == nil {
+		t.Error("expected an error for a missing client certificate file")
+	}
+}
+
+func TestLoadTLSConfigReturnsErrorForMissingCAFile(t *testing.T) {
+	if _, err := LoadTLSConfig("", "", "/nonexistent/ca.pem", false); err == nil {
+		t.Error("expected an error for a missing CA file")
+	}
+}
@@ -0,0 +1,496 @@
+// Command sindri-agent runs on a provisioned instance and bridges it to the
+// mimir Console over a persistent WebSocket (or gRPC) connection.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/config"
+	"github.com/pacphi/sindri/agent/internal/crashreport"
+	"github.com/pacphi/sindri/agent/internal/errorreport"
+	"github.com/pacphi/sindri/agent/internal/heartbeat"
+	"github.com/pacphi/sindri/agent/internal/labels"
+	"github.com/pacphi/sindri/agent/internal/metrics"
+	"github.com/pacphi/sindri/agent/internal/promexport"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+	"github.com/pacphi/sindri/agent/internal/terminal"
+)
+
+// fallbackTickInterval is used for the heartbeat and metrics tickers when
+// cfg.MetricsInterval is unset (e.g. a Config built directly rather than
+// via config.Load, as in tests).
+const fallbackTickInterval = 60 * time.Second
+
+// defaultGCInterval is used for the forced-GC ticker in Agent.Run when
+// cfg.GCInterval is unset (e.g. a Config built directly rather than via
+// config.Load, as in tests).
+const defaultGCInterval = 5 * time.Minute
+
+// gitCommit and buildDate identify the exact build running, set at build
+// time via e.g. -ldflags "-X main.gitCommit=abc123 -X main.buildDate=2025-01-01".
+// Both are empty on a plain "go build".
+var (
+	gitCommit string
+	buildDate string
+)
+
+// HeartbeatSender delivers a single HeartbeatPayload to the Console, e.g.
+// over the active WebSocket connection.
+type HeartbeatSender interface {
+	SendHeartbeat(payload heartbeat.HeartbeatPayload) error
+}
+
+// MetricsSender delivers a single MetricsPayload to the Console.
+type MetricsSender interface {
+	SendMetrics(payload *metrics.MetricsPayload) error
+}
+
+// ErrorSender delivers a single MsgReportError to the Console.
+type ErrorSender interface {
+	SendError(msg protocol.MsgReportError) error
+}
+
+// MetricsBatchSender delivers a MsgMetricsBatch to the Console, used
+// instead of MetricsSender when cfg.MetricsPushBatchSize is greater than
+// 1.
+type MetricsBatchSender interface {
+	SendMetricsBatch(msg protocol.MsgMetricsBatch) error
+}
+
+// DiagnosticsSender delivers a single MsgDiagnostics to the Console.
+type DiagnosticsSender interface {
+	SendDiagnostics(msg protocol.MsgDiagnostics) error
+}
+
+// MetricsCollector collects a single MetricsPayload from the host. It is
+// satisfied by *metrics.Collector; tests substitute a fake (e.g. one that
+// always fails) via WithMetricsCollector.
+type MetricsCollector interface {
+	Collect() (*metrics.MetricsPayload, error)
+}
+
+// PromExporter receives every collected MetricsPayload for exposition via
+// promexport.Exporter's local Prometheus endpoint. It is satisfied by
+// *promexport.Exporter.
+type PromExporter interface {
+	UpdateSnapshot(payload *metrics.MetricsPayload)
+}
+
+// AgentOption configures optional Agent behavior at construction time.
+type AgentOption func(*Agent)
+
+// WithHeartbeatSender makes Run deliver each built HeartbeatPayload to
+// sender on every heartbeat tick.
+func WithHeartbeatSender(sender HeartbeatSender) AgentOption {
+	return func(a *Agent) { a.heartbeatSender = sender }
+}
+
+// WithMetricsSender makes Run deliver each collected MetricsPayload to
+// sender on every metrics tick.
+func WithMetricsSender(sender MetricsSender) AgentOption {
+	return func(a *Agent) { a.metricsSender = sender }
+}
+
+// WithMetricsBatchSender makes Run deliver a MsgMetricsBatch to sender
+// whenever cfg.MetricsPushBatchSize payloads have accumulated. Ignored
+// unless MetricsPushBatchSize is greater than 1.
+func WithMetricsBatchSender(sender MetricsBatchSender) AgentOption {
+	return func(a *Agent) { a.metricsBatchSender = sender }
+}
+
+// WithErrorSender makes Run deliver a MsgReportError to sender whenever a
+// non-fatal error occurs (e.g. a failed metrics collection or heartbeat
+// send), subject to errorreport.Reporter's deduplication window.
+func WithErrorSender(sender ErrorSender) AgentOption {
+	return func(a *Agent) { a.errorSender = sender }
+}
+
+// WithMetricsCollector overrides the Agent's metrics collector, e.g. with a
+// fake that always fails, for testing error-reporting behavior.
+func WithMetricsCollector(collector MetricsCollector) AgentOption {
+	return func(a *Agent) { a.metrics = collector }
+}
+
+// WithDiagnosticsSender makes Run deliver a MsgDiagnostics to sender on
+// every cfg.GCInterval tick, right after forcing a GC cycle.
+func WithDiagnosticsSender(sender DiagnosticsSender) AgentOption {
+	return func(a *Agent) { a.diagnosticsSender = sender }
+}
+
+// WithPromExporter makes Run hand every collected MetricsPayload to
+// exporter, in addition to whatever MetricsSender or MetricsBatchSender is
+// configured.
+func WithPromExporter(exporter PromExporter) AgentOption {
+	return func(a *Agent) { a.promExporter = exporter }
+}
+
+// Agent encapsulates the running agent's state: its configuration, active
+// terminal sessions, metrics collector, and heartbeat builder. Wrapping
+// this in a struct (rather than package-level state in main) lets the run
+// loop be exercised in unit tests without spawning a real process.
+type Agent struct {
+	cfg                *config.Config
+	terminals          *terminal.Manager
+	metrics            MetricsCollector
+	metricsDiff        *metrics.DiffTracker
+	metricsBatch       *metrics.BatchTracker
+	labels             *labels.Store
+	heartbeat          *heartbeat.Manager
+	errorReporter      *errorreport.Reporter
+	heartbeatSender    HeartbeatSender
+	metricsSender      MetricsSender
+	metricsBatchSender MetricsBatchSender
+	errorSender        ErrorSender
+	diagnosticsSender  DiagnosticsSender
+	promExporter       PromExporter
+
+	// reloadInterval carries a new metrics/heartbeat tick period from
+	// ApplyConfigDelta to Run, which resets its tickers on receipt.
+	// Buffered by one, since only the most recent reload matters.
+	reloadInterval chan time.Duration
+
+	// suspended pauses Run's heartbeat and metrics ticks, and the
+	// terminal Manager's input handling, while true (see Suspend). The
+	// ticker goroutines in Run keep running so activity resumes on the
+	// very next tick after Resume, with no restart delay.
+	suspended atomic.Bool
+
+	mu          sync.Mutex
+	lastMetrics *metrics.MetricsPayload
+}
+
+// NewAgent constructs an Agent from cfg.
+func NewAgent(cfg *config.Config, opts ...AgentOption) *Agent {
+	collector := metrics.NewCollectorWithCPUSample(cfg.CPUSampleDuration)
+	if cfg.DiskIOStatsEnabled {
+		collector.EnableDiskIOStats()
+	}
+	if len(cfg.NetworkInterfaces) > 0 {
+		collector.SetNetworkInterfaceAllowlist(cfg.NetworkInterfaces)
+	}
+	if cfg.TopProcessesCount > 0 {
+		collector.EnableTopProcesses(cfg.TopProcessesCount)
+	}
+
+	a := &Agent{
+		cfg:            cfg,
+		terminals:      terminal.NewManager(),
+		metrics:        collector,
+		metricsDiff:    metrics.NewDiffTracker(0),
+		labels:         labels.NewStore(),
+		errorReporter:  errorreport.NewReporter(),
+		reloadInterval: make(chan time.Duration, 1),
+	}
+	if cfg.MetricsPushBatchSize > 1 {
+		a.metricsBatch = metrics.NewBatchTracker(cfg.MetricsPushBatchSize)
+	}
+	a.heartbeat = heartbeat.NewManager(
+		heartbeat.WithMetricsSource(a.lastMetricsSnapshot),
+		heartbeat.WithDiskPressurePct(cfg.HBDiskPressurePct),
+		heartbeat.WithMemoryPressurePct(cfg.HBMemPressurePct),
+		heartbeat.WithEnvironment(cfg.Environment),
+		heartbeat.WithIncludeMetricsSummary(cfg.HBIncludeMetrics),
+		heartbeat.WithLabelsSource(a.labels.Snapshot),
+	)
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Suspend pauses heartbeat, metrics, and terminal input activity — e.g.
+// while the instance is being snapshotted or migrated — until Resume is
+// called. reason is logged but otherwise unused.
+func (a *Agent) Suspend(reason string) {
+	a.suspended.Store(true)
+	a.terminals.Suspend()
+	log.Printf("sindri-agent: suspended (reason=%q)", reason)
+}
+
+// Resume restores activity paused by Suspend.
+func (a *Agent) Resume() {
+	a.suspended.Store(false)
+	a.terminals.Resume()
+	log.Print("sindri-agent: resumed")
+}
+
+// buildPayload returns the HeartbeatPayload the agent would send to the
+// Console right now.
+func (a *Agent) buildPayload() heartbeat.HeartbeatPayload {
+	return a.heartbeat.Build()
+}
+
+// buildStateSync returns the StateSyncPayload the agent would send to the
+// Console right now.
+func (a *Agent) buildStateSync(agentVersion, hostname string) protocol.StateSyncPayload {
+	return protocol.StateSyncPayload{
+		AgentVersion: agentVersion,
+		Hostname:     hostname,
+		Environment:  a.cfg.Environment,
+		GitCommit:    a.cfg.GitCommit,
+		BuildDate:    a.cfg.BuildDate,
+		Labels:       a.labels.Snapshot(),
+	}
+}
+
+// reportError builds a MsgReportError via a.errorReporter (which suppresses
+// duplicates of the same component+code within its dedupe window) and, if
+// one is returned and an ErrorSender is configured, delivers it to the
+// Console. Send failures are only logged — reporting errors must never
+// itself be a source of fatal failures.
+func (a *Agent) reportError(component, code, message string, err error) {
+	if a.errorSender == nil {
+		return
+	}
+	msg := a.errorReporter.Report(component, code, message, err, nil)
+	if msg == nil {
+		return
+	}
+	if sendErr := a.errorSender.SendError(*msg); sendErr != nil {
+		log.Printf("sindri-agent: send error report: %v", sendErr)
+	}
+}
+
+// lastMetricsSnapshot returns the most recently collected MetricsPayload,
+// or nil if none has been collected yet. It is passed to
+// heartbeat.WithMetricsSource so the heartbeat manager can compute pressure
+// flags without polling the host itself.
+func (a *Agent) lastMetricsSnapshot() *metrics.MetricsPayload {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.lastMetrics
+}
+
+// tickInterval returns the configured metrics/heartbeat tick period, or
+// fallbackTickInterval if cfg.MetricsInterval is unset (e.g. a Config built
+// directly rather than via config.Load, as in tests).
+func (a *Agent) tickInterval() time.Duration {
+	if a.cfg.MetricsInterval > 0 {
+		return a.cfg.MetricsInterval
+	}
+	return fallbackTickInterval
+}
+
+// ApplyConfigDelta applies the reloadable fields of delta (see
+// config.ConfigDelta) to the running agent — currently Tags, taken up
+// immediately, and MetricsInterval, which additionally resets Run's
+// heartbeat and metrics tickers so the new period takes effect on the
+// next tick rather than after up to one stale interval. Fields in
+// delta.RestartRequired are not applied; config.Watcher has already
+// logged a warning for those.
+func (a *Agent) ApplyConfigDelta(delta config.ConfigDelta) {
+	for _, field := range delta.Changed {
+		switch field {
+		case "Tags":
+			a.mu.Lock()
+			a.cfg.Tags = delta.Config.Tags
+			a.mu.Unlock()
+		case "MetricsInterval":
+			a.mu.Lock()
+			a.cfg.MetricsInterval = delta.Config.MetricsInterval
+			a.mu.Unlock()
+			select {
+			case a.reloadInterval <- delta.Config.MetricsInterval:
+			default:
+			}
+		}
+	}
+}
+
+// gcInterval returns the configured forced-GC period, or defaultGCInterval
+// if cfg.GCInterval is unset (e.g. a Config built directly rather than via
+// config.Load, as in tests).
+func (a *Agent) gcInterval() time.Duration {
+	if a.cfg.GCInterval > 0 {
+		return a.cfg.GCInterval
+	}
+	return defaultGCInterval
+}
+
+// Run starts the agent's main loop, ticking heartbeat and metrics sends
+// until ctx is canceled. While the agent is suspended (see Suspend), ticks
+// are skipped rather than the tickers being stopped, so activity resumes on
+// the very next tick after Resume. A separate, suspend-independent ticker
+// forces a GC cycle every cfg.GCInterval to bound heap fragmentation from
+// terminal I/O buffers on a long-running agent, reporting the resulting
+// runtime.MemStats via DiagnosticsSender if one is configured.
+func (a *Agent) Run(ctx context.Context) error {
+	interval := a.tickInterval()
+	heartbeatTicker := time.NewTicker(interval)
+	defer heartbeatTicker.Stop()
+	metricsTicker := time.NewTicker(interval)
+	defer metricsTicker.Stop()
+	gcTicker := time.NewTicker(a.gcInterval())
+	defer gcTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case interval := <-a.reloadInterval:
+			heartbeatTicker.Reset(interval)
+			metricsTicker.Reset(interval)
+			log.Printf("sindri-agent: reloaded metrics_interval=%s", interval)
+
+		case <-gcTicker.C:
+			runtime.GC()
+			if a.diagnosticsSender != nil {
+				if err := a.diagnosticsSender.SendDiagnostics(protocol.NewDiagnostics()); err != nil {
+					log.Printf("sindri-agent: send diagnostics: %v", err)
+				}
+			}
+
+		case <-heartbeatTicker.C:
+			if a.suspended.Load() || a.heartbeatSender == nil {
+				continue
+			}
+			if err := a.heartbeatSender.SendHeartbeat(a.buildPayload()); err != nil {
+				log.Printf("sindri-agent: send heartbeat: %v", err)
+				a.reportError("heartbeat", protocol.ErrCodeHeartbeatSendFailed, "send heartbeat", err)
+			}
+
+		case <-metricsTicker.C:
+			if a.suspended.Load() {
+				continue
+			}
+			payload, err := a.metrics.Collect()
+			if err != nil {
+				log.Printf("sindri-agent: collect metrics: %v", err)
+				a.reportError("metrics", protocol.ErrCodeMetricsCollectFailed, "collect metrics", err)
+				continue
+			}
+			a.mu.Lock()
+			a.lastMetrics = payload
+			a.mu.Unlock()
+			if a.promExporter != nil {
+				a.promExporter.UpdateSnapshot(payload)
+			}
+			diffed := a.metricsDiff.Next(payload)
+
+			if a.metricsBatch != nil {
+				batch, ready := a.metricsBatch.Add(diffed)
+				if !ready || a.metricsBatchSender == nil {
+					continue
+				}
+				if err := a.metricsBatchSender.SendMetricsBatch(protocol.MsgMetricsBatch{Payloads: batch}); err != nil {
+					log.Printf("sindri-agent: send metrics batch: %v", err)
+				}
+				continue
+			}
+
+			if a.metricsSender == nil {
+				continue
+			}
+			if err := a.metricsSender.SendMetrics(diffed); err != nil {
+				log.Printf("sindri-agent: send metrics: %v", err)
+			}
+		}
+	}
+}
+
+// applyRuntimeTuning applies cfg's Go runtime GC settings, if configured,
+// before any subsystem starts. cfg.GCInterval is applied by Agent.Run
+// itself via a periodic runtime.GC (see Agent.Run's gcTicker); this only
+// covers the one-shot debug settings.
+func applyRuntimeTuning(cfg *config.Config) {
+	if cfg.GOGC != nil {
+		prev := debug.SetGCPercent(*cfg.GOGC)
+		log.Printf("sindri-agent: GOGC=%d (was %d)", *cfg.GOGC, prev)
+	}
+	if cfg.GOMEMLIMIT != nil {
+		prev := debug.SetMemoryLimit(*cfg.GOMEMLIMIT)
+		log.Printf("sindri-agent: GOMEMLIMIT=%d bytes (was %d)", *cfg.GOMEMLIMIT, prev)
+	}
+}
+
+// run starts the agent and blocks until it exits. If cfg.CoreDumpOnPanic is
+// set, an unhandled panic is captured (stack trace plus logs's recent
+// output), uploaded to the Console, and then re-raised so the process still
+// crashes and exits non-zero as before. If cfg.PromAddr is set, a
+// promexport.Exporter is started alongside the agent, sharing the same
+// root context so it shuts down gracefully on SIGTERM/SIGINT. A
+// config.Watcher listens for SIGHUP for the agent's whole lifetime,
+// reloading Tags and MetricsInterval into the running agent without
+// dropping its active terminal sessions; a change to ConsoleURL or APIKey
+// is logged as requiring a restart, and changes to any other field are
+// neither applied nor logged.
+func run(cfg *config.Config, logs *crashreport.RingWriter) error {
+	if cfg.CoreDumpOnPanic {
+		defer crashreport.Recover(cfg, logs)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var opts []AgentOption
+	if cfg.PromAddr != "" {
+		exporter := promexport.NewExporter(cfg.PromAddr)
+		opts = append(opts, WithPromExporter(exporter))
+		go func() {
+			if err := exporter.Start(ctx); err != nil {
+				log.Printf("sindri-agent: prometheus exporter: %v", err)
+			}
+		}()
+	}
+
+	agent := NewAgent(cfg, opts...)
+
+	watcher := config.NewWatcher(cfg)
+	watcher.Start()
+	defer watcher.Stop()
+	go func() {
+		for {
+			select {
+			case delta := <-watcher.Deltas():
+				agent.ApplyConfigDelta(delta)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	err := agent.Run(ctx)
+	if errors.Is(err, context.Canceled) {
+		// A SIGTERM/SIGINT canceled ctx; this is a requested shutdown, not
+		// a failure.
+		return nil
+	}
+	return err
+}
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("sindri-agent: load config: %v", err)
+	}
+	cfg.GitCommit = gitCommit
+	cfg.BuildDate = buildDate
+
+	logs := crashreport.NewRingWriter(os.Stderr)
+	log.SetOutput(logs)
+
+	applyRuntimeTuning(cfg)
+
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	actualInterval := cfg.JitteredMetricsInterval(rnd)
+	log.Printf("sindri-agent: starting (metrics_interval=%s metrics_jitter_pct=%d actual_metrics_interval=%s)",
+		cfg.MetricsInterval, cfg.MetricsJitterPct, actualInterval)
+
+	if err := run(cfg, logs); err != nil {
+		fmt.Fprintln(os.Stderr, "sindri-agent:", err)
+		os.Exit(1)
+	}
+}
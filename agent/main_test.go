@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pacphi/sindri/agent/internal/config"
+	"github.com/pacphi/sindri/agent/internal/heartbeat"
+	"github.com/pacphi/sindri/agent/internal/metrics"
+	"github.com/pacphi/sindri/agent/internal/protocol"
+)
+
+// recordingHeartbeatSender records every HeartbeatPayload it receives.
+type recordingHeartbeatSender struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (r *recordingHeartbeatSender) SendHeartbeat(heartbeat.HeartbeatPayload) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.count++
+	return nil
+}
+
+func (r *recordingHeartbeatSender) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
+
+func TestAgentRunStopsOnContextCancel(t *testing.T) {
+	agent := NewAgent(&config.Config{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := agent.Run(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestBuildPayloadIncludesEnvironment(t *testing.T) {
+	t.Setenv("SINDRI_ENVIRONMENT", "staging")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+
+	agent := NewAgent(cfg)
+	if payload := agent.buildPayload(); payload.Environment != "staging" {
+		t.Errorf("Environment = %q, want staging", payload.Environment)
+	}
+}
+
+func TestBuildStateSyncPassesThroughBuildProvenance(t *testing.T) {
+	cfg := &config.Config{GitCommit: "abc123", BuildDate: "2025-01-01"}
+	agent := NewAgent(cfg)
+
+	sync := agent.buildStateSync("v1.2.3", "host-1")
+	if sync.GitCommit != "abc123" || sync.BuildDate != "2025-01-01" {
+		t.Errorf("sync = %+v, want GitCommit=abc123 BuildDate=2025-01-01", sync)
+	}
+	if sync.AgentVersion != "v1.2.3" || sync.Hostname != "host-1" {
+		t.Errorf("sync = %+v, want AgentVersion=v1.2.3 Hostname=host-1", sync)
+	}
+}
+
+func TestAgentPausesHeartbeatsWhileSuspended(t *testing.T) {
+	sender := &recordingHeartbeatSender{}
+	cfg := &config.Config{MetricsInterval: 20 * time.Millisecond}
+	agent := NewAgent(cfg, WithHeartbeatSender(sender))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go agent.Run(ctx)
+
+	agent.Suspend("test")
+	time.Sleep(200 * time.Millisecond)
+	if got := sender.Count(); got != 0 {
+		t.Fatalf("heartbeats sent while suspended = %d, want 0", got)
+	}
+
+	agent.Resume()
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for sender.Count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := sender.Count(); got == 0 {
+		t.Fatalf("heartbeats sent after resume = %d, want > 0", got)
+	}
+}
+
+// brokenMetricsCollector always fails, for exercising error-reporting.
+type brokenMetricsCollector struct{}
+
+func (brokenMetricsCollector) Collect() (*metrics.MetricsPayload, error) {
+	return nil, errors.New("collector unavailable")
+}
+
+// recordingErrorSender records every MsgReportError it receives.
+type recordingErrorSender struct {
+	mu   sync.Mutex
+	msgs []protocol.MsgReportError
+}
+
+func (r *recordingErrorSender) SendError(msg protocol.MsgReportError) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.msgs = append(r.msgs, msg)
+	return nil
+}
+
+func (r *recordingErrorSender) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.msgs)
+}
+
+func TestAgentReportsExactlyOneMetricsErrorPerDedupeWindow(t *testing.T) {
+	sender := &recordingErrorSender{}
+	cfg := &config.Config{MetricsInterval: 10 * time.Millisecond}
+	agent := NewAgent(cfg, WithMetricsCollector(brokenMetricsCollector{}), WithErrorSender(sender))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	agent.Run(ctx)
+
+	if got := sender.Count(); got != 1 {
+		t.Fatalf("error reports sent = %d, want 1 (dedupe window should suppress the rest)", got)
+	}
+	if sender.msgs[0].Code != protocol.ErrCodeMetricsCollectFailed {
+		t.Errorf("Code = %q, want %q", sender.msgs[0].Code, protocol.ErrCodeMetricsCollectFailed)
+	}
+}
+
+// recordingMetricsBatchSender records every MsgMetricsBatch it receives.
+type recordingMetricsBatchSender struct {
+	mu      sync.Mutex
+	batches []protocol.MsgMetricsBatch
+}
+
+func (r *recordingMetricsBatchSender) SendMetricsBatch(msg protocol.MsgMetricsBatch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, msg)
+	return nil
+}
+
+func (r *recordingMetricsBatchSender) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.batches)
+}
+
+func TestAgentBatchesMetricsAtConfiguredSize(t *testing.T) {
+	sender := &recordingMetricsBatchSender{}
+	cfg := &config.Config{MetricsInterval: 10 * time.Millisecond, MetricsPushBatchSize: 3}
+	agent := NewAgent(cfg, WithMetricsBatchSender(sender))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	agent.Run(ctx)
+
+	if got := sender.Count(); got > 2 {
+		t.Fatalf("batches sent = %d, want at most 2 for 5 ticks of batch size 3", got)
+	}
+	for _, batch := range sender.batches {
+		if len(batch.Payloads) != 3 {
+			t.Errorf("batch has %d payloads, want 3", len(batch.Payloads))
+		}
+	}
+}
+
+// recordingDiagnosticsSender records every MsgDiagnostics it receives.
+type recordingDiagnosticsSender struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (r *recordingDiagnosticsSender) SendDiagnostics(protocol.MsgDiagnostics) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.count++
+	return nil
+}
+
+func (r *recordingDiagnosticsSender) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
+
+func TestAgentSendsDiagnosticsOnGCTick(t *testing.T) {
+	sender := &recordingDiagnosticsSender{}
+	cfg := &config.Config{GCInterval: 10 * time.Millisecond}
+	agent := NewAgent(cfg, WithDiagnosticsSender(sender))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+	agent.Run(ctx)
+
+	if got := sender.Count(); got < 2 {
+		t.Errorf("diagnostics sent = %d, want at least 2 for 55ms at a 10ms GC interval", got)
+	}
+}
+
+func TestApplyRuntimeTuningSetsGOGC(t *testing.T) {
+	original := debug.SetGCPercent(100)
+	defer debug.SetGCPercent(original)
+
+	gogc := 50
+	applyRuntimeTuning(&config.Config{GOGC: &gogc})
+
+	got := debug.SetGCPercent(gogc)
+	if got != gogc {
+		t.Errorf("GOGC in effect = %d, want %d", got, gogc)
+	}
+}
+
+func TestBuildStateSyncEmptyOnNonLdflagsBuild(t *testing.T) {
+	agent := NewAgent(&config.Config{})
+
+	sync := agent.buildStateSync("v1.2.3", "host-1")
+	if sync.GitCommit != "" || sync.BuildDate != "" {
+		t.Errorf("sync = %+v, want empty GitCommit/BuildDate", sync)
+	}
+}